@@ -0,0 +1,109 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=admissionpolicies,verbs=get;list;watch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=aso-ap
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode"
+// +kubebuilder:storageversion
+// AdmissionPolicy is a set of constraints written in CEL which resources must satisfy to be created or have their spec
+// changed. Constraints are checked by the operator's webhooks against the resource as submitted.
+type AdmissionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AdmissionPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:validation:Enum={"Deny","Audit"}
+type AdmissionPolicyMode string
+
+const (
+	// AdmissionPolicyModeDeny rejects resources which violate the policy.
+	AdmissionPolicyModeDeny = AdmissionPolicyMode("Deny")
+
+	// AdmissionPolicyModeAudit admits resources which violate the policy, returning a warning to the user.
+	AdmissionPolicyModeAudit = AdmissionPolicyMode("Audit")
+)
+
+// +kubebuilder:validation:Enum={"Fail","Ignore"}
+type AdmissionPolicyFailurePolicy string
+
+const (
+	// AdmissionPolicyFailurePolicyFail treats an expression which can't be evaluated as a violation.
+	AdmissionPolicyFailurePolicyFail = AdmissionPolicyFailurePolicy("Fail")
+
+	// AdmissionPolicyFailurePolicyIgnore skips expressions which can't be evaluated.
+	AdmissionPolicyFailurePolicyIgnore = AdmissionPolicyFailurePolicy("Ignore")
+)
+
+// AdmissionPolicySpec defines the constraints of an AdmissionPolicy.
+type AdmissionPolicySpec struct {
+	// Mode determines what happens when a resource violates the policy. Deny rejects the resource, while Audit admits
+	// it with a warning. Defaults to Deny.
+	Mode AdmissionPolicyMode `json:"mode,omitempty"`
+
+	// FailurePolicy determines what happens when an expression can't be evaluated for a resource, for example because
+	// it refers to a property the resource doesn't have. Fail treats this as a violation, while Ignore skips the
+	// expression. Defaults to Fail.
+	FailurePolicy AdmissionPolicyFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// Match restricts the resources the policy applies to. If omitted, the policy applies to all resources.
+	Match ResourceMatch `json:"match,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	// Validations are the constraints resources must satisfy.
+	Validations []AdmissionValidation `json:"validations"`
+}
+
+// AdmissionValidation is a single constraint of an AdmissionPolicy.
+type AdmissionValidation struct {
+	// +kubebuilder:validation:Required
+	// Expression is a CEL expression which must return true for the resource to be admitted. The expression can use
+	// self, the resource being admitted.
+	// For example: `self.spec.location in ["eastus", "westus"]`
+	Expression string `json:"expression"`
+
+	// +kubebuilder:validation:Required
+	// Message is returned to the user when the expression returns false.
+	Message string `json:"message"`
+}
+
+// EffectiveMode returns the mode of the policy, applying the default if unset.
+func (spec *AdmissionPolicySpec) EffectiveMode() AdmissionPolicyMode {
+	if spec.Mode == "" {
+		return AdmissionPolicyModeDeny
+	}
+
+	return spec.Mode
+}
+
+// EffectiveFailurePolicy returns the failure policy of the policy, applying the default if unset.
+func (spec *AdmissionPolicySpec) EffectiveFailurePolicy() AdmissionPolicyFailurePolicy {
+	if spec.FailurePolicy == "" {
+		return AdmissionPolicyFailurePolicyFail
+	}
+
+	return spec.FailurePolicy
+}
+
+// +kubebuilder:object:root=true
+// AdmissionPolicyList contains a list of AdmissionPolicy
+type AdmissionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AdmissionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AdmissionPolicy{}, &AdmissionPolicyList{})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/configmaps"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/secrets"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=armactions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources={armactions/status,armactions/finalizers},verbs=get;update;patch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last Run",type="date",JSONPath=".status.lastRunTime"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].severity"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message"
+// +kubebuilder:storageversion
+// ARMAction invokes an ARM action (a POST, such as regenerateKey, restart or failover) on a resource managed by the
+// operator. The action is run once for each generation of the ARMAction or, if a schedule is given, at each time in
+// the schedule. Each run invokes the action at most once. The result of the most recent run can be exported to config
+// maps and secrets, and is recorded in status if requested.
+type ARMAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ARMActionSpec   `json:"spec,omitempty"`
+	Status ARMActionStatus `json:"status,omitempty"`
+}
+
+var _ conditions.Conditioner = &ARMAction{}
+
+// GetConditions returns the conditions of the resource
+func (action *ARMAction) GetConditions() conditions.Conditions {
+	return action.Status.Conditions
+}
+
+// SetConditions sets the conditions on the resource status
+func (action *ARMAction) SetConditions(conditions conditions.Conditions) {
+	action.Status.Conditions = conditions
+}
+
+var _ conversion.Hub = &ARMAction{}
+
+// Hub marks that this ARMAction is the hub type for conversion
+func (action *ARMAction) Hub() {}
+
+var _ configmaps.Exporter = &ARMAction{}
+
+// ConfigMapDestinationExpressions returns the Spec.OperatorSpec.ConfigMapExpressions property
+func (action *ARMAction) ConfigMapDestinationExpressions() []*core.DestinationExpression {
+	if action.Spec.OperatorSpec == nil {
+		return nil
+	}
+	return action.Spec.OperatorSpec.ConfigMapExpressions
+}
+
+var _ secrets.Exporter = &ARMAction{}
+
+// SecretDestinationExpressions returns the Spec.OperatorSpec.SecretExpressions property
+func (action *ARMAction) SecretDestinationExpressions() []*core.DestinationExpression {
+	if action.Spec.OperatorSpec == nil {
+		return nil
+	}
+	return action.Spec.OperatorSpec.SecretExpressions
+}
+
+// RecordsResult returns true if the result of the most recent run is to be recorded in status
+func (action *ARMAction) RecordsResult() bool {
+	return action.Spec.OperatorSpec != nil && action.Spec.OperatorSpec.RecordResult
+}
+
+var _ genruntime.ARMOwned = &ARMAction{}
+
+// Owner returns the ResourceReference of the resource the action is invoked on
+func (action *ARMAction) Owner() *genruntime.ResourceReference {
+	if action.Spec.Owner == nil {
+		return nil
+	}
+	return action.Spec.Owner.AsResourceReference()
+}
+
+// ARMActionSpec defines the action invoked by an ARMAction, and when.
+type ARMActionSpec struct {
+	// +kubebuilder:validation:Required
+	// Owner is the resource the action is invoked on, given by group, kind and name. The action is invoked with the
+	// credential used to manage the owner.
+	Owner *genruntime.ArbitraryOwnerReference `json:"owner,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[a-zA-Z][a-zA-Z0-9]*(/[a-zA-Z][a-zA-Z0-9]*)*$"
+	// Action is the name of the action, for example regenerateKey or restart. It's appended to the ARM ID of the owner
+	// to give the URL of the request.
+	Action string `json:"action"`
+
+	// +kubebuilder:validation:Pattern="^[0-9]{4}-[0-9]{2}-[0-9]{2}(-[a-zA-Z]+)?$"
+	// APIVersion is the ARM API version used to invoke the action, for example 2024-05-01. It defaults to the API
+	// version of the owner.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Body is the payload sent to Azure with the action, using the property names of the Azure REST API. No payload is
+	// sent if omitted.
+	Body map[string]apiextensionsv1.JSON `json:"body,omitempty"`
+
+	// Schedule, if set, runs the action at each time in the schedule, rather than once for each generation. It's either
+	// a five field cron expression (e.g. "0 22 * * *") or an RRULE (e.g. "FREQ=WEEKLY;BYDAY=SA;BYHOUR=22"), evaluated
+	// in UTC unless prefixed with a time zone (e.g. "TZ=Europe/London 0 22 * * *").
+	Schedule string `json:"schedule,omitempty"`
+
+	// OperatorSpec is the specification for configuring operator behavior. This field is interpreted by the operator and
+	// not passed directly to Azure.
+	OperatorSpec *ARMActionOperatorSpec `json:"operatorSpec,omitempty"`
+}
+
+// ARMActionOperatorSpec is the specification for configuring operator behavior for an ARMAction.
+type ARMActionOperatorSpec struct {
+	// ConfigMapExpressions configures where to place operator written dynamic ConfigMaps (created with CEL
+	// expressions). They're written each time the action completes.
+	ConfigMapExpressions []*core.DestinationExpression `json:"configMapExpressions,omitempty"`
+
+	// SecretExpressions configures where to place operator written dynamic secrets (created with CEL expressions).
+	// They're written each time the action completes.
+	SecretExpressions []*core.DestinationExpression `json:"secretExpressions,omitempty"`
+
+	// RecordResult, if true, records the result of the most recent run in status. Results often contain secrets (for
+	// example, regenerated keys), so they aren't recorded by default; use secretExpressions to export them instead.
+	RecordResult bool `json:"recordResult,omitempty"`
+}
+
+// ARMActionStatus reports the runs of an ARMAction.
+type ARMActionStatus struct {
+	// Conditions describe the state of the action. The Ready condition reports the outcome of the most recent run.
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
+
+	// LastRunGeneration is the generation of the ARMAction most recently run to completion.
+	LastRunGeneration int64 `json:"lastRunGeneration,omitempty"`
+
+	// LastRunTime is when the action most recently ran to completion.
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// NextRunTime is when the action is next due to run, if it has a schedule.
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+
+	// Result is the response returned by Azure when the action most recently ran to completion. Responses which
+	// aren't JSON objects are recorded under the key "value". Only recorded if the operatorSpec has recordResult set,
+	// or until the result has been exported.
+	Result map[string]apiextensionsv1.JSON `json:"result,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ARMActionList contains a list of ARMAction
+type ARMActionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ARMAction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ARMAction{}, &ARMActionList{})
+}
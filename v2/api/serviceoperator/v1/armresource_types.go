@@ -0,0 +1,453 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	"github.com/rotisserie/eris"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/configmaps"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/secrets"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=armresources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources={armresources/status,armresources/finalizers},verbs=get;update;patch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="API Version",type="string",JSONPath=".spec.apiVersion"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].severity"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message"
+// +kubebuilder:storageversion
+// ARMResource is an Azure resource of any type and API version, for use where the operator doesn't yet support the
+// resource type (or API version) needed. The body is sent to Azure as is, so isn't validated until it reaches Azure.
+// It's otherwise reconciled in the same way as other resources: it's created, updated and deleted in Azure along
+// with the Kubernetes resource, and can export values from the resource returned by Azure to config maps and secrets.
+type ARMResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ARMResourceSpec   `json:"spec,omitempty"`
+	Status ARMResourceStatus `json:"status,omitempty"`
+}
+
+var _ conditions.Conditioner = &ARMResource{}
+
+// GetConditions returns the conditions of the resource
+func (resource *ARMResource) GetConditions() conditions.Conditions {
+	return resource.Status.Conditions
+}
+
+// SetConditions sets the conditions on the resource status
+func (resource *ARMResource) SetConditions(conditions conditions.Conditions) {
+	resource.Status.Conditions = conditions
+}
+
+var _ conversion.Hub = &ARMResource{}
+
+// Hub marks that this ARMResource is the hub type for conversion
+func (resource *ARMResource) Hub() {}
+
+var _ configmaps.Exporter = &ARMResource{}
+
+// ConfigMapDestinationExpressions returns the Spec.OperatorSpec.ConfigMapExpressions property
+func (resource *ARMResource) ConfigMapDestinationExpressions() []*core.DestinationExpression {
+	if resource.Spec.OperatorSpec == nil {
+		return nil
+	}
+	return resource.Spec.OperatorSpec.ConfigMapExpressions
+}
+
+var _ secrets.Exporter = &ARMResource{}
+
+// SecretDestinationExpressions returns the Spec.OperatorSpec.SecretExpressions property
+func (resource *ARMResource) SecretDestinationExpressions() []*core.DestinationExpression {
+	if resource.Spec.OperatorSpec == nil {
+		return nil
+	}
+	return resource.Spec.OperatorSpec.SecretExpressions
+}
+
+var _ genruntime.KubernetesResource = &ARMResource{}
+
+// AzureName returns the Azure name of the resource, which defaults to the name of the Kubernetes resource
+func (resource *ARMResource) AzureName() string {
+	if resource.Spec.AzureName == "" {
+		return resource.Name
+	}
+	return resource.Spec.AzureName
+}
+
+// GetAPIVersion returns the ARM API version of the resource, as given by Spec.APIVersion
+func (resource *ARMResource) GetAPIVersion() string {
+	return resource.Spec.APIVersion
+}
+
+// GetResourceScope returns the scope of the resource. Resources without an owner are always Tenant scoped; otherwise
+// this is given by Spec.Scope, defaulting to ResourceGroup.
+func (resource *ARMResource) GetResourceScope() genruntime.ResourceScope {
+	if resource.Spec.Owner == nil {
+		return genruntime.ResourceScopeTenant
+	}
+
+	switch resource.Spec.Scope {
+	case ARMResourceScopeExtension:
+		return genruntime.ResourceScopeExtension
+	case ARMResourceScopeTenant:
+		return genruntime.ResourceScopeTenant
+	default:
+		return genruntime.ResourceScopeResourceGroup
+	}
+}
+
+// GetSpec returns the specification of this resource
+func (resource *ARMResource) GetSpec() genruntime.ConvertibleSpec {
+	return &resource.Spec
+}
+
+// GetStatus returns the status of this resource
+func (resource *ARMResource) GetStatus() genruntime.ConvertibleStatus {
+	return &resource.Status
+}
+
+// GetSupportedOperations returns the operations supported by the resource
+func (resource *ARMResource) GetSupportedOperations() []genruntime.ResourceOperation {
+	return []genruntime.ResourceOperation{
+		genruntime.ResourceOperationDelete,
+		genruntime.ResourceOperationGet,
+		genruntime.ResourceOperationPut,
+	}
+}
+
+// GetType returns the ARM Type of the resource, as given by Spec.Type
+func (resource *ARMResource) GetType() string {
+	return resource.Spec.Type
+}
+
+// NewEmptyStatus returns a new empty (blank) status
+func (resource *ARMResource) NewEmptyStatus() genruntime.ConvertibleStatus {
+	return &ARMResourceStatus{}
+}
+
+// Owner returns the ResourceReference of the owner, or nil if the resource has no owner
+func (resource *ARMResource) Owner() *genruntime.ResourceReference {
+	if resource.Spec.Owner == nil {
+		return nil
+	}
+	return resource.Spec.Owner.AsResourceReference()
+}
+
+// SetStatus sets the status of this resource
+func (resource *ARMResource) SetStatus(status genruntime.ConvertibleStatus) error {
+	st, ok := status.(*ARMResourceStatus)
+	if !ok {
+		return eris.Errorf("expected status of type ARMResourceStatus but received %T instead", status)
+	}
+
+	resource.Status = *st
+	return nil
+}
+
+// OriginalGVK returns a GroupValueKind for the original API version used to create the resource
+func (resource *ARMResource) OriginalGVK() *schema.GroupVersionKind {
+	return &schema.GroupVersionKind{
+		Group:   GroupVersion.Group,
+		Version: GroupVersion.Version,
+		Kind:    "ARMResource",
+	}
+}
+
+// +kubebuilder:validation:Enum=Extension;ResourceGroup;Tenant
+// ARMResourceScope is the scope of an ARMResource.
+type ARMResourceScope string
+
+const (
+	// ARMResourceScopeExtension is used for extension resources, such as locks, which can be created on any resource.
+	ARMResourceScopeExtension = ARMResourceScope("Extension")
+	// ARMResourceScopeResourceGroup is used for resources created in a resource group, or as children of other
+	// resources.
+	ARMResourceScopeResourceGroup = ARMResourceScope("ResourceGroup")
+	// ARMResourceScopeTenant is used for resources created at the tenant scope.
+	ARMResourceScopeTenant = ARMResourceScope("Tenant")
+)
+
+// ARMResourceSpec defines the desired state of an ARMResource.
+type ARMResourceSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[^/]+(/[^/]+)+$"
+	// Type is the ARM type of the resource, for example Microsoft.Network/virtualNetworks/subnets. Child resources
+	// must be owned by a resource of the parent type.
+	Type string `json:"type"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[0-9]{4}-[0-9]{2}-[0-9]{2}(-[a-zA-Z]+)?$"
+	// APIVersion is the ARM API version used for the resource, for example 2024-05-01.
+	APIVersion string `json:"apiVersion"`
+
+	// AzureName is the name of the resource in Azure. Defaults to the name of the Kubernetes resource.
+	AzureName string `json:"azureName,omitempty"`
+
+	// Owner is the resource this resource belongs to, such as its resource group or parent resource. Tenant scoped
+	// resources don't have an owner.
+	Owner *genruntime.ArbitraryOwnerReference `json:"owner,omitempty"`
+
+	// Scope is the scope of the resource. Resources without an owner are always Tenant scoped; otherwise this defaults
+	// to ResourceGroup. Extension resources, which must have an owner, must set this to Extension.
+	Scope ARMResourceScope `json:"scope,omitempty"`
+
+	// Body is the payload sent to Azure, for example {"location": "westus", "properties": {...}}. The name of the
+	// resource is added by the operator.
+	Body map[string]apiextensionsv1.JSON `json:"body,omitempty"`
+
+	// OperatorSpec is the specification for configuring operator behavior. This field is interpreted by the operator and
+	// not passed directly to Azure.
+	OperatorSpec *ARMResourceOperatorSpec `json:"operatorSpec,omitempty"`
+}
+
+var _ genruntime.ConvertibleSpec = &ARMResourceSpec{}
+
+// ConvertSpecFrom populates our ARMResourceSpec from the provided source
+func (spec *ARMResourceSpec) ConvertSpecFrom(source genruntime.ConvertibleSpec) error {
+	src, ok := source.(*ARMResourceSpec)
+	if !ok {
+		return eris.Errorf("expected spec of type ARMResourceSpec but received %T instead", source)
+	}
+
+	src.DeepCopyInto(spec)
+	return nil
+}
+
+// ConvertSpecTo populates the provided destination from our ARMResourceSpec
+func (spec *ARMResourceSpec) ConvertSpecTo(destination genruntime.ConvertibleSpec) error {
+	dst, ok := destination.(*ARMResourceSpec)
+	if !ok {
+		return eris.Errorf("expected spec of type ARMResourceSpec but received %T instead", destination)
+	}
+
+	spec.DeepCopyInto(dst)
+	return nil
+}
+
+var _ genruntime.ARMTransformer = &ARMResourceSpec{}
+
+// ConvertToARM converts from a Kubernetes CRD object to an ARM object
+func (spec *ARMResourceSpec) ConvertToARM(resolved genruntime.ConvertToARMResolvedDetails) (interface{}, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	result := &ARMResource_Spec_ARM{
+		APIVersion: spec.APIVersion,
+		Type:       spec.Type,
+		Name:       resolved.Name,
+		Body:       make(map[string]apiextensionsv1.JSON, len(spec.Body)),
+	}
+
+	for key, value := range spec.Body {
+		result.Body[key] = *value.DeepCopy()
+	}
+
+	return result, nil
+}
+
+// NewEmptyARMValue returns an empty ARM value suitable for deserializing into
+func (spec *ARMResourceSpec) NewEmptyARMValue() genruntime.ARMResourceStatus {
+	return &ARMResource_Spec_ARM{
+		APIVersion: spec.APIVersion,
+		Type:       spec.Type,
+	}
+}
+
+// PopulateFromARM populates a Kubernetes CRD object from an Azure ARM object
+func (spec *ARMResourceSpec) PopulateFromARM(owner genruntime.ArbitraryOwnerReference, armInput interface{}) error {
+	typedInput, ok := armInput.(ARMResource_Spec_ARM)
+	if !ok {
+		return eris.Errorf("unexpected type supplied for PopulateFromARM() function. Expected ARMResource_Spec_ARM, got %T", armInput)
+	}
+
+	spec.AzureName = genruntime.ExtractKubernetesResourceNameFromARMName(typedInput.Name)
+	spec.APIVersion = typedInput.APIVersion
+	spec.Type = typedInput.Type
+	if owner.Name != "" {
+		spec.Owner = &owner
+	}
+	spec.Body = typedInput.Body
+
+	return nil
+}
+
+// ARMResourceOperatorSpec is the specification for configuring operator behavior for an ARMResource.
+type ARMResourceOperatorSpec struct {
+	// ConfigMapExpressions are expressions whose results are written to config maps. The resource returned by Azure is
+	// available as self.status.body.
+	ConfigMapExpressions []*core.DestinationExpression `json:"configMapExpressions,omitempty"`
+
+	// SecretExpressions are expressions whose results are written to secrets. The resource returned by Azure is
+	// available as self.status.body.
+	SecretExpressions []*core.DestinationExpression `json:"secretExpressions,omitempty"`
+}
+
+// ARMResourceStatus defines the observed state of an ARMResource.
+type ARMResourceStatus struct {
+	// Conditions describe the observed state of the resource.
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
+
+	// Body is the resource as last returned by Azure.
+	Body map[string]apiextensionsv1.JSON `json:"body,omitempty"`
+}
+
+var _ genruntime.ConvertibleStatus = &ARMResourceStatus{}
+
+// ConvertStatusFrom populates our ARMResourceStatus from the provided source
+func (status *ARMResourceStatus) ConvertStatusFrom(source genruntime.ConvertibleStatus) error {
+	src, ok := source.(*ARMResourceStatus)
+	if !ok {
+		return eris.Errorf("expected status of type ARMResourceStatus but received %T instead", source)
+	}
+
+	src.DeepCopyInto(status)
+	return nil
+}
+
+// ConvertStatusTo populates the provided destination from our ARMResourceStatus
+func (status *ARMResourceStatus) ConvertStatusTo(destination genruntime.ConvertibleStatus) error {
+	dst, ok := destination.(*ARMResourceStatus)
+	if !ok {
+		return eris.Errorf("expected status of type ARMResourceStatus but received %T instead", destination)
+	}
+
+	status.DeepCopyInto(dst)
+	return nil
+}
+
+var _ genruntime.FromARMConverter = &ARMResourceStatus{}
+
+// NewEmptyARMValue returns an empty ARM value suitable for deserializing into
+func (status *ARMResourceStatus) NewEmptyARMValue() genruntime.ARMResourceStatus {
+	return &ARMResource_STATUS_ARM{}
+}
+
+// PopulateFromARM populates a Kubernetes CRD object from an Azure ARM object
+func (status *ARMResourceStatus) PopulateFromARM(owner genruntime.ArbitraryOwnerReference, armInput interface{}) error {
+	typedInput, ok := armInput.(ARMResource_STATUS_ARM)
+	if !ok {
+		return eris.Errorf("unexpected type supplied for PopulateFromARM() function. Expected ARMResource_STATUS_ARM, got %T", armInput)
+	}
+
+	status.Body = typedInput
+	return nil
+}
+
+// +kubebuilder:object:generate=false
+// ARMResource_Spec_ARM is the payload sent to Azure for an ARMResource: the body from the spec, with the name of the
+// resource added. The type and API version of the resource aren't part of the payload.
+type ARMResource_Spec_ARM struct {
+	APIVersion string
+	Type       string
+	Name       string
+	Body       map[string]apiextensionsv1.JSON
+}
+
+var _ genruntime.ARMResourceSpec = &ARMResource_Spec_ARM{}
+
+// GetAPIVersion returns the ARM API version of the resource
+func (payload *ARMResource_Spec_ARM) GetAPIVersion() string {
+	return payload.APIVersion
+}
+
+// GetName returns the Name of the resource
+func (payload *ARMResource_Spec_ARM) GetName() string {
+	return payload.Name
+}
+
+// GetType returns the ARM Type of the resource
+func (payload *ARMResource_Spec_ARM) GetType() string {
+	return payload.Type
+}
+
+// WithPayload returns a copy of this spec with its body and name replaced by those in the provided JSON payload.
+// The ARM type and API version aren't part of the payload, so can't be recovered from it alone.
+func (payload *ARMResource_Spec_ARM) WithPayload(data []byte) (genruntime.ARMResourceSpec, error) {
+	result := &ARMResource_Spec_ARM{
+		APIVersion: payload.APIVersion,
+		Type:       payload.Type,
+	}
+
+	err := json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarshalJSON serializes the body of the payload, including the name of the resource
+func (payload ARMResource_Spec_ARM) MarshalJSON() ([]byte, error) {
+	body := make(map[string]apiextensionsv1.JSON, len(payload.Body)+1)
+	for key, value := range payload.Body {
+		body[key] = value
+	}
+
+	if payload.Name != "" {
+		name, err := json.Marshal(payload.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		body["name"] = apiextensionsv1.JSON{Raw: name}
+	}
+
+	return json.Marshal(body)
+}
+
+// UnmarshalJSON deserializes the body of the payload, extracting the name of the resource
+func (payload *ARMResource_Spec_ARM) UnmarshalJSON(data []byte) error {
+	var body map[string]apiextensionsv1.JSON
+	err := json.Unmarshal(data, &body)
+	if err != nil {
+		return err
+	}
+
+	payload.Name = ""
+	if name, ok := body["name"]; ok {
+		err = json.Unmarshal(name.Raw, &payload.Name)
+		if err != nil {
+			return eris.Wrap(err, "deserializing name")
+		}
+
+		delete(body, "name")
+	}
+
+	payload.Body = body
+	return nil
+}
+
+// +kubebuilder:object:generate=false
+// ARMResource_STATUS_ARM is the resource returned by Azure for an ARMResource.
+type ARMResource_STATUS_ARM map[string]apiextensionsv1.JSON
+
+// +kubebuilder:object:root=true
+// ARMResourceList contains a list of ARMResource
+type ARMResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ARMResource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ARMResource{}, &ARMResourceList{})
+}
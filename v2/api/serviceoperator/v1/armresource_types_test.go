@@ -0,0 +1,289 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+func rawJSON(value string) apiextensionsv1.JSON {
+	return apiextensionsv1.JSON{Raw: []byte(value)}
+}
+
+func Test_ARMResource_Spec_ARM_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		payload  ARMResource_Spec_ARM
+		expected string
+	}{
+		{
+			name: "Body and name",
+			payload: ARMResource_Spec_ARM{
+				APIVersion: "2024-05-01",
+				Type:       "Microsoft.Network/virtualNetworks",
+				Name:       "vnet",
+				Body: map[string]apiextensionsv1.JSON{
+					"location":   rawJSON(`"westus"`),
+					"properties": rawJSON(`{"addressSpace":{"addressPrefixes":["10.0.0.0/16"]}}`),
+				},
+			},
+			expected: `{"location":"westus","name":"vnet","properties":{"addressSpace":{"addressPrefixes":["10.0.0.0/16"]}}}`,
+		},
+		{
+			name: "Name replaces any name in the body",
+			payload: ARMResource_Spec_ARM{
+				Name: "vnet",
+				Body: map[string]apiextensionsv1.JSON{
+					"name": rawJSON(`"other"`),
+				},
+			},
+			expected: `{"name":"vnet"}`,
+		},
+		{
+			name: "No name",
+			payload: ARMResource_Spec_ARM{
+				Body: map[string]apiextensionsv1.JSON{
+					"location": rawJSON(`"westus"`),
+				},
+			},
+			expected: `{"location":"westus"}`,
+		},
+		{
+			name:     "Empty",
+			payload:  ARMResource_Spec_ARM{},
+			expected: `{}`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			data, err := json.Marshal(c.payload)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(data)).To(MatchJSON(c.expected))
+
+			// The API version and type aren't part of the payload
+			g.Expect(string(data)).ToNot(ContainSubstring("apiVersion"))
+			g.Expect(string(data)).ToNot(ContainSubstring("Microsoft.Network"))
+		})
+	}
+}
+
+func Test_ARMResource_Spec_ARM_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		data         string
+		expectedName string
+		expectedBody map[string]apiextensionsv1.JSON
+		expectedErr  string
+	}{
+		{
+			name:         "Body and name",
+			data:         `{"name":"vnet","location":"westus"}`,
+			expectedName: "vnet",
+			expectedBody: map[string]apiextensionsv1.JSON{
+				"location": rawJSON(`"westus"`),
+			},
+		},
+		{
+			name:         "No name",
+			data:         `{"location":"westus"}`,
+			expectedName: "",
+			expectedBody: map[string]apiextensionsv1.JSON{
+				"location": rawJSON(`"westus"`),
+			},
+		},
+		{
+			name:        "Name isn't a string",
+			data:        `{"name":42}`,
+			expectedErr: "deserializing name",
+		},
+		{
+			name:        "Not an object",
+			data:        `[]`,
+			expectedErr: "cannot unmarshal",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			// Any previous name is replaced
+			payload := ARMResource_Spec_ARM{
+				APIVersion: "2024-05-01",
+				Name:       "previous",
+			}
+
+			err := json.Unmarshal([]byte(c.data), &payload)
+			if c.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.expectedErr)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(payload.Name).To(Equal(c.expectedName))
+			g.Expect(payload.Body).To(Equal(c.expectedBody))
+			g.Expect(payload.APIVersion).To(Equal("2024-05-01"))
+		})
+	}
+}
+
+func Test_ARMResource_Spec_ARM_RoundTrips(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	payload := ARMResource_Spec_ARM{
+		Name: "vnet",
+		Body: map[string]apiextensionsv1.JSON{
+			"location": rawJSON(`"westus"`),
+			"tags":     rawJSON(`{"env":"test"}`),
+		},
+	}
+
+	data, err := json.Marshal(payload)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var result ARMResource_Spec_ARM
+	g.Expect(json.Unmarshal(data, &result)).To(Succeed())
+	g.Expect(result.Name).To(Equal(payload.Name))
+	g.Expect(result.Body).To(HaveLen(2))
+	g.Expect(result.Body["location"].Raw).To(MatchJSON(`"westus"`))
+	g.Expect(result.Body["tags"].Raw).To(MatchJSON(`{"env":"test"}`))
+}
+
+func Test_ARMResource_Spec_ARM_WithPayload_KeepsTypeAndAPIVersion(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	payload := &ARMResource_Spec_ARM{
+		APIVersion: "2024-05-01",
+		Type:       "Microsoft.Network/virtualNetworks",
+		Name:       "vnet",
+	}
+
+	result, err := payload.WithPayload([]byte(`{"name":"other","location":"westus"}`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.GetAPIVersion()).To(Equal("2024-05-01"))
+	g.Expect(result.GetType()).To(Equal("Microsoft.Network/virtualNetworks"))
+	g.Expect(result.GetName()).To(Equal("other"))
+}
+
+func Test_ARMResourceSpec_ConvertToARM(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := &ARMResourceSpec{
+		Type:       "Microsoft.Network/virtualNetworks",
+		APIVersion: "2024-05-01",
+		AzureName:  "ignored",
+		Body: map[string]apiextensionsv1.JSON{
+			"location": rawJSON(`"westus"`),
+		},
+	}
+
+	result, err := spec.ConvertToARM(genruntime.ConvertToARMResolvedDetails{Name: "vnet"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(&ARMResource_Spec_ARM{
+		APIVersion: "2024-05-01",
+		Type:       "Microsoft.Network/virtualNetworks",
+		Name:       "vnet",
+		Body: map[string]apiextensionsv1.JSON{
+			"location": rawJSON(`"westus"`),
+		},
+	}))
+
+	// The body is copied, so changes to the payload don't affect the spec
+	payload, ok := result.(*ARMResource_Spec_ARM)
+	g.Expect(ok).To(BeTrue())
+	payload.Body["location"].Raw[1] = 'X'
+	g.Expect(string(spec.Body["location"].Raw)).To(Equal(`"westus"`))
+}
+
+func Test_ARMResourceSpec_ConvertToARM_GivenNilSpec_ReturnsNil(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var spec *ARMResourceSpec
+	result, err := spec.ConvertToARM(genruntime.ConvertToARMResolvedDetails{Name: "vnet"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(BeNil())
+}
+
+func Test_ARMResourceSpec_PopulateFromARM(t *testing.T) {
+	t.Parallel()
+
+	body := map[string]apiextensionsv1.JSON{
+		"location": rawJSON(`"westus"`),
+	}
+
+	cases := []struct {
+		name          string
+		owner         genruntime.ArbitraryOwnerReference
+		expectedOwner *genruntime.ArbitraryOwnerReference
+	}{
+		{
+			name:          "With owner",
+			owner:         genruntime.ArbitraryOwnerReference{Group: "resources.azure.com", Kind: "ResourceGroup", Name: "rg"},
+			expectedOwner: &genruntime.ArbitraryOwnerReference{Group: "resources.azure.com", Kind: "ResourceGroup", Name: "rg"},
+		},
+		{
+			name:          "Without owner",
+			owner:         genruntime.ArbitraryOwnerReference{},
+			expectedOwner: nil,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			armInput := ARMResource_Spec_ARM{
+				APIVersion: "2024-05-01",
+				Type:       "Microsoft.Network/virtualNetworks/subnets",
+				Name:       "vnet/default",
+				Body:       body,
+			}
+
+			var spec ARMResourceSpec
+			g.Expect(spec.PopulateFromARM(c.owner, armInput)).To(Succeed())
+			g.Expect(spec).To(Equal(ARMResourceSpec{
+				Type:       "Microsoft.Network/virtualNetworks/subnets",
+				APIVersion: "2024-05-01",
+				AzureName:  "default",
+				Owner:      c.expectedOwner,
+				Body:       body,
+			}))
+		})
+	}
+}
+
+func Test_ARMResourceSpec_PopulateFromARM_GivenWrongType_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var spec ARMResourceSpec
+	err := spec.PopulateFromARM(genruntime.ArbitraryOwnerReference{}, &ARMResource_Spec_ARM{})
+	g.Expect(err).To(MatchError(ContainSubstring("Expected ARMResource_Spec_ARM")))
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=azurecredentials,verbs=get;list;watch
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=azurecredentials/status,verbs=get;update;patch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aso-cred
+// +kubebuilder:printcolumn:name="Subscription",type="string",JSONPath=".spec.subscriptionID"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
+// +kubebuilder:storageversion
+// AzureCredential is an identity the operator uses to manage resources in Azure. Resources use it through the
+// serviceoperator.azure.com/azure-credential annotation, set either on the resource or on its namespace.
+// The operator regularly checks that a token can be acquired with the credential, and reports the result in status.
+type AzureCredential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureCredentialSpec   `json:"spec,omitempty"`
+	Status AzureCredentialStatus `json:"status,omitempty"`
+}
+
+var _ conditions.Conditioner = &AzureCredential{}
+
+// GetConditions returns the conditions of the resource
+func (credential *AzureCredential) GetConditions() conditions.Conditions {
+	return credential.Status.Conditions
+}
+
+// SetConditions sets the conditions on the resource status
+func (credential *AzureCredential) SetConditions(conditions conditions.Conditions) {
+	credential.Status.Conditions = conditions
+}
+
+// AzureCredentialSpec defines the identity of an AzureCredential.
+// Workload identity is used unless ClientSecret or ClientCertificate is specified.
+type AzureCredentialSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[0-9a-fA-F-]{36}$"
+	// SubscriptionID is the subscription resources using this credential are created in.
+	SubscriptionID string `json:"subscriptionID"`
+
+	// +kubebuilder:validation:Required
+	// TenantID is the Entra tenant of the identity.
+	TenantID string `json:"tenantID"`
+
+	// +kubebuilder:validation:Required
+	// ClientID is the client ID of the identity.
+	ClientID string `json:"clientID"`
+
+	// AdditionalTenants are other tenants the identity may acquire tokens for.
+	AdditionalTenants []string `json:"additionalTenants,omitempty"`
+
+	// ClientSecret is the secret key holding the client secret of the identity.
+	ClientSecret *SecretKeyReference `json:"clientSecret,omitempty"`
+
+	// ClientCertificate is the secret key holding the client certificate of the identity.
+	ClientCertificate *ClientCertificateReference `json:"clientCertificate,omitempty"`
+
+	// AllowedNamespaces are the namespaces, other than the one it's in, whose resources may use this credential.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// CheckPermissions enables periodic checks that the identity has the access needed by the resources using this
+	// credential, reported by the PermissionsVerified condition.
+	CheckPermissions bool `json:"checkPermissions,omitempty"`
+}
+
+// SecretKeyReference is a reference to a key of a Kubernetes secret in the same namespace as the AzureCredential.
+type SecretKeyReference struct {
+	// +kubebuilder:validation:Required
+	// Name is the name of the secret.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// Key is the key of the secret holding the value.
+	Key string `json:"key"`
+}
+
+// ClientCertificateReference is a reference to the client certificate of an identity.
+type ClientCertificateReference struct {
+	// +kubebuilder:validation:Required
+	// Certificate is the secret key holding the PEM or PKCS#12 encoded certificate and private key.
+	Certificate SecretKeyReference `json:"certificate"`
+
+	// Password is the secret key holding the password of the certificate, if it has one.
+	Password *SecretKeyReference `json:"password,omitempty"`
+}
+
+// AzureCredentialStatus reports the health of an AzureCredential.
+type AzureCredentialStatus struct {
+	// Conditions describe the health of the credential. The Ready condition reports whether a token could be acquired.
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
+
+	// TenantID is the tenant a token was last acquired for.
+	TenantID string `json:"tenantID,omitempty"`
+
+	// SubscriptionID is the subscription resources using the credential are created in.
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+
+	// LastTokenAcquired is when a token was last acquired with the credential.
+	LastTokenAcquired *metav1.Time `json:"lastTokenAcquired,omitempty"`
+
+	// MissingPermissions are the permissions found to be missing by the most recent permissions check.
+	MissingPermissions []MissingPermission `json:"missingPermissions,omitempty"`
+}
+
+// MissingPermission is access a resource needs but the identity of a credential doesn't have.
+type MissingPermission struct {
+	// Resource is the resource needing the access.
+	Resource string `json:"resource"`
+
+	// Scope is the ARM ID at which the access is needed.
+	Scope string `json:"scope"`
+
+	// Actions are the missing actions, such as Microsoft.Network/virtualNetworks/subnets/join/action.
+	Actions []string `json:"actions,omitempty"`
+}
+
+// AllowsNamespace returns true if resources in the specified namespace may use the credential.
+func (credential *AzureCredential) AllowsNamespace(namespace string) bool {
+	if namespace == credential.Namespace {
+		return true
+	}
+
+	for _, allowed := range credential.Spec.AllowedNamespaces {
+		if allowed == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// +kubebuilder:object:root=true
+// AzureCredentialList contains a list of AzureCredential
+type AzureCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureCredential `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureCredential{}, &AzureCredentialList{})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=operationhistories,verbs=get;list;watch;create;update;patch;delete
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=aso-ops
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".resource.kind"
+// +kubebuilder:printcolumn:name="Resource",type="string",JSONPath=".resource.name"
+// +kubebuilder:printcolumn:name="Last Action",type="string",JSONPath=".operations[-1:].action"
+// +kubebuilder:printcolumn:name="Last Status",type="integer",JSONPath=".operations[-1:].httpStatus"
+// +kubebuilder:storageversion
+// OperationHistory records the most recent operations the operator has performed in Azure for a resource.
+// It is created by the operator alongside the resource, and deleted along with it.
+type OperationHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Resource identifies the resource the operations were performed for.
+	Resource OperationHistoryResource `json:"resource,omitempty"`
+
+	// Operations are the most recent operations performed for the resource, oldest first.
+	Operations []Operation `json:"operations,omitempty"`
+}
+
+// OperationHistoryResource identifies the resource an OperationHistory belongs to.
+type OperationHistoryResource struct {
+	// Group is the API group of the resource.
+	Group string `json:"group,omitempty"`
+
+	// Kind is the kind of the resource.
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the resource.
+	Name string `json:"name,omitempty"`
+
+	// ResourceID is the ARM ID of the resource.
+	ResourceID string `json:"resourceID,omitempty"`
+}
+
+// Operation is a single operation performed in Azure for a resource, such as creating, updating or deleting it.
+type Operation struct {
+	// Action is the action taken by the operator, such as BeginCreateOrUpdate or BeginDelete.
+	Action string `json:"action,omitempty"`
+
+	// Verb is the HTTP method of the request sent to ARM.
+	Verb string `json:"verb,omitempty"`
+
+	// StartTime is when the request was sent to ARM.
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is when the operation completed. For long-running operations, this is unset until the operator has
+	// observed the operation complete.
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// HTTPStatus is the status code returned by ARM for the request.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+
+	// CorrelationID is the x-ms-correlation-request-id of the request. Quote this when raising issues with Azure support.
+	CorrelationID string `json:"correlationID,omitempty"`
+
+	// RequestID is the x-ms-request-id of the request.
+	RequestID string `json:"requestID,omitempty"`
+
+	// Generation is the generation of the resource the operation applied.
+	Generation int64 `json:"generation,omitempty"`
+
+	// Error is the classified error, if the operation failed.
+	Error *core.CloudErrorDetails `json:"error,omitempty"`
+}
+
+// InProgress returns true if the operation is a long-running operation that hasn't yet been observed to complete.
+func (op *Operation) InProgress() bool {
+	return op.EndTime == nil
+}
+
+// +kubebuilder:object:root=true
+// OperationHistoryList contains a list of OperationHistory
+type OperationHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperationHistory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperationHistory{}, &OperationHistoryList{})
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=payloadmutationpolicies,verbs=get;list;watch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=aso-pmp
+// +kubebuilder:storageversion
+// PayloadMutationPolicy is a set of rules written in CEL which change the payload sent to Azure for matching
+// resources, applied just before the payload is sent. Policies are applied in order of name, and the rules within a
+// policy in the order they're listed; each rule sees the payload as changed by the rules before it.
+type PayloadMutationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PayloadMutationPolicySpec `json:"spec,omitempty"`
+}
+
+// PayloadMutationPolicySpec defines the rules of a PayloadMutationPolicy.
+type PayloadMutationPolicySpec struct {
+	// +kubebuilder:validation:MinItems=1
+	// Rules are the mutations to apply, in order.
+	Rules []PayloadMutationRule `json:"rules"`
+}
+
+// PayloadMutationRule is a single mutation of the payload sent to Azure.
+type PayloadMutationRule struct {
+	// +kubebuilder:validation:Required
+	// Name identifies the rule in logs and errors.
+	Name string `json:"name"`
+
+	// Match restricts the resources the rule applies to. If omitted, the rule applies to all resources.
+	Match ResourceMatch `json:"match,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Patch is a CEL expression returning a JSON merge patch (RFC 7386) to apply to the payload, as a map. The
+	// expression can use self, the resource being reconciled, and payload, the ARM payload as a map. Return {} to leave
+	// the payload unchanged, and null for a key to remove it.
+	// For example: `{"properties": {"minimumTlsVersion": "TLS1_2"}}`
+	Patch string `json:"patch"`
+}
+
+// +kubebuilder:object:root=true
+// PayloadMutationPolicyList contains a list of PayloadMutationPolicy
+type PayloadMutationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PayloadMutationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PayloadMutationPolicy{}, &PayloadMutationPolicyList{})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=referencegrants,verbs=get;list;watch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=aso-refgrant
+// +kubebuilder:storageversion
+// ReferenceGrant allows resources in other namespaces to refer to resources, secrets and configmaps in the namespace
+// of the ReferenceGrant, either as their owner or as a reference. Without a ReferenceGrant, resources can only refer
+// to others in their own namespace.
+type ReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReferenceGrantSpec `json:"spec,omitempty"`
+}
+
+// ReferenceGrantSpec defines which references a ReferenceGrant allows.
+// A reference is allowed if it comes from any of the From namespaces, and refers to any of the To targets.
+type ReferenceGrantSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	// From are the namespaces allowed to refer to the targets.
+	From []ReferenceGrantFrom `json:"from"`
+
+	// +kubebuilder:validation:MinItems=1
+	// To are the targets which may be referred to.
+	To []ReferenceGrantTo `json:"to"`
+}
+
+// ReferenceGrantFrom identifies a namespace whose resources may refer to the targets of a ReferenceGrant.
+type ReferenceGrantFrom struct {
+	// +kubebuilder:validation:Required
+	// Namespace is the namespace of the resources.
+	Namespace string `json:"namespace"`
+}
+
+// ReferenceGrantTo identifies targets which may be referred to.
+type ReferenceGrantTo struct {
+	// Group is the API group of the target, such as network.azure.com. Use "" for secrets and configmaps.
+	Group string `json:"group"`
+
+	// +kubebuilder:validation:Required
+	// Kind is the kind of the target, such as VirtualNetwork, Secret or ConfigMap.
+	Kind string `json:"kind"`
+
+	// Name is the name of the target. If omitted, all targets of the kind may be referred to.
+	Name string `json:"name,omitempty"`
+}
+
+// Permits returns true if the grant allows resources in fromNamespace to refer to the target with the specified kind
+// and name in the namespace of the grant.
+func (grant *ReferenceGrant) Permits(fromNamespace string, groupKind schema.GroupKind, name string) bool {
+	fromAllowed := false
+	for _, from := range grant.Spec.From {
+		if from.Namespace == fromNamespace {
+			fromAllowed = true
+			break
+		}
+	}
+
+	if !fromAllowed {
+		return false
+	}
+
+	for _, to := range grant.Spec.To {
+		if to.Group == groupKind.Group &&
+			to.Kind == groupKind.Kind &&
+			(to.Name == "" || to.Name == name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// +kubebuilder:object:root=true
+// ReferenceGrantList contains a list of ReferenceGrant
+type ReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReferenceGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReferenceGrant{}, &ReferenceGrantList{})
+}
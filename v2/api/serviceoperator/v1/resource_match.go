@@ -0,0 +1,46 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceMatch selects the resources a policy applies to. Each field that is specified must match.
+type ResourceMatch struct {
+	// Groups are the API groups of matching resources, e.g. storage.azure.com.
+	Groups []string `json:"groups,omitempty"`
+
+	// Kinds are the kinds of matching resources, e.g. StorageAccount.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Namespaces are the namespaces of matching resources.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Matches returns true if resources of the specified kind in the specified namespace are selected.
+func (m *ResourceMatch) Matches(groupKind schema.GroupKind, namespace string) bool {
+	return matchesAny(m.Groups, groupKind.Group, strings.EqualFold) &&
+		matchesAny(m.Kinds, groupKind.Kind, strings.EqualFold) &&
+		matchesAny(m.Namespaces, namespace, func(a, b string) bool { return a == b })
+}
+
+// matchesAny returns true if candidates is empty, or contains value
+func matchesAny(candidates []string, value string, equal func(string, string) bool) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+
+	for _, candidate := range candidates {
+		if equal(candidate, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,419 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/rotisserie/eris"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/configmaps"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/secrets"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=resourcelookups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources={resourcelookups/status,resourcelookups/finalizers},verbs=get;update;patch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].severity"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message"
+// +kubebuilder:storageversion
+// ResourceLookup reads an existing Azure resource of any type, so that values from it can be exported to config maps
+// and secrets. The resource is read from Azure on every sync, and is never created, modified or deleted by the
+// operator.
+type ResourceLookup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResourceLookupSpec   `json:"spec,omitempty"`
+	Status ResourceLookupStatus `json:"status,omitempty"`
+}
+
+var _ conditions.Conditioner = &ResourceLookup{}
+
+// GetConditions returns the conditions of the resource
+func (lookup *ResourceLookup) GetConditions() conditions.Conditions {
+	return lookup.Status.Conditions
+}
+
+// SetConditions sets the conditions on the resource status
+func (lookup *ResourceLookup) SetConditions(conditions conditions.Conditions) {
+	lookup.Status.Conditions = conditions
+}
+
+var _ conversion.Hub = &ResourceLookup{}
+
+// Hub marks that this ResourceLookup is the hub type for conversion
+func (lookup *ResourceLookup) Hub() {}
+
+var _ configmaps.Exporter = &ResourceLookup{}
+
+// ConfigMapDestinationExpressions returns the Spec.OperatorSpec.ConfigMapExpressions property
+func (lookup *ResourceLookup) ConfigMapDestinationExpressions() []*core.DestinationExpression {
+	if lookup.Spec.OperatorSpec == nil {
+		return nil
+	}
+	return lookup.Spec.OperatorSpec.ConfigMapExpressions
+}
+
+var _ secrets.Exporter = &ResourceLookup{}
+
+// SecretDestinationExpressions returns the Spec.OperatorSpec.SecretExpressions property
+func (lookup *ResourceLookup) SecretDestinationExpressions() []*core.DestinationExpression {
+	if lookup.Spec.OperatorSpec == nil {
+		return nil
+	}
+	return lookup.Spec.OperatorSpec.SecretExpressions
+}
+
+var _ genruntime.ReadOnlyResource = &ResourceLookup{}
+
+// IsReadOnly returns true, as a ResourceLookup never changes the resource it reads
+func (lookup *ResourceLookup) IsReadOnly() bool {
+	return true
+}
+
+var _ genruntime.KubernetesResource = &ResourceLookup{}
+
+// AzureName returns the Azure name of the resource, from Spec.ARMID if set, otherwise from Spec.AzureName. If Spec.ARMID
+// is invalid, the name is empty.
+func (lookup *ResourceLookup) AzureName() string {
+	if lookup.Spec.ARMID != "" {
+		id, err := lookup.Spec.parseARMID()
+		if err != nil {
+			return ""
+		}
+		return id.Name
+	}
+	return lookup.Spec.AzureName
+}
+
+// GetAPIVersion returns the ARM API version of the resource, as given by Spec.APIVersion
+func (lookup *ResourceLookup) GetAPIVersion() string {
+	return lookup.Spec.APIVersion
+}
+
+// GetResourceScope returns the scope of the resource. When the resource is identified by Spec.ARMID, this depends on
+// its parent:
+//   - Resources in a resource group, and children of other resources, are ResourceGroup scoped.
+//   - Resources at the root of the tenant are Tenant scoped.
+//   - Other resources, such as those created directly in a subscription or extension resources created on another
+//     resource, are Extension scoped.
+//
+// Otherwise, resources without an owner are Tenant scoped, and all others are ResourceGroup scoped.
+func (lookup *ResourceLookup) GetResourceScope() genruntime.ResourceScope {
+	if lookup.Spec.ARMID != "" {
+		id, err := lookup.Spec.parseARMID()
+		if err != nil {
+			// The error is reported when the owner is resolved
+			return genruntime.ResourceScopeResourceGroup
+		}
+
+		return armIDScope(id)
+	}
+
+	if lookup.Owner() == nil {
+		return genruntime.ResourceScopeTenant
+	}
+	return genruntime.ResourceScopeResourceGroup
+}
+
+// GetSpec returns the specification of this resource
+func (lookup *ResourceLookup) GetSpec() genruntime.ConvertibleSpec {
+	return &lookup.Spec
+}
+
+// GetStatus returns the status of this resource
+func (lookup *ResourceLookup) GetStatus() genruntime.ConvertibleStatus {
+	return &lookup.Status
+}
+
+// GetSupportedOperations returns the operations supported by the resource
+func (lookup *ResourceLookup) GetSupportedOperations() []genruntime.ResourceOperation {
+	return []genruntime.ResourceOperation{
+		genruntime.ResourceOperationGet,
+	}
+}
+
+// GetType returns the ARM Type of the resource, from Spec.ARMID if set, otherwise from Spec.Type. If Spec.ARMID is
+// invalid, the type is empty.
+func (lookup *ResourceLookup) GetType() string {
+	if lookup.Spec.ARMID != "" {
+		id, err := lookup.Spec.parseARMID()
+		if err != nil {
+			return ""
+		}
+		return id.ResourceType.String()
+	}
+	return lookup.Spec.Type
+}
+
+// NewEmptyStatus returns a new empty (blank) status
+func (lookup *ResourceLookup) NewEmptyStatus() genruntime.ConvertibleStatus {
+	return &ResourceLookupStatus{}
+}
+
+// Owner returns the ResourceReference of the owner. When the resource is identified by Spec.ARMID, this is the ARM
+// ID of its parent, or nil for resources at the root of the tenant. If Spec.ARMID is invalid, it's returned as is, so
+// that the error is reported when the owner is resolved.
+func (lookup *ResourceLookup) Owner() *genruntime.ResourceReference {
+	if lookup.Spec.ARMID != "" {
+		id, err := lookup.Spec.parseARMID()
+		if err != nil {
+			return &genruntime.ResourceReference{ARMID: lookup.Spec.ARMID}
+		}
+		if isTenantRoot(id.Parent) {
+			return nil
+		}
+		return &genruntime.ResourceReference{ARMID: id.Parent.String()}
+	}
+
+	if lookup.Spec.Owner == nil {
+		return nil
+	}
+	return lookup.Spec.Owner.AsResourceReference()
+}
+
+// SetStatus sets the status of this resource
+func (lookup *ResourceLookup) SetStatus(status genruntime.ConvertibleStatus) error {
+	st, ok := status.(*ResourceLookupStatus)
+	if !ok {
+		return eris.Errorf("expected status of type ResourceLookupStatus but received %T instead", status)
+	}
+
+	lookup.Status = *st
+	return nil
+}
+
+// OriginalGVK returns a GroupValueKind for the original API version used to create the resource
+func (lookup *ResourceLookup) OriginalGVK() *schema.GroupVersionKind {
+	return &schema.GroupVersionKind{
+		Group:   GroupVersion.Group,
+		Version: GroupVersion.Version,
+		Kind:    "ResourceLookup",
+	}
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.armId) ? !has(self.owner) && !has(self.type) && !has(self.azureName) : has(self.type) && has(self.azureName)",message="either armId, or type and azureName (with an optional owner), must be set"
+// ResourceLookupSpec identifies the Azure resource read by a ResourceLookup. The resource is identified either by
+// ARMID, or by Owner, Type and AzureName.
+type ResourceLookupSpec struct {
+	// +kubebuilder:validation:Pattern="(?i)^(/subscriptions/([^/]+)(/resourcegroups/([^/]+))?)?/providers/([^/]+)/([^/]+/[^/]+)(/([^/]+/[^/]+))*$"
+	// ARMID is the ARM ID of the resource, for example
+	// /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv.
+	ARMID string `json:"armId,omitempty"`
+
+	// Owner is the resource the resource belongs to, such as its resource group or parent resource. Not used with
+	// ARMID.
+	Owner *genruntime.ArbitraryOwnerReference `json:"owner,omitempty"`
+
+	// +kubebuilder:validation:Pattern="^[^/]+(/[^/]+)+$"
+	// Type is the ARM type of the resource, for example Microsoft.Network/virtualNetworks/subnets. Not used with ARMID.
+	Type string `json:"type,omitempty"`
+
+	// AzureName is the name of the resource in Azure. Not used with ARMID.
+	AzureName string `json:"azureName,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[0-9]{4}-[0-9]{2}-[0-9]{2}(-[a-zA-Z]+)?$"
+	// APIVersion is the ARM API version used to read the resource, for example 2024-05-01.
+	APIVersion string `json:"apiVersion"`
+
+	// OperatorSpec is the specification for configuring operator behavior. This field is interpreted by the operator and
+	// not passed directly to Azure.
+	OperatorSpec *ResourceLookupOperatorSpec `json:"operatorSpec,omitempty"`
+}
+
+var _ genruntime.ConvertibleSpec = &ResourceLookupSpec{}
+
+// ConvertSpecFrom populates our ResourceLookupSpec from the provided source
+func (spec *ResourceLookupSpec) ConvertSpecFrom(source genruntime.ConvertibleSpec) error {
+	src, ok := source.(*ResourceLookupSpec)
+	if !ok {
+		return eris.Errorf("expected spec of type ResourceLookupSpec but received %T instead", source)
+	}
+
+	src.DeepCopyInto(spec)
+	return nil
+}
+
+// ConvertSpecTo populates the provided destination from our ResourceLookupSpec
+func (spec *ResourceLookupSpec) ConvertSpecTo(destination genruntime.ConvertibleSpec) error {
+	dst, ok := destination.(*ResourceLookupSpec)
+	if !ok {
+		return eris.Errorf("expected spec of type ResourceLookupSpec but received %T instead", destination)
+	}
+
+	spec.DeepCopyInto(dst)
+	return nil
+}
+
+var _ genruntime.ARMTransformer = &ResourceLookupSpec{}
+
+// ConvertToARM converts from a Kubernetes CRD object to an ARM object. The result identifies the resource, but is never
+// sent to Azure.
+func (spec *ResourceLookupSpec) ConvertToARM(resolved genruntime.ConvertToARMResolvedDetails) (interface{}, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	result := &ARMResource_Spec_ARM{
+		APIVersion: spec.APIVersion,
+		Type:       spec.Type,
+		Name:       resolved.Name,
+	}
+
+	if spec.ARMID != "" {
+		id, err := spec.parseARMID()
+		if err != nil {
+			return nil, err
+		}
+		result.Type = id.ResourceType.String()
+	}
+
+	return result, nil
+}
+
+// NewEmptyARMValue returns an empty ARM value suitable for deserializing into
+func (spec *ResourceLookupSpec) NewEmptyARMValue() genruntime.ARMResourceStatus {
+	return &ARMResource_Spec_ARM{
+		APIVersion: spec.APIVersion,
+		Type:       spec.Type,
+	}
+}
+
+// PopulateFromARM populates a Kubernetes CRD object from an Azure ARM object
+func (spec *ResourceLookupSpec) PopulateFromARM(owner genruntime.ArbitraryOwnerReference, armInput interface{}) error {
+	typedInput, ok := armInput.(ARMResource_Spec_ARM)
+	if !ok {
+		return eris.Errorf("unexpected type supplied for PopulateFromARM() function. Expected ARMResource_Spec_ARM, got %T", armInput)
+	}
+
+	spec.AzureName = genruntime.ExtractKubernetesResourceNameFromARMName(typedInput.Name)
+	spec.APIVersion = typedInput.APIVersion
+	spec.Type = typedInput.Type
+	if owner.Name != "" {
+		spec.Owner = &owner
+	}
+
+	return nil
+}
+
+// parseARMID returns the parsed ARMID, or an error if it's invalid
+func (spec *ResourceLookupSpec) parseARMID() (*arm.ResourceID, error) {
+	id, err := arm.ParseResourceID(spec.ARMID)
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid armId %q", spec.ARMID)
+	}
+
+	return id, nil
+}
+
+// armIDScope returns the scope of the resource with the given ARM ID, based on its parent
+func armIDScope(id *arm.ResourceID) genruntime.ResourceScope {
+	parent := id.Parent
+	switch {
+	case isTenantRoot(parent):
+		return genruntime.ResourceScopeTenant
+	case strings.EqualFold(parent.ResourceType.String(), arm.ResourceGroupResourceType.String()):
+		return genruntime.ResourceScopeResourceGroup
+	case len(id.ResourceType.Types) > 1:
+		// A child resource, which is found within its parent
+		return genruntime.ResourceScopeResourceGroup
+	default:
+		return genruntime.ResourceScopeExtension
+	}
+}
+
+// isTenantRoot returns true if the ARM ID is the root of the tenant, which is the parent of tenant level resources
+func isTenantRoot(id *arm.ResourceID) bool {
+	return id == nil || strings.EqualFold(id.ResourceType.String(), arm.TenantResourceType.String())
+}
+
+// ResourceLookupOperatorSpec is the specification for configuring operator behavior for a ResourceLookup.
+type ResourceLookupOperatorSpec struct {
+	// ConfigMapExpressions are expressions whose results are written to config maps. The resource read from Azure is
+	// available as self.status.body.
+	ConfigMapExpressions []*core.DestinationExpression `json:"configMapExpressions,omitempty"`
+
+	// SecretExpressions are expressions whose results are written to secrets. The resource read from Azure is
+	// available as self.status.body.
+	SecretExpressions []*core.DestinationExpression `json:"secretExpressions,omitempty"`
+}
+
+// ResourceLookupStatus defines the observed state of a ResourceLookup.
+type ResourceLookupStatus struct {
+	// Conditions describe the observed state of the resource.
+	Conditions []conditions.Condition `json:"conditions,omitempty"`
+
+	// Body is the resource as last read from Azure.
+	Body map[string]apiextensionsv1.JSON `json:"body,omitempty"`
+}
+
+var _ genruntime.ConvertibleStatus = &ResourceLookupStatus{}
+
+// ConvertStatusFrom populates our ResourceLookupStatus from the provided source
+func (status *ResourceLookupStatus) ConvertStatusFrom(source genruntime.ConvertibleStatus) error {
+	src, ok := source.(*ResourceLookupStatus)
+	if !ok {
+		return eris.Errorf("expected status of type ResourceLookupStatus but received %T instead", source)
+	}
+
+	src.DeepCopyInto(status)
+	return nil
+}
+
+// ConvertStatusTo populates the provided destination from our ResourceLookupStatus
+func (status *ResourceLookupStatus) ConvertStatusTo(destination genruntime.ConvertibleStatus) error {
+	dst, ok := destination.(*ResourceLookupStatus)
+	if !ok {
+		return eris.Errorf("expected status of type ResourceLookupStatus but received %T instead", destination)
+	}
+
+	status.DeepCopyInto(dst)
+	return nil
+}
+
+var _ genruntime.FromARMConverter = &ResourceLookupStatus{}
+
+// NewEmptyARMValue returns an empty ARM value suitable for deserializing into
+func (status *ResourceLookupStatus) NewEmptyARMValue() genruntime.ARMResourceStatus {
+	return &ARMResource_STATUS_ARM{}
+}
+
+// PopulateFromARM populates a Kubernetes CRD object from an Azure ARM object
+func (status *ResourceLookupStatus) PopulateFromARM(owner genruntime.ArbitraryOwnerReference, armInput interface{}) error {
+	typedInput, ok := armInput.(ARMResource_STATUS_ARM)
+	if !ok {
+		return eris.Errorf("unexpected type supplied for PopulateFromARM() function. Expected ARMResource_STATUS_ARM, got %T", armInput)
+	}
+
+	status.Body = typedInput
+	return nil
+}
+
+// +kubebuilder:object:root=true
+// ResourceLookupList contains a list of ResourceLookup
+type ResourceLookupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceLookup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ResourceLookup{}, &ResourceLookupList{})
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+const testVNetID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"
+
+func Test_ResourceLookup_IdentifiedByARMID(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		armID         string
+		expectedName  string
+		expectedType  string
+		expectedOwner *genruntime.ResourceReference
+		expectedScope genruntime.ResourceScope
+	}{
+		{
+			name:          "Resource in a resource group",
+			armID:         testVNetID,
+			expectedName:  "vnet",
+			expectedType:  "Microsoft.Network/virtualNetworks",
+			expectedOwner: &genruntime.ResourceReference{ARMID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg"},
+			expectedScope: genruntime.ResourceScopeResourceGroup,
+		},
+		{
+			name:          "Child resource",
+			armID:         testVNetID + "/subnets/default",
+			expectedName:  "default",
+			expectedType:  "Microsoft.Network/virtualNetworks/subnets",
+			expectedOwner: &genruntime.ResourceReference{ARMID: testVNetID},
+			expectedScope: genruntime.ResourceScopeResourceGroup,
+		},
+		{
+			name:          "Resource in a subscription",
+			armID:         "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleDefinitions/reader",
+			expectedName:  "reader",
+			expectedType:  "Microsoft.Authorization/roleDefinitions",
+			expectedOwner: &genruntime.ResourceReference{ARMID: "/subscriptions/00000000-0000-0000-0000-000000000000"},
+			expectedScope: genruntime.ResourceScopeExtension,
+		},
+		{
+			name:          "Extension resource",
+			armID:         testVNetID + "/providers/Microsoft.Authorization/locks/lock",
+			expectedName:  "lock",
+			expectedType:  "Microsoft.Authorization/locks",
+			expectedOwner: &genruntime.ResourceReference{ARMID: testVNetID},
+			expectedScope: genruntime.ResourceScopeExtension,
+		},
+		{
+			name:          "Tenant resource",
+			armID:         "/providers/Microsoft.Management/managementGroups/mg",
+			expectedName:  "mg",
+			expectedType:  "Microsoft.Management/managementGroups",
+			expectedOwner: nil,
+			expectedScope: genruntime.ResourceScopeTenant,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			lookup := &ResourceLookup{
+				Spec: ResourceLookupSpec{
+					ARMID:      c.armID,
+					APIVersion: "2024-05-01",
+				},
+			}
+
+			g.Expect(lookup.AzureName()).To(Equal(c.expectedName))
+			g.Expect(lookup.GetType()).To(Equal(c.expectedType))
+			g.Expect(lookup.Owner()).To(Equal(c.expectedOwner))
+			g.Expect(lookup.GetResourceScope()).To(Equal(c.expectedScope))
+
+			armSpec, err := lookup.Spec.ConvertToARM(genruntime.ConvertToARMResolvedDetails{Name: c.expectedName})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(armSpec).To(Equal(&ARMResource_Spec_ARM{
+				APIVersion: "2024-05-01",
+				Type:       c.expectedType,
+				Name:       c.expectedName,
+			}))
+		})
+	}
+}
+
+func Test_ResourceLookup_IdentifiedByOwnerTypeAndName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		owner         *genruntime.ArbitraryOwnerReference
+		expectedOwner *genruntime.ResourceReference
+		expectedScope genruntime.ResourceScope
+	}{
+		{
+			name:          "Owned by a Kubernetes resource",
+			owner:         &genruntime.ArbitraryOwnerReference{Group: "resources.azure.com", Kind: "ResourceGroup", Name: "rg"},
+			expectedOwner: &genruntime.ResourceReference{Group: "resources.azure.com", Kind: "ResourceGroup", Name: "rg"},
+			expectedScope: genruntime.ResourceScopeResourceGroup,
+		},
+		{
+			name:          "Owned by an ARM ID",
+			owner:         &genruntime.ArbitraryOwnerReference{ARMID: testVNetID},
+			expectedOwner: &genruntime.ResourceReference{ARMID: testVNetID},
+			expectedScope: genruntime.ResourceScopeResourceGroup,
+		},
+		{
+			name:          "No owner",
+			owner:         nil,
+			expectedOwner: nil,
+			expectedScope: genruntime.ResourceScopeTenant,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			lookup := &ResourceLookup{
+				Spec: ResourceLookupSpec{
+					Owner:      c.owner,
+					Type:       "Microsoft.Network/virtualNetworks/subnets",
+					AzureName:  "default",
+					APIVersion: "2024-05-01",
+				},
+			}
+
+			g.Expect(lookup.AzureName()).To(Equal("default"))
+			g.Expect(lookup.GetType()).To(Equal("Microsoft.Network/virtualNetworks/subnets"))
+			g.Expect(lookup.Owner()).To(Equal(c.expectedOwner))
+			g.Expect(lookup.GetResourceScope()).To(Equal(c.expectedScope))
+
+			armSpec, err := lookup.Spec.ConvertToARM(genruntime.ConvertToARMResolvedDetails{Name: "default"})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(armSpec).To(Equal(&ARMResource_Spec_ARM{
+				APIVersion: "2024-05-01",
+				Type:       "Microsoft.Network/virtualNetworks/subnets",
+				Name:       "default",
+			}))
+		})
+	}
+}
+
+func Test_ResourceLookup_InvalidARMID_DoesNotFallBack(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	lookup := &ResourceLookup{
+		Spec: ResourceLookupSpec{
+			ARMID:      "subscriptions/not-an-id",
+			Type:       "Microsoft.Network/virtualNetworks",
+			AzureName:  "vnet",
+			APIVersion: "2024-05-01",
+		},
+	}
+
+	g.Expect(lookup.AzureName()).To(BeEmpty())
+	g.Expect(lookup.GetType()).To(BeEmpty())
+	g.Expect(lookup.Owner()).To(Equal(&genruntime.ResourceReference{ARMID: "subscriptions/not-an-id"}))
+
+	_, err := lookup.Spec.ConvertToARM(genruntime.ConvertToARMResolvedDetails{Name: "vnet"})
+	g.Expect(err).To(MatchError(ContainSubstring("subscriptions/not-an-id")))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=resourceplans,verbs=get;list;watch;create;update;patch;delete
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=aso-plan
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".resource.kind"
+// +kubebuilder:printcolumn:name="Resource",type="string",JSONPath=".resource.name"
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".status.action"
+// +kubebuilder:printcolumn:name="Changes",type="integer",JSONPath=".status.changeCount"
+// +kubebuilder:storageversion
+// ResourcePlan records the changes the operator would make in Azure for a resource with the plan reconcile policy.
+// It is created by the operator alongside the resource, and deleted along with it.
+type ResourcePlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Resource identifies the resource the plan is for.
+	Resource OperationHistoryResource `json:"resource,omitempty"`
+
+	// Status is the most recent plan for the resource.
+	Status ResourcePlanStatus `json:"status,omitempty"`
+}
+
+// ResourcePlanAction is the change the operator would make to a resource in Azure.
+// +kubebuilder:validation:Enum={"Create","Modify","NoOp"}
+type ResourcePlanAction string
+
+const (
+	// ResourcePlanActionCreate indicates the resource doesn't exist in Azure, and would be created.
+	ResourcePlanActionCreate = ResourcePlanAction("Create")
+
+	// ResourcePlanActionModify indicates the resource exists in Azure, and would be modified.
+	ResourcePlanActionModify = ResourcePlanAction("Modify")
+
+	// ResourcePlanActionNoOp indicates the resource in Azure already matches the desired state.
+	ResourcePlanActionNoOp = ResourcePlanAction("NoOp")
+)
+
+// ResourcePlanStatus is the most recent plan for a resource.
+type ResourcePlanStatus struct {
+	// Action is the change the operator would make to the resource in Azure.
+	Action ResourcePlanAction `json:"action,omitempty"`
+
+	// Generation is the generation of the resource that was planned.
+	Generation int64 `json:"generation,omitempty"`
+
+	// PlannedTime is when the plan was made.
+	PlannedTime metav1.Time `json:"plannedTime,omitempty"`
+
+	// ChangeCount is the number of properties that would be changed.
+	ChangeCount int `json:"changeCount,omitempty"`
+
+	// Changes are the properties that would be changed, if the resource would be modified.
+	Changes []PlannedChange `json:"changes,omitempty"`
+}
+
+// PlannedChange is a single property the operator would change in Azure.
+type PlannedChange struct {
+	// Path is the JSON path of the property in the ARM payload, e.g. properties.minimumTlsVersion.
+	Path string `json:"path,omitempty"`
+
+	// Type is the kind of change: Add (the property has no value in Azure), Modify (the property has a different
+	// value in Azure), Absent (the property isn't returned by Azure at all, as for passwords) or Remove (the property
+	// was set by the operator at a merge path, but is no longer in the spec).
+	Type string `json:"type,omitempty"`
+
+	// OldValue is the value of the property in Azure, if it has one. Not recorded if Redacted is set.
+	OldValue *apiextensionsv1.JSON `json:"oldValue,omitempty"`
+
+	// NewValue is the value the property would be given, unless it would be removed. Not recorded if Redacted is set.
+	NewValue *apiextensionsv1.JSON `json:"newValue,omitempty"`
+
+	// Redacted is true if the values of the property aren't recorded, as they include the value of a secret.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ResourcePlanList contains a list of ResourcePlan
+type ResourcePlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourcePlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ResourcePlan{}, &ResourcePlanList{})
+}
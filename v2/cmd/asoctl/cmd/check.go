@@ -0,0 +1,21 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+// newCheckCommand creates a new cobra command for checking ASO resources before they're applied
+func newCheckCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check ASO resources before applying them to a cluster",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newCheckPermissionsCommand())
+
+	return cmd, nil
+}
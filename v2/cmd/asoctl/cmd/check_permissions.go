@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+	"github.com/Azure/azure-service-operator/v2/internal/permissions"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/config"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+func newCheckPermissionsCommand() *cobra.Command {
+	var options checkPermissionsOptions
+
+	cmd := &cobra.Command{
+		Use:   "permissions",
+		Short: "Check the current identity has the Azure permissions needed by ASO resources",
+		Long: `Checks the current identity has the Azure permissions needed to create the ASO resources in the specified
+files, reporting any missing actions.
+
+Permissions are checked with the Microsoft.Authorization/permissions API at the scope of each resource, and of each
+resource it refers to. Resources that don't exist yet are checked at the scope of their nearest existing parent.
+
+Owners and references must either be included in the files, or use ARM IDs.
+
+Authentication uses the same modes, and the same environment variables, as 'asoctl import azure-resource'.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return checkPermissions(ctx, &options)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(
+		&options.files,
+		"file",
+		"f",
+		nil,
+		"YAML files containing the ASO resources to check. Multiple files can be specified.")
+	_ = cmd.MarkFlagRequired("file")
+
+	cmd.Flags().StringVarP(
+		&options.namespace,
+		"namespace",
+		"n",
+		"default",
+		"The namespace of resources that don't specify one")
+
+	cmd.Flags().StringVar(
+		&options.subscriptionID,
+		"subscription",
+		os.Getenv(config.AzureSubscriptionID),
+		"The subscription the resources will be created in. Defaults to the AZURE_SUBSCRIPTION_ID environment variable.")
+
+	return cmd
+}
+
+// checkPermissions checks the permissions needed by the resources in the files
+func checkPermissions(ctx context.Context, options *checkPermissionsOptions) error {
+	if options.subscriptionID == "" {
+		return eris.New("no subscription provided, use --subscription or set AZURE_SUBSCRIPTION_ID")
+	}
+
+	log := CreateLogger()
+	scheme := api.CreateScheme()
+
+	var objs []genruntime.ARMMetaObject
+	for _, file := range options.files {
+		loaded, err := loadResources(scheme, file, options.namespace)
+		if err != nil {
+			return eris.Wrapf(err, "failed to load resources from %s", file)
+		}
+
+		objs = append(objs, loaded...)
+	}
+
+	if len(objs) == 0 {
+		log.Info("No ASO resources found, nothing to check.")
+		return nil
+	}
+
+	resources, err := permissions.NewResourceSet(scheme, objs)
+	if err != nil {
+		return eris.Wrap(err, "failed to index resources")
+	}
+
+	var requirements []permissions.Requirement
+	for _, obj := range objs {
+		reqs, err := permissions.RequirementsFor(ctx, resources, obj, options.subscriptionID)
+		if err != nil {
+			return eris.Wrapf(err, "failed to find permissions needed by %s", obj.GetName())
+		}
+
+		requirements = append(requirements, reqs...)
+	}
+
+	client, err := createARMClient(cloudFromEnvironment().Cloud())
+	if err != nil {
+		return eris.Wrap(err, "failed to create ARM client")
+	}
+
+	missing, err := permissions.NewChecker(client).Check(ctx, requirements)
+	if err != nil {
+		return eris.Wrap(err, "failed to check permissions")
+	}
+
+	if len(missing) == 0 {
+		log.Info("All permissions present", "resources", len(objs))
+		return nil
+	}
+
+	writeMissingPermissions(os.Stdout, missing)
+	return eris.Errorf("missing permissions needed by %d resources", countResources(missing))
+}
+
+// loadResources reads the ASO resources in a YAML file, ignoring any other Kubernetes resources
+func loadResources(scheme *runtime.Scheme, file string, namespace string) ([]genruntime.ARMMetaObject, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, eris.Wrapf(err, "opening %s", file)
+	}
+	defer f.Close()
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := yaml.NewYAMLReader(bufio.NewReader(f))
+
+	var result []genruntime.ARMMetaObject
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, eris.Wrap(err, "reading YAML")
+		}
+
+		if strings.TrimSpace(string(doc)) == "" {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) {
+				// Not an ASO resource
+				continue
+			}
+
+			return nil, eris.Wrap(err, "decoding resource")
+		}
+
+		metaObj, ok := obj.(genruntime.ARMMetaObject)
+		if !ok {
+			continue
+		}
+
+		if metaObj.GetNamespace() == "" {
+			metaObj.SetNamespace(namespace)
+		}
+
+		result = append(result, metaObj)
+	}
+
+	return result, nil
+}
+
+// writeMissingPermissions writes the missing permissions, one action per line, grouped by resource
+func writeMissingPermissions(out io.Writer, missing []permissions.MissingPermission) {
+	resource := ""
+	for _, m := range missing {
+		if m.Resource != resource {
+			resource = m.Resource
+			fmt.Fprintf(out, "%s:\n", resource)
+		}
+
+		for _, action := range m.Actions {
+			fmt.Fprintf(out, "  %s at %s\n", action, m.Scope)
+		}
+	}
+}
+
+func countResources(missing []permissions.MissingPermission) int {
+	resources := make(map[string]struct{}, len(missing))
+	for _, m := range missing {
+		resources[m.Resource] = struct{}{}
+	}
+
+	return len(resources)
+}
+
+type checkPermissionsOptions struct {
+	files          []string
+	namespace      string
+	subscriptionID string
+}
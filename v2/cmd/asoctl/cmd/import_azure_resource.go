@@ -136,7 +136,7 @@ func importAzureResource(
 	}
 
 	// Create an ARM client for requesting resources
-	client, err := createARMClient(options)
+	client, err := createARMClient(options.cloud())
 	if err != nil {
 		return eris.Wrapf(err, "failed to create ARM client")
 	}
@@ -227,7 +227,7 @@ func importAzureResource(
 }
 
 // createARMClient creates our client for talking to ARM
-func createARMClient(options *importAzureResourceOptions) (*genericarmclient.GenericClient, error) {
+func createARMClient(activeCloud cloud.Configuration) (*genericarmclient.GenericClient, error) {
 	creds, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
 		return nil, eris.Wrap(err, "unable to get default Azure credential")
@@ -237,7 +237,6 @@ func createARMClient(options *importAzureResourceOptions) (*genericarmclient.Gen
 		UserAgent: "asoctl/" + version.BuildVersion,
 	}
 
-	activeCloud := options.cloud()
 	return genericarmclient.NewGenericClient(activeCloud, creds, clientOptions)
 }
 
@@ -342,12 +341,17 @@ func (option *importAzureResourceOptions) writeToFolder() (string, bool) {
 
 func (option *importAzureResourceOptions) cloud() cloud.Configuration {
 	option.readCloud.Do(func() {
-		option.cloudCfg = asocloud.Configuration{
-			AzureAuthorityHost:      os.Getenv(config.AzureAuthorityHost),
-			ResourceManagerEndpoint: os.Getenv(config.ResourceManagerEndpoint),
-			ResourceManagerAudience: os.Getenv(config.ResourceManagerAudience),
-		}
+		option.cloudCfg = cloudFromEnvironment()
 	})
 
 	return option.cloudCfg.Cloud()
 }
+
+// cloudFromEnvironment returns the cloud configured by environment variables, defaulting to the public cloud
+func cloudFromEnvironment() asocloud.Configuration {
+	return asocloud.Configuration{
+		AzureAuthorityHost:      os.Getenv(config.AzureAuthorityHost),
+		ResourceManagerEndpoint: os.Getenv(config.ResourceManagerEndpoint),
+		ResourceManagerAudience: os.Getenv(config.ResourceManagerAudience),
+	}
+}
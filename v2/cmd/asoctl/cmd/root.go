@@ -47,6 +47,7 @@ func newRootCommand() (*cobra.Command, error) {
 	rootCmd.Flags().SortFlags = false
 
 	cmds := []func() (*cobra.Command, error){
+		newCheckCommand,
 		newCleanCommand,
 		newImportCommand,
 		newExportCommand,
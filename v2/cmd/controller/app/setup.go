@@ -38,15 +38,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/Azure/azure-service-operator/v2/internal/audit"
+	"github.com/Azure/azure-service-operator/v2/internal/changeevents"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
 	"github.com/Azure/azure-service-operator/v2/internal/controllers"
 	"github.com/Azure/azure-service-operator/v2/internal/crdmanagement"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	"github.com/Azure/azure-service-operator/v2/internal/identity"
 	asometrics "github.com/Azure/azure-service-operator/v2/internal/metrics"
+	"github.com/Azure/azure-service-operator/v2/internal/permissions"
 	armreconciler "github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
 	entrareconciler "github.com/Azure/azure-service-operator/v2/internal/reconcilers/entra"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/generic"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
@@ -272,6 +276,26 @@ func getMetricsOpts(flags *Flags) server.Options {
 	return metricsOptions
 }
 
+// newAuditSink creates the sink for audit records of requests that modify resources in Azure, or returns nil if
+// audit logging is disabled.
+func newAuditSink(cfg config.AuditLog, log logr.Logger) (audit.Sink, error) {
+	switch cfg.Sink {
+	case config.AuditLogSinkStdout:
+		return audit.NewStdoutSink(log), nil
+	case config.AuditLogSinkFile:
+		file, err := audit.NewRotatingFile(cfg.File, int64(cfg.FileMaxSize)*1024*1024, cfg.FileMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+
+		return audit.NewJSONSink(file, log), nil
+	case config.AuditLogSinkWebhook:
+		return audit.NewWebhookSink(cfg.WebhookURL, nil, log), nil
+	default:
+		return nil, nil
+	}
+}
+
 func getDefaultAzureCredential(cfg config.Values, setupLog logr.Logger) (*identity.Credential, error) {
 	tokenCred, err := getDefaultAzureTokenCredential(cfg, setupLog)
 	if err != nil {
@@ -414,6 +438,23 @@ func initializeClients(cfg config.Values, mgr ctrl.Manager) (*clients, error) {
 		nil,
 		armMetrics)
 
+	if cfg.StatusRefresh.Mode == config.StatusRefreshModeResourceGraph {
+		statusCache := armreconciler.NewStatusCache(cfg.StatusRefresh.Interval, log.WithName("statuscache"))
+		armClientCache.SetStatusCache(statusCache)
+		err = mgr.Add(statusCache)
+		if err != nil {
+			return nil, eris.Wrap(err, "error adding status cache to manager")
+		}
+	}
+
+	auditSink, err := newAuditSink(cfg.AuditLog, log.WithName("audit"))
+	if err != nil {
+		return nil, eris.Wrap(err, "error creating audit log")
+	}
+	if auditSink != nil {
+		armClientCache.SetAuditSink(auditSink)
+	}
+
 	genericarmclient.AddToUserAgent(cfg.UserAgentSuffix)
 
 	entraClientCache := entrareconciler.NewEntraClientCache(
@@ -449,19 +490,38 @@ func initializeClients(cfg config.Values, mgr ctrl.Manager) (*clients, error) {
 func initializeWatchers(readyResources map[string]apiextensions.CustomResourceDefinition, cfg config.Values, mgr ctrl.Manager, clients *clients) error {
 	clients.log.V(Status).Info("Configuration details", "config", cfg.String())
 
+	resourceResolver := resolver.NewResolver(clients.kubeClient)
 	clientsProvider := &controllers.ClientsProvider{
 		KubeClient:             clients.kubeClient,
 		ARMConnectionFactory:   clients.armConnectionFactory,
 		EntraConnectionFactory: clients.entraConnectionFactory,
+		Resolver:               resourceResolver,
 	}
 
+	options := clients.options
+	if cfg.ChangeEvents.Enabled() {
+		log := clients.log.WithName("changeevents")
+		options.ChangeEvents = changeevents.NewDispatcher(resourceResolver, log)
+		err := mgr.Add(changeevents.NewServer(cfg.ChangeEvents.BindAddress, cfg.ChangeEvents.Key, options.ChangeEvents, log))
+		if err != nil {
+			return eris.Wrap(err, "failed to add change event server to manager")
+		}
+	}
+
+	// ReferenceGrants are optional, so we only watch them if their CRD is installed
+	_, options.WatchReferenceGrants = readyResources["referencegrants.serviceoperator.azure.com"]
+
+	// Likewise PayloadMutationPolicies and ResourcePlans, so we only use them if their CRDs are installed
+	_, options.ApplyPayloadMutationPolicies = readyResources["payloadmutationpolicies.serviceoperator.azure.com"]
+	_, options.RecordResourcePlans = readyResources["resourceplans.serviceoperator.azure.com"]
+
 	objs, err := controllers.GetKnownStorageTypes(
 		mgr,
 		clientsProvider,
 		clients.credentialProvider,
 		clients.positiveConditions,
 		clients.expressionEvaluator,
-		clients.options)
+		options)
 	if err != nil {
 		return eris.Wrap(err, "failed getting storage types and reconcilers")
 	}
@@ -472,17 +532,48 @@ func initializeWatchers(readyResources map[string]apiextensions.CustomResourceDe
 		return eris.Wrap(err, "failed to filter storage types by ready CRDs")
 	}
 
+	// Only the types registered below are indexed, so only they can be searched for deletion protection
+	err = resourceResolver.IndexDeletionProtectedTypes(mgr.GetScheme(), objs)
+	if err != nil {
+		return eris.Wrap(err, "failed to add deletion protected types to resource resolver")
+	}
+
 	err = generic.RegisterAll(
 		mgr,
 		mgr.GetFieldIndexer(),
 		clients.kubeClient,
 		clients.positiveConditions,
 		objs,
-		clients.options)
+		options)
 	if err != nil {
 		return eris.Wrap(err, "failed to register gvks")
 	}
 
+	// AzureCredentials are optional, so we only check their health if their CRD is installed
+	if _, ok := readyResources["azurecredentials.serviceoperator.azure.com"]; ok {
+		credentialReconciler := identity.NewAzureCredentialReconciler(
+			clients.kubeClient,
+			clients.positiveConditions,
+			&identity.CredentialProviderOptions{
+				Cloud: to.Ptr(cfg.Cloud()),
+			})
+		permissionChecker, err := permissions.NewCredentialChecker(
+			clients.kubeClient,
+			resourceResolver,
+			clients.credentialProvider,
+			cfg.Cloud(),
+			objs)
+		if err != nil {
+			return eris.Wrap(err, "failed to create permission checker")
+		}
+		credentialReconciler.SetPermissionChecker(permissionChecker)
+
+		err = credentialReconciler.SetupWithManager(mgr, options.Options)
+		if err != nil {
+			return eris.Wrap(err, "failed to register AzureCredential reconciler")
+		}
+	}
+
 	return nil
 }
 
@@ -6,14 +6,17 @@ Licensed under the MIT license.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"time"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/Azure/azure-service-operator/v2/cmd/controller/app"
 	"github.com/Azure/azure-service-operator/v2/cmd/controller/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/internal/version"
 )
 
@@ -41,10 +44,26 @@ func main() {
 	ctrl.SetLogger(log)
 	log.Info("Launching with flags", "flags", appFlags.String())
 
+	shutdownTracing, err := tracing.Setup(ctx, log.WithName("tracing"))
+	if err != nil {
+		log.Error(err, "failed to set up tracing")
+		os.Exit(1)
+	}
+
 	mgr := app.SetupControllerManager(ctx, log, appFlags)
 	log.Info("starting manager")
-	if err = mgr.Start(ctx); err != nil {
+	err = mgr.Start(ctx)
+
+	// Flush any spans not yet exported. The signal handler's context has been cancelled by now, so we need another
+	// context, with a timeout so that an unreachable collector can't stop us exiting
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if shutdownErr := shutdownTracing(shutdownCtx); shutdownErr != nil {
+		log.Error(shutdownErr, "failed to flush traces")
+	}
+
+	if err != nil {
 		log.Error(err, "failed to start manager")
-		os.Exit(1)
+		os.Exit(1) //nolint:gocritic // cancel doesn't need to run as we're exiting
 	}
 }
@@ -0,0 +1,82 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Outcome summarises the result of a request to ARM
+type Outcome string
+
+const (
+	// OutcomeSucceeded indicates ARM completed the request
+	OutcomeSucceeded = Outcome("Succeeded")
+	// OutcomeAccepted indicates ARM accepted the request, and will complete it asynchronously
+	OutcomeAccepted = Outcome("Accepted")
+	// OutcomeFailed indicates ARM rejected the request, or no response was received
+	OutcomeFailed = Outcome("Failed")
+)
+
+// Resource identifies the Kubernetes resource on whose behalf a request was made
+type Resource struct {
+	Group      string    `json:"group"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid"`
+	Generation int64     `json:"generation"`
+	// Actor is the user who last changed the spec of the resource, as recorded by the webhooks
+	Actor string `json:"actor,omitempty"`
+}
+
+// Record is an audit record of a single mutating request sent to ARM
+type Record struct {
+	Time time.Time `json:"time"`
+	// Resource is the Kubernetes resource the request was made for, if known
+	Resource *Resource `json:"resource,omitempty"`
+	// Credential identifies the credential used to authenticate the request
+	Credential    string        `json:"credential"`
+	Method        string        `json:"method"`
+	ARMID         string        `json:"armId"`
+	APIVersion    string        `json:"apiVersion,omitempty"`
+	PayloadHash   string        `json:"payloadHash,omitempty"`
+	CorrelationID string        `json:"correlationId,omitempty"`
+	RequestID     string        `json:"requestId,omitempty"`
+	StatusCode    int           `json:"statusCode,omitempty"`
+	Outcome       Outcome       `json:"outcome"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// HashPayload returns the SHA-256 hash of the payload, in the form used by Record.PayloadHash.
+// Returns an empty string if there is no payload.
+func HashPayload(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256(payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+type resourceKey struct{}
+
+// WithResource returns a context identifying the Kubernetes resource that requests made with it are on behalf of
+func WithResource(ctx context.Context, resource Resource) context.Context {
+	return context.WithValue(ctx, resourceKey{}, resource)
+}
+
+// ResourceFromContext returns the Kubernetes resource recorded in the context by WithResource, if any
+func ResourceFromContext(ctx context.Context) (Resource, bool) {
+	resource, ok := ctx.Value(resourceKey{}).(Resource)
+	return resource, ok
+}
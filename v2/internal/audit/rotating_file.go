@@ -0,0 +1,178 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// RotatingFile is an io.WriteCloser which appends to a file, rotating it once it reaches a maximum size.
+// Rotated files are renamed with a numeric suffix (path.1 being the most recent), and only the most recent backups
+// are kept.
+type RotatingFile struct {
+	lock       sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File // nil if a new file couldn't be opened when rotating; the next Write tries again
+	size       int64
+	closed     bool
+}
+
+var _ io.WriteCloser = &RotatingFile{}
+
+// NewRotatingFile creates a new RotatingFile writing to path.
+// maxSize is the size in bytes at which the file is rotated, and maxBackups is the number of rotated files to keep.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if maxSize <= 0 {
+		return nil, eris.Errorf("maxSize must be greater than 0, but was %d", maxSize)
+	}
+
+	if maxBackups < 0 {
+		return nil, eris.Errorf("maxBackups must not be negative, but was %d", maxBackups)
+	}
+
+	result := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	err := result.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Write appends p to the file, rotating it first if the write would take it over the maximum size.
+// Each write goes to a single file, so writes larger than the maximum size are not split. If the file can't be
+// rotated, p is still appended to the current file, and the error is returned so that it can be reported.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return 0, eris.Errorf("file %s is closed", f.path)
+	}
+
+	if f.file == nil {
+		// The last rotation couldn't open a new file, so try again
+		err := f.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var rotateErr error
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		rotateErr = f.rotate()
+		if f.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		return n, eris.Wrapf(err, "writing to %s", f.path)
+	}
+
+	return n, rotateErr
+}
+
+// Close closes the current file
+func (f *RotatingFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return eris.Wrapf(err, "opening %s", f.path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return eris.Wrapf(err, "reading size of %s", f.path)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate shuffles the backups along (discarding the oldest), moves the current file to the first backup, and opens a
+// new file. The current file is kept open until the new one is, so that if rotation fails part way through, writes
+// continue to the current file. If the new file can't be opened, the next Write tries again.
+// Must be called with the lock held.
+func (f *RotatingFile) rotate() error {
+	if f.maxBackups == 0 {
+		err := os.Remove(f.path)
+		if err != nil && !os.IsNotExist(err) {
+			return eris.Wrapf(err, "removing %s", f.path)
+		}
+
+		return f.reopen()
+	}
+
+	err := os.Remove(f.backupPath(f.maxBackups))
+	if err != nil && !os.IsNotExist(err) {
+		return eris.Wrapf(err, "removing %s", f.backupPath(f.maxBackups))
+	}
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err = os.Rename(f.backupPath(i), f.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return eris.Wrapf(err, "renaming %s", f.backupPath(i))
+		}
+	}
+
+	err = os.Rename(f.path, f.backupPath(1))
+	if err != nil {
+		return eris.Wrapf(err, "renaming %s", f.path)
+	}
+
+	return f.reopen()
+}
+
+// reopen opens a new file at path once the current one has been moved out of the way, and closes the current one.
+// Must be called with the lock held.
+func (f *RotatingFile) reopen() error {
+	previous := f.file
+	err := f.open()
+
+	// Writes aren't buffered, so nothing is lost if the previous file doesn't close cleanly
+	_ = previous.Close()
+
+	if err != nil {
+		f.file = nil
+		return err
+	}
+
+	return nil
+}
+
+func (f *RotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", f.path, index)
+}
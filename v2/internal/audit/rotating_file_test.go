@@ -0,0 +1,122 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_RotatingFile_RotatesWhenFull(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	file, err := NewRotatingFile(path, 10, 2)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = file.Write([]byte(line))
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	g.Expect(readFile(t, path)).To(Equal("fourth\n"))
+	g.Expect(readFile(t, path+".1")).To(Equal("third\n"))
+	g.Expect(readFile(t, path+".2")).To(Equal("second\n"))
+	g.Expect(path + ".3").ToNot(BeAnExistingFile())
+}
+
+func Test_RotatingFile_WithNoBackups_Truncates(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	file, err := NewRotatingFile(path, 10, 0)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err = file.Write([]byte(line))
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	g.Expect(readFile(t, path)).To(Equal("second\n"))
+	g.Expect(path + ".1").ToNot(BeAnExistingFile())
+}
+
+func Test_RotatingFile_AppendsToExistingFile(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	g.Expect(os.WriteFile(path, []byte("existing\n"), 0o600)).To(Succeed())
+
+	file, err := NewRotatingFile(path, 100, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+
+	_, err = file.Write([]byte("new\n"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(readFile(t, path)).To(Equal("existing\nnew\n"))
+}
+
+func Test_RotatingFile_RotationFails_KeepsWritingAndRecovers(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	file, err := NewRotatingFile(path, 10, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+
+	_, err = file.Write([]byte("first\n"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// A directory in the way of the backup stops the file being rotated
+	obstacle := path + ".1"
+	g.Expect(os.MkdirAll(filepath.Join(obstacle, "dir"), 0o700)).To(Succeed())
+
+	n, err := file.Write([]byte("second\n"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(n).To(Equal(len("second\n")))
+	g.Expect(readFile(t, path)).To(Equal("first\nsecond\n"))
+
+	// Once the problem is fixed, rotation resumes
+	g.Expect(os.RemoveAll(obstacle)).To(Succeed())
+
+	_, err = file.Write([]byte("third\n"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(readFile(t, path)).To(Equal("third\n"))
+	g.Expect(readFile(t, path+".1")).To(Equal("first\nsecond\n"))
+}
+
+func Test_RotatingFile_WriteAfterClose_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	file, err := NewRotatingFile(path, 10, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(file.Close()).To(Succeed())
+
+	_, err = file.Write([]byte("first\n"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %s", path, err)
+	}
+
+	return string(content)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Sink receives audit records.
+// Implementations must be safe for concurrent use. Failures to deliver a record are logged by the sink rather than
+// failing the request being audited.
+type Sink interface {
+	Write(ctx context.Context, record Record)
+}
+
+// jsonSink writes each record to an io.Writer as a single line of JSON
+type jsonSink struct {
+	lock   sync.Mutex
+	writer io.Writer
+	log    logr.Logger
+}
+
+var _ Sink = &jsonSink{}
+
+// NewJSONSink creates a Sink which writes each record to writer as a line of JSON
+func NewJSONSink(writer io.Writer, log logr.Logger) Sink {
+	return &jsonSink{
+		writer: writer,
+		log:    log,
+	}
+}
+
+// NewStdoutSink creates a Sink which writes each record to stdout as a line of JSON
+func NewStdoutSink(log logr.Logger) Sink {
+	return NewJSONSink(os.Stdout, log)
+}
+
+func (s *jsonSink) Write(_ context.Context, record Record) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		s.log.Error(err, "failed to serialize audit record", "armID", record.ARMID)
+		return
+	}
+
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err = s.writer.Write(line)
+	if err != nil {
+		s.log.Error(err, "failed to write audit record", "armID", record.ARMID)
+	}
+}
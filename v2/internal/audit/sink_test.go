@@ -0,0 +1,103 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+)
+
+func Test_JSONSink_WritesOneRecordPerLine(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var buffer bytes.Buffer
+	sink := NewJSONSink(&buffer, logr.Discard())
+
+	sink.Write(context.Background(), newTestRecord("PUT"))
+	sink.Write(context.Background(), newTestRecord("DELETE"))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	g.Expect(lines).To(HaveLen(2))
+
+	var record Record
+	g.Expect(json.Unmarshal([]byte(lines[1]), &record)).To(Succeed())
+	g.Expect(record.Method).To(Equal("DELETE"))
+	g.Expect(record.Resource.Name).To(Equal("myrg"))
+	g.Expect(record.Resource.Actor).To(Equal("alice@example.com"))
+}
+
+func Test_WebhookSink_PostsRecord(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	received := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, server.Client(), logr.Discard())
+	sink.Write(context.Background(), newTestRecord("PUT"))
+
+	var record Record
+	g.Expect(json.Unmarshal(<-received, &record)).To(Succeed())
+	g.Expect(record.ARMID).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg"))
+	g.Expect(record.PayloadHash).To(Equal(HashPayload([]byte(`{"location":"westus"}`))))
+	g.Expect(record.Outcome).To(Equal(OutcomeSucceeded))
+}
+
+func Test_HashPayload_WithNoPayload_ReturnsEmpty(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(HashPayload(nil)).To(BeEmpty())
+	g.Expect(HashPayload([]byte("{}"))).To(HavePrefix("sha256:"))
+}
+
+func Test_ResourceFromContext_ReturnsResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	_, ok := ResourceFromContext(context.Background())
+	g.Expect(ok).To(BeFalse())
+
+	ctx := WithResource(context.Background(), Resource{Kind: "ResourceGroup", Name: "myrg"})
+	resource, ok := ResourceFromContext(ctx)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(resource.Name).To(Equal("myrg"))
+}
+
+func newTestRecord(method string) Record {
+	return Record{
+		Resource: &Resource{
+			Group:      "resources.azure.com",
+			Kind:       "ResourceGroup",
+			Namespace:  "default",
+			Name:       "myrg",
+			Generation: 2,
+			Actor:      "alice@example.com",
+		},
+		Credential:  "default/aso-credential",
+		Method:      method,
+		ARMID:       "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg",
+		APIVersion:  "2020-06-01",
+		PayloadHash: HashPayload([]byte(`{"location":"westus"}`)),
+		StatusCode:  http.StatusOK,
+		Outcome:     OutcomeSucceeded,
+	}
+}
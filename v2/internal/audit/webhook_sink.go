@@ -0,0 +1,80 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/rotisserie/eris"
+)
+
+// defaultWebhookTimeout bounds how long delivering a record can delay the request being audited
+const defaultWebhookTimeout = 5 * time.Second
+
+// webhookSink POSTs each record to an HTTP endpoint as JSON
+type webhookSink struct {
+	url    string
+	client *http.Client
+	log    logr.Logger
+}
+
+var _ Sink = &webhookSink{}
+
+// NewWebhookSink creates a Sink which POSTs each record to url as JSON.
+// Records are delivered synchronously, so that a record has been accepted by the endpoint before the operator acts on
+// the response from ARM. If client is nil, a client with a short timeout is used.
+func NewWebhookSink(url string, client *http.Client, log logr.Logger) Sink {
+	if client == nil {
+		client = &http.Client{
+			Timeout: defaultWebhookTimeout,
+		}
+	}
+
+	return &webhookSink{
+		url:    url,
+		client: client,
+		log:    log,
+	}
+}
+
+func (s *webhookSink) Write(ctx context.Context, record Record) {
+	err := s.post(ctx, record)
+	if err != nil {
+		s.log.Error(err, "failed to deliver audit record", "armID", record.ARMID, "correlationID", record.CorrelationID)
+	}
+}
+
+func (s *webhookSink) post(ctx context.Context, record Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return eris.Wrap(err, "serializing audit record")
+	}
+
+	// Deliver the record even if the request being audited has been cancelled, so that it's not lost
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return eris.Wrap(err, "creating audit request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return eris.Wrap(err, "sending audit record")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return eris.Errorf("audit webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
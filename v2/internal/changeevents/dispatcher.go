@@ -0,0 +1,92 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package changeevents
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// ARMIDResolver finds the resources of a particular kind that have an ARM ID.
+type ARMIDResolver interface {
+	ResolveResourcesByARMID(ctx context.Context, groupKind schema.GroupKind, armID string) ([]genruntime.ARMMetaObject, error)
+}
+
+// Dispatcher enqueues resources for reconcile when they're changed in Azure.
+type Dispatcher struct {
+	resolver ARMIDResolver
+	log      logr.Logger
+
+	lock     sync.RWMutex
+	channels map[schema.GroupKind]chan event.GenericEvent
+}
+
+// NewDispatcher creates a new Dispatcher which uses the specified resolver to find the resources to reconcile.
+func NewDispatcher(resolver ARMIDResolver, log logr.Logger) *Dispatcher {
+	return &Dispatcher{
+		resolver: resolver,
+		log:      log,
+		channels: make(map[schema.GroupKind]chan event.GenericEvent),
+	}
+}
+
+// Source returns a source of events for the controller of the specified kind. Each resource of that kind which is
+// changed in Azure is passed to the handler as a generic event.
+func (d *Dispatcher) Source(groupKind schema.GroupKind, h handler.EventHandler) source.Source {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	ch := make(chan event.GenericEvent)
+	d.channels[groupKind] = ch
+
+	return source.Channel[client.Object](ch, h)
+}
+
+// Dispatch enqueues every resource with the specified ARM ID for reconcile, returning the number of resources found.
+func (d *Dispatcher) Dispatch(ctx context.Context, armID string) (int, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	var errs []error
+	count := 0
+	for groupKind, ch := range d.channels {
+		objs, err := d.resolver.ResolveResourcesByARMID(ctx, groupKind, armID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, obj := range objs {
+			d.log.V(Verbose).Info(
+				"Resource changed in Azure, enqueuing for reconcile",
+				"kind", groupKind,
+				"resource", client.ObjectKeyFromObject(obj),
+				"id", armID)
+
+			select {
+			case ch <- event.GenericEvent{Object: obj}:
+				count++
+			case <-ctx.Done():
+				return count, eris.Wrap(ctx.Err(), "enqueuing resource for reconcile")
+			}
+		}
+	}
+
+	return count, kerrors.NewAggregate(errs)
+}
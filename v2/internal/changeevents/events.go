@@ -0,0 +1,102 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package changeevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// ResourceWriteSuccess is raised by Event Grid when a resource is created or updated
+	ResourceWriteSuccess = "Microsoft.Resources.ResourceWriteSuccess"
+	// ResourceDeleteSuccess is raised by Event Grid when a resource is deleted
+	ResourceDeleteSuccess = "Microsoft.Resources.ResourceDeleteSuccess"
+
+	structuredContentType = "application/cloudevents+json"
+	batchContentType      = "application/cloudevents-batch+json"
+)
+
+// ResourceChange is a change to a resource in Azure, extracted from a change event.
+type ResourceChange struct {
+	// EventType is the type of the event, such as Microsoft.Resources.ResourceWriteSuccess
+	EventType string
+	// ResourceID is the ARM ID of the resource that changed
+	ResourceID string
+}
+
+// cloudEvent is the subset of a CloudEvent (in the structured JSON format) that we need.
+// See https://learn.microsoft.com/azure/event-grid/event-schema-resource-groups
+type cloudEvent struct {
+	Type    string          `json:"type"`
+	Subject string          `json:"subject"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// resourceEventData is the subset of the data of an Azure resource event that we need.
+type resourceEventData struct {
+	ResourceURI string `json:"resourceUri"`
+}
+
+// ParseResourceChanges extracts the resource changes from a request delivering CloudEvents. Structured (single or
+// batched) and binary content modes are supported. Events that aren't resource write or delete events are ignored.
+func ParseResourceChanges(req *http.Request, body []byte) ([]ResourceChange, error) {
+	var events []cloudEvent
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch {
+	case mediaType == batchContentType || (mediaType != structuredContentType && isJSONArray(body)):
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, eris.Wrap(err, "parsing batch of CloudEvents")
+		}
+	case mediaType == structuredContentType || req.Header.Get("ce-type") == "":
+		var e cloudEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			return nil, eris.Wrap(err, "parsing CloudEvent")
+		}
+		events = append(events, e)
+	default:
+		// Binary content mode, the attributes are in headers and the body is the data
+		events = append(events, cloudEvent{
+			Type:    req.Header.Get("ce-type"),
+			Subject: req.Header.Get("ce-subject"),
+			Data:    body,
+		})
+	}
+
+	result := make([]ResourceChange, 0, len(events))
+	for _, e := range events {
+		if e.Type != ResourceWriteSuccess && e.Type != ResourceDeleteSuccess {
+			continue
+		}
+
+		id := e.Subject
+		var data resourceEventData
+		if len(e.Data) > 0 && json.Unmarshal(e.Data, &data) == nil && data.ResourceURI != "" {
+			id = data.ResourceURI
+		}
+
+		if id == "" {
+			continue
+		}
+
+		result = append(result, ResourceChange{
+			EventType:  e.Type,
+			ResourceID: id,
+		})
+	}
+
+	return result, nil
+}
+
+func isJSONArray(body []byte) bool {
+	return strings.HasPrefix(string(bytes.TrimSpace(body)), "[")
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package changeevents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testResourceID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/acct"
+
+	testWriteEvent = `{
+	"specversion": "1.0",
+	"type": "Microsoft.Resources.ResourceWriteSuccess",
+	"source": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+	"subject": "` + testResourceID + `",
+	"id": "1",
+	"data": {
+		"resourceUri": "` + testResourceID + `",
+		"operationName": "Microsoft.Storage/storageAccounts/write",
+		"status": "Succeeded"
+	}
+}`
+
+	testActionEvent = `{
+	"specversion": "1.0",
+	"type": "Microsoft.Resources.ResourceActionSuccess",
+	"subject": "` + testResourceID + `",
+	"id": "2"
+}`
+)
+
+func newEventRequest(contentType string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/?key="+testKey, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func Test_ParseResourceChanges_StructuredEvent(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	req := newEventRequest("application/cloudevents+json; charset=utf-8", testWriteEvent)
+	changes, err := ParseResourceChanges(req, []byte(testWriteEvent))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changes).To(ConsistOf(ResourceChange{EventType: ResourceWriteSuccess, ResourceID: testResourceID}))
+}
+
+func Test_ParseResourceChanges_BatchOfEvents_IgnoresOtherEventTypes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	body := "[" + testWriteEvent + "," + testActionEvent + "]"
+	req := newEventRequest("application/cloudevents-batch+json", body)
+	changes, err := ParseResourceChanges(req, []byte(body))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changes).To(ConsistOf(ResourceChange{EventType: ResourceWriteSuccess, ResourceID: testResourceID}))
+}
+
+func Test_ParseResourceChanges_BinaryEvent(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	body := `{"status": "Succeeded"}`
+	req := newEventRequest("application/json", body)
+	req.Header.Set("ce-type", ResourceDeleteSuccess)
+	req.Header.Set("ce-subject", testResourceID)
+
+	changes, err := ParseResourceChanges(req, []byte(body))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changes).To(ConsistOf(ResourceChange{EventType: ResourceDeleteSuccess, ResourceID: testResourceID}))
+}
+
+func Test_ParseResourceChanges_InvalidJSON_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	req := newEventRequest("application/cloudevents+json", "{")
+	_, err := ParseResourceChanges(req, []byte("{"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package changeevents
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/rotisserie/eris"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+)
+
+const (
+	// maxRequestSize limits the size of the body of each request. Event Grid delivers batches of at most 1MB.
+	maxRequestSize = 1024 * 1024
+
+	// dispatchTimeout limits how long we wait to enqueue the resources in a single request
+	dispatchTimeout = 30 * time.Second
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// Server receives Azure resource change events over HTTP, and dispatches each changed resource for reconcile.
+// Events are expected to be CloudEvents, as delivered by an Event Grid subscription to a system topic for a
+// subscription or resource group using a webhook endpoint.
+type Server struct {
+	bindAddress string
+	key         string
+	dispatcher  *Dispatcher
+	log         logr.Logger
+}
+
+var (
+	_ manager.Runnable               = &Server{}
+	_ manager.LeaderElectionRunnable = &Server{}
+	_ http.Handler                   = &Server{}
+)
+
+// NewServer creates a new Server listening on bindAddress. The key must be supplied as the "key" query parameter of
+// every request. The server refuses to start without a key, as anyone able to reach it could otherwise trigger
+// reconciles at will.
+func NewServer(bindAddress string, key string, dispatcher *Dispatcher, log logr.Logger) *Server {
+	return &Server{
+		bindAddress: bindAddress,
+		key:         key,
+		dispatcher:  dispatcher,
+		log:         log,
+	}
+}
+
+// NeedLeaderElection returns true, as only the leader is reconciling resources.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// Start serves requests until the context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	if s.key == "" {
+		return eris.New("refusing to receive change events without a key")
+	}
+
+	server := &http.Server{
+		Addr:              s.bindAddress,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		//nolint:contextcheck // The original context has already been cancelled
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.log.Error(err, "Failed to shut down change event server")
+		}
+	}()
+
+	s.log.V(Status).Info("Listening for change events", "address", s.bindAddress)
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return eris.Wrap(err, "serving change events")
+	}
+
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !s.authorized(req) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodOptions:
+		s.validate(w, req)
+	case http.MethodPost:
+		s.receive(w, req)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// validate responds to the CloudEvents webhook validation handshake, which Event Grid performs before delivering
+// events to an endpoint.
+// See https://github.com/cloudevents/spec/blob/v1.0/http-webhook.md#4-abuse-protection
+func (s *Server) validate(w http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("WebHook-Request-Origin")
+	if origin == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("WebHook-Allowed-Origin", origin)
+	w.Header().Set("WebHook-Allowed-Rate", "*")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) receive(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	changes, err := ParseResourceChanges(req, body)
+	if err != nil {
+		s.log.V(Info).Info("Failed to parse change events", "error", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), dispatchTimeout)
+	defer cancel()
+
+	for _, change := range changes {
+		count, err := s.dispatcher.Dispatch(ctx, change.ResourceID)
+		if err != nil {
+			// Ask for the event to be delivered again later
+			s.log.Error(err, "Failed to dispatch change event", "id", change.ResourceID, "type", change.EventType)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		s.log.V(Verbose).Info(
+			"Received change event",
+			"id", change.ResourceID,
+			"type", change.EventType,
+			"resources", count)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authorized returns true if the request includes the key. Requests are never authorized if there's no key.
+func (s *Server) authorized(req *http.Request) bool {
+	if s.key == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(req.URL.Query().Get("key")), []byte(s.key)) == 1
+}
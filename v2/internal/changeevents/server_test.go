@@ -0,0 +1,140 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package changeevents
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20210401"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+var storageAccountGroupKind = schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccount"}
+
+const testKey = "secret"
+
+type fakeResolver struct {
+	resources map[string][]genruntime.ARMMetaObject
+}
+
+func (r fakeResolver) ResolveResourcesByARMID(_ context.Context, groupKind schema.GroupKind, armID string) ([]genruntime.ARMMetaObject, error) {
+	if groupKind != storageAccountGroupKind {
+		return nil, nil
+	}
+
+	return r.resources[strings.ToLower(armID)], nil
+}
+
+// newTestServer creates a server whose dispatcher sends events for storage accounts to the returned channel
+func newTestServer(key string) (*Server, chan event.GenericEvent) {
+	acct := &storage.StorageAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "acct",
+			Namespace: "default",
+		},
+	}
+
+	resolver := fakeResolver{
+		resources: map[string][]genruntime.ARMMetaObject{
+			strings.ToLower(testResourceID): {acct},
+		},
+	}
+
+	ch := make(chan event.GenericEvent, 10)
+	dispatcher := NewDispatcher(resolver, logr.Discard())
+	dispatcher.channels[storageAccountGroupKind] = ch
+
+	return NewServer(":0", key, dispatcher, logr.Discard()), ch
+}
+
+func Test_Server_ReceivesEvent_EnqueuesResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	server, ch := newTestServer(testKey)
+	req := newEventRequest("application/cloudevents+json", testWriteEvent)
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	g.Expect(resp.Code).To(Equal(http.StatusOK))
+	g.Expect(ch).To(HaveLen(1))
+	e := <-ch
+	g.Expect(e.Object.GetName()).To(Equal("acct"))
+}
+
+func Test_Server_ReceivesEventForUnknownResource_Succeeds(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	server, ch := newTestServer(testKey)
+	body := strings.ReplaceAll(testWriteEvent, "storageAccounts/acct", "storageAccounts/other")
+	req := newEventRequest("application/cloudevents+json", body)
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	g.Expect(resp.Code).To(Equal(http.StatusOK))
+	g.Expect(ch).To(BeEmpty())
+}
+
+func Test_Server_ValidationHandshake_AllowsOrigin(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	server, _ := newTestServer(testKey)
+	req := httptest.NewRequest(http.MethodOptions, "/?key="+testKey, nil)
+	req.Header.Set("WebHook-Request-Origin", "eventgrid.azure.net")
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	g.Expect(resp.Code).To(Equal(http.StatusOK))
+	g.Expect(resp.Header().Get("WebHook-Allowed-Origin")).To(Equal("eventgrid.azure.net"))
+}
+
+func Test_Server_RejectsRequestsWithoutKey(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	server, ch := newTestServer(testKey)
+
+	req := newEventRequest("application/cloudevents+json", testWriteEvent)
+	req.URL.RawQuery = ""
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+	g.Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+
+	req = newEventRequest("application/cloudevents+json", testWriteEvent)
+	req.URL.RawQuery = "key=wrong"
+	resp = httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+	g.Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+	g.Expect(ch).To(BeEmpty())
+}
+
+func Test_Server_WithoutKey_RejectsAllRequests(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	server, ch := newTestServer("")
+
+	req := newEventRequest("application/cloudevents+json", testWriteEvent)
+	req.URL.RawQuery = "key="
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+	g.Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+	g.Expect(ch).To(BeEmpty())
+
+	g.Expect(server.Start(context.Background())).To(MatchError(ContainSubstring("without a key")))
+}
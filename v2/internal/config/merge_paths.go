@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MergePath lists the JSON paths of the payload where properties set outside the operator are preserved, for the
+// resource types in a group, or for a single kind within a group.
+type MergePath struct {
+	// Group is the group of the resource types the paths apply to, e.g. storage.azure.com
+	Group string
+
+	// Kind is the kind of the resource type the paths apply to, e.g. StorageAccount. If empty, the paths apply to all
+	// kinds in Group.
+	Kind string
+
+	// Paths are dotted property names within the ARM payload, e.g. tags or properties.networkAcls.
+	Paths []string
+}
+
+// MergePaths is a set of JSON paths to merge for particular resource types.
+type MergePaths []MergePath
+
+// ParseMergePaths parses merge paths from a semicolon separated list of entries, each of the form
+// <group>[/<kind>]:<path>[,<path>...].
+// For example: "storage.azure.com/StorageAccount:tags,properties.networkAcls;keyvault.azure.com:tags"
+func ParseMergePaths(s string) (MergePaths, error) {
+	var result MergePaths
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		mergePath, err := parseMergePath(entry)
+		if err != nil {
+			return nil, eris.Wrapf(err, "parsing %q", entry)
+		}
+
+		result = append(result, mergePath)
+	}
+
+	return result, nil
+}
+
+func parseMergePath(entry string) (MergePath, error) {
+	groupKind, paths, ok := strings.Cut(entry, ":")
+	if !ok {
+		return MergePath{}, eris.New("expected <group>[/<kind>]:<path>[,<path>...]")
+	}
+
+	var result MergePath
+	result.Group, result.Kind, _ = strings.Cut(strings.TrimSpace(groupKind), "/")
+	if result.Group == "" {
+		return MergePath{}, eris.New("group must be specified")
+	}
+
+	result.Paths = ParseJSONPaths(paths)
+	if len(result.Paths) == 0 {
+		return MergePath{}, eris.New("at least one path must be specified")
+	}
+
+	return result, nil
+}
+
+// ParseJSONPaths parses a comma separated list of dotted JSON paths, ignoring empty entries.
+func ParseJSONPaths(s string) []string {
+	var result []string
+	for _, path := range strings.Split(s, ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			result = append(result, path)
+		}
+	}
+
+	return result
+}
+
+// For returns the paths to merge for the specified resource type, combining those specified for its group and kind.
+func (paths MergePaths) For(gk schema.GroupKind) []string {
+	var result []string
+	for _, mergePath := range paths {
+		if !strings.EqualFold(mergePath.Group, gk.Group) {
+			continue
+		}
+
+		if mergePath.Kind != "" && !strings.EqualFold(mergePath.Kind, gk.Kind) {
+			continue
+		}
+
+		result = append(result, mergePath.Paths...)
+	}
+
+	return result
+}
+
+func (paths MergePaths) String() string {
+	entries := make([]string, 0, len(paths))
+	for _, mergePath := range paths {
+		groupKind := mergePath.Group
+		if mergePath.Kind != "" {
+			groupKind += "/" + mergePath.Kind
+		}
+
+		entries = append(entries, groupKind+":"+strings.Join(mergePath.Paths, ","))
+	}
+
+	sort.Strings(entries)
+	return strings.Join(entries, "|")
+}
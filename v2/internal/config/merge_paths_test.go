@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+)
+
+func Test_ParseMergePaths_Valid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		expected config.MergePaths
+	}{
+		"Empty": {
+			value:    "",
+			expected: nil,
+		},
+		"Group only": {
+			value: "keyvault.azure.com:tags",
+			expected: config.MergePaths{
+				{Group: "keyvault.azure.com", Paths: []string{"tags"}},
+			},
+		},
+		"Multiple entries with whitespace": {
+			value: " storage.azure.com/StorageAccount: tags , properties.networkAcls ; keyvault.azure.com:tags; ",
+			expected: config.MergePaths{
+				{Group: "storage.azure.com", Kind: "StorageAccount", Paths: []string{"tags", "properties.networkAcls"}},
+				{Group: "keyvault.azure.com", Paths: []string{"tags"}},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			paths, err := config.ParseMergePaths(c.value)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(paths).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_ParseMergePaths_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Missing paths": "storage.azure.com",
+		"Empty paths":   "storage.azure.com: , ",
+		"Missing group": "/StorageAccount:tags",
+	}
+
+	for name, value := range cases {
+		value := value
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := config.ParseMergePaths(value)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func Test_MergePaths_For_CombinesGroupAndKind(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	paths, err := config.ParseMergePaths("storage.azure.com:tags;storage.azure.com/StorageAccount:properties.networkAcls;keyvault.azure.com:tags")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(paths.For(schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccount"})).
+		To(Equal([]string{"tags", "properties.networkAcls"}))
+	g.Expect(paths.For(schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccountsBlobService"})).
+		To(Equal([]string{"tags"}))
+	g.Expect(paths.For(schema.GroupKind{Group: "network.azure.com", Kind: "VirtualNetwork"})).
+		To(BeEmpty())
+}
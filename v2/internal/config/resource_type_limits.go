@@ -0,0 +1,188 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceTypeLimit overrides the concurrency and rate limits for the resource types in a group, or for a single
+// kind within a group.
+type ResourceTypeLimit struct {
+	// Group is the group of the resource types the limit applies to, e.g. network.azure.com
+	Group string
+
+	// Kind is the kind of the resource type the limit applies to, e.g. PrivateDnsZonesARecord. If empty, the limit
+	// applies to all kinds in Group.
+	Kind string
+
+	// MaxConcurrentReconciles overrides the number of goroutines dedicated to reconciling resources of the type.
+	// Zero means the operator-wide MaxConcurrentReconciles applies.
+	MaxConcurrentReconciles int
+
+	// QPS is the rate (per second) that the token bucket for the type is refilled. Zero means the type has no
+	// token bucket of its own.
+	QPS float64
+
+	// BucketSize is the size of the token bucket for the type. Zero means the operator-wide bucket size applies.
+	// This value only has an effect if QPS is set.
+	BucketSize int
+}
+
+// ResourceTypeLimits is a set of overrides to the concurrency and rate limits of particular resource types.
+type ResourceTypeLimits []ResourceTypeLimit
+
+const (
+	limitKeyMaxConcurrentReconciles = "maxConcurrentReconciles"
+	limitKeyQPS                     = "qps"
+	limitKeyBucketSize              = "bucketSize"
+)
+
+// ParseResourceTypeLimits parses resource type limits from a semicolon separated list of entries, each of the form
+// <group>[/<kind>]:<key>=<value>[,<key>=<value>...], where the keys are maxConcurrentReconciles, qps and bucketSize.
+// For example: "network.azure.com/PrivateDnsZonesARecord:maxConcurrentReconciles=10,qps=20;containerservice.azure.com:maxConcurrentReconciles=1"
+func ParseResourceTypeLimits(s string) (ResourceTypeLimits, error) {
+	var result ResourceTypeLimits
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		limit, err := parseResourceTypeLimit(entry)
+		if err != nil {
+			return nil, eris.Wrapf(err, "parsing %q", entry)
+		}
+
+		result = append(result, limit)
+	}
+
+	return result, nil
+}
+
+func parseResourceTypeLimit(entry string) (ResourceTypeLimit, error) {
+	groupKind, settings, ok := strings.Cut(entry, ":")
+	if !ok {
+		return ResourceTypeLimit{}, eris.New("expected <group>[/<kind>]:<key>=<value>")
+	}
+
+	var result ResourceTypeLimit
+	result.Group, result.Kind, _ = strings.Cut(strings.TrimSpace(groupKind), "/")
+	if result.Group == "" {
+		return ResourceTypeLimit{}, eris.New("group must be specified")
+	}
+
+	for _, setting := range strings.Split(settings, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(setting), "=")
+		if !ok {
+			return ResourceTypeLimit{}, eris.Errorf("expected <key>=<value> but got %q", setting)
+		}
+
+		var err error
+		switch key {
+		case limitKeyMaxConcurrentReconciles:
+			result.MaxConcurrentReconciles, err = strconv.Atoi(value)
+			if err == nil && result.MaxConcurrentReconciles <= 0 {
+				err = eris.New("must be at least 1")
+			}
+		case limitKeyQPS:
+			result.QPS, err = strconv.ParseFloat(value, 64)
+			if err == nil && result.QPS <= 0 {
+				err = eris.New("must be positive")
+			}
+		case limitKeyBucketSize:
+			result.BucketSize, err = strconv.Atoi(value)
+			if err == nil && result.BucketSize <= 0 {
+				err = eris.New("must be at least 1")
+			}
+		default:
+			err = eris.Errorf(
+				"unknown key, expected one of %s, %s or %s",
+				limitKeyMaxConcurrentReconciles,
+				limitKeyQPS,
+				limitKeyBucketSize)
+		}
+
+		if err != nil {
+			return ResourceTypeLimit{}, eris.Wrapf(err, "invalid %s %q", key, value)
+		}
+	}
+
+	return result, nil
+}
+
+// For returns the limits that apply to the specified resource type. Limits specified for the kind take precedence over
+// those specified for its group. Any field left as zero means the operator-wide default applies.
+func (limits ResourceTypeLimits) For(gk schema.GroupKind) ResourceTypeLimit {
+	result := ResourceTypeLimit{
+		Group: gk.Group,
+		Kind:  gk.Kind,
+	}
+
+	// Apply group limits first, so that kind limits override them
+	for _, matchKind := range []bool{false, true} {
+		for _, limit := range limits {
+			if !strings.EqualFold(limit.Group, gk.Group) {
+				continue
+			}
+
+			if matchKind != (limit.Kind != "") {
+				continue
+			}
+
+			if matchKind && !strings.EqualFold(limit.Kind, gk.Kind) {
+				continue
+			}
+
+			result.merge(limit)
+		}
+	}
+
+	return result
+}
+
+func (l *ResourceTypeLimit) merge(other ResourceTypeLimit) {
+	if other.MaxConcurrentReconciles != 0 {
+		l.MaxConcurrentReconciles = other.MaxConcurrentReconciles
+	}
+
+	if other.QPS != 0 {
+		l.QPS = other.QPS
+	}
+
+	if other.BucketSize != 0 {
+		l.BucketSize = other.BucketSize
+	}
+}
+
+func (limits ResourceTypeLimits) String() string {
+	var builder strings.Builder
+	for i, limit := range limits {
+		if i > 0 {
+			builder.WriteString("|")
+		}
+
+		builder.WriteString(limit.Group)
+		if limit.Kind != "" {
+			builder.WriteString(fmt.Sprintf("/%s", limit.Kind))
+		}
+
+		builder.WriteString(
+			fmt.Sprintf(
+				":%s=%d,%s=%f,%s=%d",
+				limitKeyMaxConcurrentReconciles,
+				limit.MaxConcurrentReconciles,
+				limitKeyQPS,
+				limit.QPS,
+				limitKeyBucketSize,
+				limit.BucketSize))
+	}
+
+	return builder.String()
+}
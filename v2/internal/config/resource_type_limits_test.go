@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+)
+
+func Test_ParseResourceTypeLimits_Valid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		expected config.ResourceTypeLimits
+	}{
+		"Empty": {
+			value:    "",
+			expected: nil,
+		},
+		"Group only": {
+			value: "containerservice.azure.com:maxConcurrentReconciles=2",
+			expected: config.ResourceTypeLimits{
+				{Group: "containerservice.azure.com", MaxConcurrentReconciles: 2},
+			},
+		},
+		"Kind with all settings": {
+			value: "network.azure.com/PrivateDnsZonesARecord:maxConcurrentReconciles=10,qps=2.5,bucketSize=50",
+			expected: config.ResourceTypeLimits{
+				{Group: "network.azure.com", Kind: "PrivateDnsZonesARecord", MaxConcurrentReconciles: 10, QPS: 2.5, BucketSize: 50},
+			},
+		},
+		"Multiple entries with whitespace": {
+			value: " network.azure.com:qps=5 ; sql.azure.com/Server:maxConcurrentReconciles=3; ",
+			expected: config.ResourceTypeLimits{
+				{Group: "network.azure.com", QPS: 5},
+				{Group: "sql.azure.com", Kind: "Server", MaxConcurrentReconciles: 3},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			limits, err := config.ParseResourceTypeLimits(c.value)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(limits).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_ParseResourceTypeLimits_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"No settings":           "network.azure.com",
+		"No group":              "/PrivateDnsZonesARecord:qps=1",
+		"Unknown key":           "network.azure.com:burst=1",
+		"Missing value":         "network.azure.com:qps",
+		"Zero concurrency":      "network.azure.com:maxConcurrentReconciles=0",
+		"Negative qps":          "network.azure.com:qps=-1",
+		"Non-numeric bucket":    "network.azure.com:bucketSize=lots",
+		"Fractional concurrent": "network.azure.com:maxConcurrentReconciles=1.5",
+	}
+
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := config.ParseResourceTypeLimits(value)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func Test_ResourceTypeLimits_For_KindOverridesGroup(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	limits, err := config.ParseResourceTypeLimits(
+		"network.azure.com/PrivateDnsZonesARecord:maxConcurrentReconciles=10;network.azure.com:maxConcurrentReconciles=2,qps=5")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	record := limits.For(schema.GroupKind{Group: "network.azure.com", Kind: "PrivateDnsZonesARecord"})
+	g.Expect(record.MaxConcurrentReconciles).To(Equal(10))
+	g.Expect(record.QPS).To(Equal(5.0))
+
+	vnet := limits.For(schema.GroupKind{Group: "network.azure.com", Kind: "VirtualNetwork"})
+	g.Expect(vnet.MaxConcurrentReconciles).To(Equal(2))
+	g.Expect(vnet.QPS).To(Equal(5.0))
+}
+
+func Test_ResourceTypeLimits_For_NoMatch(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	limits, err := config.ParseResourceTypeLimits("network.azure.com:maxConcurrentReconciles=2")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	limit := limits.For(schema.GroupKind{Group: "resources.azure.com", Kind: "ResourceGroup"})
+	g.Expect(limit.MaxConcurrentReconciles).To(Equal(0))
+	g.Expect(limit.QPS).To(Equal(0.0))
+	g.Expect(limit.BucketSize).To(Equal(0))
+}
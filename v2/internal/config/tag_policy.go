@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+type TagCollisionRule string
+
+const (
+	// TagCollisionRuleSpecWins keeps the value of a tag set in the spec of a resource when the policy also sets it.
+	TagCollisionRuleSpecWins = TagCollisionRule("spec-wins")
+	// TagCollisionRulePolicyWins replaces the value of a tag set in the spec of a resource when the policy also sets it.
+	TagCollisionRulePolicyWins = TagCollisionRule("policy-wins")
+)
+
+func ParseTagCollisionRule(s string) (TagCollisionRule, error) {
+	switch s {
+	case string(TagCollisionRuleSpecWins):
+		return TagCollisionRuleSpecWins, nil
+	case string(TagCollisionRulePolicyWins):
+		return TagCollisionRulePolicyWins, nil
+	default:
+		return "", eris.Errorf("invalid tag collision rule %q", s)
+	}
+}
+
+// TagPolicy configures the tags merged into the payload of every resource sent to Azure.
+type TagPolicy struct {
+	// Tags are applied to every resource, e.g. {"managed-by": "aso"}.
+	Tags map[string]string
+
+	// NamespaceLabels maps tag names to the namespace label their value is taken from, e.g.
+	// {"cost-center": "finops.contoso.com/cost-center"}. Namespaces without the label don't get the tag.
+	NamespaceLabels map[string]string
+
+	// Collision determines which value is used when a tag is set by both the policy and the spec of a resource.
+	// Valid values are [spec-wins, policy-wins]
+	Collision TagCollisionRule
+}
+
+// ParseTags parses tags from a semicolon separated list of entries, each of the form <key>=<value>.
+// Values may contain '=', keys may not. For example: "managed-by=aso;environment=prod"
+func ParseTags(s string) (map[string]string, error) {
+	var result map[string]string
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, eris.Errorf("parsing %q: expected <key>=<value>", entry)
+		}
+
+		if result == nil {
+			result = make(map[string]string)
+		}
+
+		result[key] = strings.TrimSpace(value)
+	}
+
+	return result, nil
+}
+
+func (p TagPolicy) String() string {
+	return fmt.Sprintf(
+		"Tags:%s/NamespaceLabels:%s/Collision:%s",
+		formatTags(p.Tags),
+		formatTags(p.NamespaceLabels),
+		p.Collision)
+}
+
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	entries := make([]string, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, fmt.Sprintf("%s=%s", key, tags[key]))
+	}
+
+	return strings.Join(entries, "|")
+}
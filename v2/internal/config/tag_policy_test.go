@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+)
+
+func Test_ParseTags_Valid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		expected map[string]string
+	}{
+		"Empty": {
+			value:    "",
+			expected: nil,
+		},
+		"Single tag": {
+			value:    "managed-by=aso",
+			expected: map[string]string{"managed-by": "aso"},
+		},
+		"Multiple tags with whitespace": {
+			value:    " managed-by = aso ; environment=prod; ",
+			expected: map[string]string{"managed-by": "aso", "environment": "prod"},
+		},
+		"Value containing equals": {
+			value:    "query=a=b",
+			expected: map[string]string{"query": "a=b"},
+		},
+		"Empty value": {
+			value:    "owner=",
+			expected: map[string]string{"owner": ""},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			tags, err := config.ParseTags(c.value)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tags).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_ParseTags_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Missing equals": "managed-by",
+		"Missing key":    "=aso",
+	}
+
+	for name, value := range cases {
+		value := value
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := config.ParseTags(value)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func Test_ParseTagCollisionRule(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	rule, err := config.ParseTagCollisionRule("policy-wins")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rule).To(Equal(config.TagCollisionRulePolicyWins))
+
+	_, err = config.ParseTagCollisionRule("mine")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -105,6 +105,35 @@ type Values struct {
 	// DefaultReconcilePolicy allows to override the default reconcile policy that should be used by ASO
 	// when the annotation serviceoperator.azure.com/reconcile-policy is omitted
 	DefaultReconcilePolicy annotations.ReconcilePolicyValue
+
+	// EnableDriftDetection instructs the operator to GET each resource from Azure before issuing a PUT, and to skip
+	// the PUT if the resource already matches the desired state. Differences are reported via the DriftDetected
+	// condition. This trades an extra GET for each PUT in exchange for not issuing no-op PUTs on every resync.
+	EnableDriftDetection bool
+
+	// ResourceTypeLimits overrides MaxConcurrentReconciles, and adds a token-bucket rate limit, for particular groups
+	// or kinds of resource.
+	ResourceTypeLimits ResourceTypeLimits
+
+	// EnablePriorityQueue instructs the operator to reconcile new and changed resources ahead of deletes, deletes ahead
+	// of polling long-running operations, and polling ahead of periodic resyncs.
+	EnablePriorityQueue bool
+
+	StatusRefresh StatusRefresh
+
+	ChangeEvents ChangeEvents
+
+	AuditLog AuditLog
+
+	// OperationHistoryLength is the number of recent ARM operations recorded in the OperationHistory of each resource.
+	// Zero (the default) disables recording.
+	OperationHistoryLength int
+
+	TagPolicy TagPolicy
+
+	// MergePaths gives the JSON paths of the ARM payload where properties set outside the operator (for example by
+	// Azure Policy) are preserved, for particular groups or kinds of resource.
+	MergePaths MergePaths
 }
 
 type RateLimitMode string
@@ -165,6 +194,131 @@ func (r RateLimit) String() string {
 	return builder.String()
 }
 
+type StatusRefreshMode string
+
+const (
+	StatusRefreshModeGet           = StatusRefreshMode("get")
+	StatusRefreshModeResourceGraph = StatusRefreshMode("resourcegraph")
+)
+
+func ParseStatusRefreshMode(s string) (StatusRefreshMode, error) {
+	switch s {
+	case string(StatusRefreshModeGet):
+		return StatusRefreshModeGet, nil
+	case string(StatusRefreshModeResourceGraph):
+		return StatusRefreshModeResourceGraph, nil
+	default:
+		return "", eris.Errorf("invalid status refresh mode %q", s)
+	}
+}
+
+type StatusRefresh struct {
+	// Mode configures how the status of resources is refreshed from Azure.
+	// Valid values are [get, resourcegraph]
+	// * get: Each resource is refreshed with a GET of the resource.
+	// * resourcegraph: Resources are refreshed in batches, using Azure Resource Graph queries grouped by
+	//   subscription and resource type. Resources not yet in the batch results (or whose results are out of date)
+	//   fall back to a GET.
+	Mode StatusRefreshMode
+
+	// Interval is how often batched status refreshes are performed. This value only has an effect if Mode is
+	// 'resourcegraph'.
+	Interval time.Duration
+}
+
+func (r StatusRefresh) String() string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Mode:%s", r.Mode))
+
+	// Interval only matters when refreshing in batches
+	if r.Mode == StatusRefreshModeResourceGraph {
+		builder.WriteString(fmt.Sprintf("/Interval:%s", r.Interval))
+	}
+
+	return builder.String()
+}
+
+// ChangeEvents configures receiving Azure resource change events.
+type ChangeEvents struct {
+	// BindAddress is the address on which change events are received. If empty, change events are not received.
+	BindAddress string
+
+	// Key must be supplied with each request delivering change events. Required if BindAddress is set.
+	Key string
+}
+
+// Enabled returns true if the operator should receive change events.
+func (e ChangeEvents) Enabled() bool {
+	return e.BindAddress != ""
+}
+
+func (e ChangeEvents) String() string {
+	// Don't leak the key
+	return fmt.Sprintf("BindAddress:%s/HasKey:%t", e.BindAddress, e.Key != "")
+}
+
+type AuditLogSink string
+
+const (
+	AuditLogSinkNone    = AuditLogSink("none")
+	AuditLogSinkStdout  = AuditLogSink("stdout")
+	AuditLogSinkFile    = AuditLogSink("file")
+	AuditLogSinkWebhook = AuditLogSink("webhook")
+)
+
+func ParseAuditLogSink(s string) (AuditLogSink, error) {
+	switch s {
+	case string(AuditLogSinkNone):
+		return AuditLogSinkNone, nil
+	case string(AuditLogSinkStdout):
+		return AuditLogSinkStdout, nil
+	case string(AuditLogSinkFile):
+		return AuditLogSinkFile, nil
+	case string(AuditLogSinkWebhook):
+		return AuditLogSinkWebhook, nil
+	default:
+		return "", eris.Errorf("invalid audit log sink %q", s)
+	}
+}
+
+// AuditLog configures the audit log of requests that modify resources in Azure.
+type AuditLog struct {
+	// Sink configures where audit records are written.
+	// Valid values are [none, stdout, file, webhook]
+	Sink AuditLogSink
+
+	// File is the path of the file audit records are written to. This value only has an effect if Sink is 'file'.
+	File string
+
+	// FileMaxSize is the size (in megabytes) at which File is rotated. This value only has an effect if Sink is 'file'.
+	FileMaxSize int
+
+	// FileMaxBackups is the number of rotated files to keep. This value only has an effect if Sink is 'file'.
+	FileMaxBackups int
+
+	// WebhookURL is the URL audit records are POSTed to. This value only has an effect if Sink is 'webhook'.
+	WebhookURL string
+}
+
+func (a AuditLog) String() string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Sink:%s", a.Sink))
+
+	switch a.Sink {
+	case AuditLogSinkFile:
+		builder.WriteString(fmt.Sprintf("/File:%s/", a.File))
+		builder.WriteString(fmt.Sprintf("FileMaxSize:%d/", a.FileMaxSize))
+		builder.WriteString(fmt.Sprintf("FileMaxBackups:%d", a.FileMaxBackups))
+	case AuditLogSinkWebhook:
+		// Don't log the URL, it may contain credentials
+		builder.WriteString(fmt.Sprintf("/HasWebhookURL:%t", a.WebhookURL != ""))
+	}
+
+	return builder.String()
+}
+
 var _ fmt.Stringer = Values{}
 
 // Returns the configuration as a string
@@ -185,7 +339,16 @@ func (v Values) String() string {
 	builder.WriteString(fmt.Sprintf("UserAgentSuffix:%s/", v.UserAgentSuffix))
 	builder.WriteString(fmt.Sprintf("MaxConcurrentReconciles:%d/", v.MaxConcurrentReconciles))
 	builder.WriteString(fmt.Sprintf("RateLimit:[%s]", v.RateLimit.String()))
-	builder.WriteString(fmt.Sprintf("DefaultReconcilePolicy:[%s]", v.DefaultReconcilePolicy))
+	builder.WriteString(fmt.Sprintf("DefaultReconcilePolicy:[%s]/", v.DefaultReconcilePolicy))
+	builder.WriteString(fmt.Sprintf("EnableDriftDetection:%t/", v.EnableDriftDetection))
+	builder.WriteString(fmt.Sprintf("ResourceTypeLimits:[%s]/", v.ResourceTypeLimits.String()))
+	builder.WriteString(fmt.Sprintf("EnablePriorityQueue:%t/", v.EnablePriorityQueue))
+	builder.WriteString(fmt.Sprintf("StatusRefresh:[%s]/", v.StatusRefresh.String()))
+	builder.WriteString(fmt.Sprintf("ChangeEvents:[%s]/", v.ChangeEvents.String()))
+	builder.WriteString(fmt.Sprintf("AuditLog:[%s]/", v.AuditLog.String()))
+	builder.WriteString(fmt.Sprintf("OperationHistoryLength:%d/", v.OperationHistoryLength))
+	builder.WriteString(fmt.Sprintf("TagPolicy:[%s]/", v.TagPolicy.String()))
+	builder.WriteString(fmt.Sprintf("MergePaths:[%s]", v.MergePaths.String()))
 
 	return builder.String()
 }
@@ -253,6 +416,58 @@ func ReadFromEnvironment() (Values, error) {
 		return result, err
 	}
 	result.DefaultReconcilePolicy = annotations.ReconcilePolicyValue(envOrDefault(config.DefaultReconcilePolicy, string(annotations.ReconcilePolicyManage)))
+	// Ignoring error here, as any other value or empty value means we should default to false
+	result.EnableDriftDetection, _ = strconv.ParseBool(os.Getenv(config.EnableDriftDetection))
+	result.ResourceTypeLimits, err = ParseResourceTypeLimits(os.Getenv(config.ResourceTypeLimits))
+	if err != nil {
+		return result, eris.Wrapf(err, "parsing %q", config.ResourceTypeLimits)
+	}
+	// Ignoring error here, as any other value or empty value means we should default to false
+	result.EnablePriorityQueue, _ = strconv.ParseBool(os.Getenv(config.EnablePriorityQueue))
+	result.StatusRefresh.Mode, err = ParseStatusRefreshMode(envOrDefault(config.StatusRefreshMode, string(StatusRefreshModeGet)))
+	if err != nil {
+		return result, err
+	}
+	result.StatusRefresh.Interval, err = time.ParseDuration(envOrDefault(config.StatusRefreshInterval, "5m"))
+	if err != nil {
+		return result, eris.Wrapf(err, "parsing %q", config.StatusRefreshInterval)
+	}
+	result.ChangeEvents.BindAddress = os.Getenv(config.ChangeEventsBindAddress)
+	result.ChangeEvents.Key = os.Getenv(config.ChangeEventsKey)
+	result.AuditLog.Sink, err = ParseAuditLogSink(envOrDefault(config.AuditLogSink, string(AuditLogSinkNone)))
+	if err != nil {
+		return result, err
+	}
+	result.AuditLog.File = os.Getenv(config.AuditLogFile)
+	result.AuditLog.FileMaxSize, err = envParseOrDefault(config.AuditLogFileMaxSize, 100)
+	if err != nil {
+		return result, err
+	}
+	result.AuditLog.FileMaxBackups, err = envParseOrDefault(config.AuditLogFileMaxBackups, 5)
+	if err != nil {
+		return result, err
+	}
+	result.AuditLog.WebhookURL = os.Getenv(config.AuditLogWebhookURL)
+	result.OperationHistoryLength, err = envParseOrDefault(config.OperationHistoryLength, 0)
+	if err != nil {
+		return result, err
+	}
+	result.TagPolicy.Tags, err = ParseTags(os.Getenv(config.TagPolicyTags))
+	if err != nil {
+		return result, eris.Wrapf(err, "parsing %q", config.TagPolicyTags)
+	}
+	result.TagPolicy.NamespaceLabels, err = ParseTags(os.Getenv(config.TagPolicyNamespaceLabels))
+	if err != nil {
+		return result, eris.Wrapf(err, "parsing %q", config.TagPolicyNamespaceLabels)
+	}
+	result.TagPolicy.Collision, err = ParseTagCollisionRule(envOrDefault(config.TagPolicyCollision, string(TagCollisionRuleSpecWins)))
+	if err != nil {
+		return result, err
+	}
+	result.MergePaths, err = ParseMergePaths(os.Getenv(config.MergePaths))
+	if err != nil {
+		return result, eris.Wrapf(err, "parsing %q", config.MergePaths)
+	}
 
 	// Not calling validate here to support using from tests where we
 	// don't require consistent settings.
@@ -284,23 +499,71 @@ func (v Values) Validate() error {
 	if v.MaxConcurrentReconciles <= 0 {
 		return eris.Errorf("%s must be at least 1", config.MaxConcurrentReconciles)
 	}
-	if v.DefaultReconcilePolicy != annotations.ReconcilePolicyDetachOnDelete && v.DefaultReconcilePolicy != annotations.ReconcilePolicyManage && v.DefaultReconcilePolicy != annotations.ReconcilePolicySkip {
-		return eris.Errorf("%s must be set to any of (%s, %s, %s)", config.DefaultReconcilePolicy, annotations.ReconcilePolicyDetachOnDelete, annotations.ReconcilePolicyManage, annotations.ReconcilePolicySkip)
+	if v.StatusRefresh.Mode == StatusRefreshModeResourceGraph && v.StatusRefresh.Interval <= 0 {
+		return eris.Errorf("%s must be positive", config.StatusRefreshInterval)
+	}
+	if v.ChangeEvents.Enabled() && !v.EnableDriftDetection {
+		// Otherwise every reconcile triggered by a change event would PUT the resource, raising another change event
+		return eris.Errorf("%s requires %s to be enabled", config.ChangeEventsBindAddress, config.EnableDriftDetection)
+	}
+	if v.ChangeEvents.Enabled() && v.ChangeEvents.Key == "" {
+		return eris.Errorf("missing value for %s", config.ChangeEventsKey)
+	}
+	if v.AuditLog.Sink == AuditLogSinkFile {
+		if v.AuditLog.File == "" {
+			return eris.Errorf("missing value for %s", config.AuditLogFile)
+		}
+		if v.AuditLog.FileMaxSize <= 0 {
+			return eris.Errorf("%s must be at least 1", config.AuditLogFileMaxSize)
+		}
+		if v.AuditLog.FileMaxBackups < 0 {
+			return eris.Errorf("%s must not be negative", config.AuditLogFileMaxBackups)
+		}
+	}
+	if v.AuditLog.Sink == AuditLogSinkWebhook && v.AuditLog.WebhookURL == "" {
+		return eris.Errorf("missing value for %s", config.AuditLogWebhookURL)
+	}
+	if v.OperationHistoryLength < 0 {
+		return eris.Errorf("%s must not be negative", config.OperationHistoryLength)
+	}
+	if v.DefaultReconcilePolicy != annotations.ReconcilePolicyDetachOnDelete &&
+		v.DefaultReconcilePolicy != annotations.ReconcilePolicyManage &&
+		v.DefaultReconcilePolicy != annotations.ReconcilePolicySkip &&
+		v.DefaultReconcilePolicy != annotations.ReconcilePolicyObserve &&
+		v.DefaultReconcilePolicy != annotations.ReconcilePolicyPlan {
+		return eris.Errorf(
+			"%s must be set to any of (%s, %s, %s, %s, %s)",
+			config.DefaultReconcilePolicy,
+			annotations.ReconcilePolicyDetachOnDelete,
+			annotations.ReconcilePolicyManage,
+			annotations.ReconcilePolicySkip,
+			annotations.ReconcilePolicyObserve,
+			annotations.ReconcilePolicyPlan)
 	}
 	return nil
 }
 
 // parseSyncPeriod parses the sync period from the environment
 func parseSyncPeriod() (*time.Duration, error) {
-	syncPeriodStr := envOrDefault(config.SyncPeriod, "1h")
-	if syncPeriodStr == "never" { // magical string that means no sync
+	return ParseSyncPeriod(envOrDefault(config.SyncPeriod, "1h"))
+}
+
+// ParseSyncPeriod parses the provided sync period, which is either a positive duration or "never".
+// A nil sync period means resources should not be periodically re-synced.
+func ParseSyncPeriod(value string) (*time.Duration, error) {
+	if value == "never" { // magical string that means no sync
 		return nil, nil
 	}
 
-	syncPeriod, err := time.ParseDuration(syncPeriodStr)
+	syncPeriod, err := time.ParseDuration(value)
 	if err != nil {
 		return nil, err
 	}
+
+	if syncPeriod <= 0 {
+		return nil, eris.Errorf("sync period %q must be positive", value)
+	}
+
 	return &syncPeriod, nil
 }
 
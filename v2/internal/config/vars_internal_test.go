@@ -43,3 +43,22 @@ func Test_ParseSyncPeriod_ReturnsValue(t *testing.T) {
 	g.Expect(dur).ToNot(BeNil())
 	g.Expect(*dur).To(Equal(21 * time.Minute))
 }
+
+func Test_ParseSyncPeriod_ParsesValues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	syncPeriod, err := ParseSyncPeriod("5m")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(*syncPeriod).To(Equal(5 * time.Minute))
+
+	syncPeriod, err = ParseSyncPeriod("never")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(syncPeriod).To(BeNil())
+
+	_, err = ParseSyncPeriod("daily")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseSyncPeriod("0s")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -119,7 +119,7 @@ func TestValidate(t *testing.T) {
 		DefaultReconcilePolicy:  "detach",
 	}
 
-	g.Expect(vKoDefaultReconcilePolicy.Validate().Error()).Error().Should(Equal("DEFAULT_RECONCILE_POLICY must be set to any of (detach-on-delete, manage, skip)"))
+	g.Expect(vKoDefaultReconcilePolicy.Validate().Error()).Error().Should(Equal("DEFAULT_RECONCILE_POLICY must be set to any of (detach-on-delete, manage, skip, observe, plan)"))
 
 	vKoOperatorMode := config.Values{
 		PodNamespace:            "test-namespace",
@@ -130,4 +130,39 @@ func TestValidate(t *testing.T) {
 	}
 
 	g.Expect(vKoOperatorMode.Validate().Error()).Error().Should(Equal("AZURE_TARGET_NAMESPACES must include watchers to specify target namespaces"))
+
+	vKoAuditLogFile := config.Values{
+		PodNamespace:            "test-namespace",
+		MaxConcurrentReconciles: 1,
+		DefaultReconcilePolicy:  "detach-on-delete",
+		AuditLog: config.AuditLog{
+			Sink:        config.AuditLogSinkFile,
+			FileMaxSize: 100,
+		},
+	}
+
+	g.Expect(vKoAuditLogFile.Validate().Error()).Error().Should(Equal("missing value for AUDIT_LOG_FILE"))
+
+	vKoAuditLogWebhook := config.Values{
+		PodNamespace:            "test-namespace",
+		MaxConcurrentReconciles: 1,
+		DefaultReconcilePolicy:  "detach-on-delete",
+		AuditLog: config.AuditLog{
+			Sink: config.AuditLogSinkWebhook,
+		},
+	}
+
+	g.Expect(vKoAuditLogWebhook.Validate().Error()).Error().Should(Equal("missing value for AUDIT_LOG_WEBHOOK_URL"))
+
+	vKoChangeEventsKey := config.Values{
+		PodNamespace:            "test-namespace",
+		MaxConcurrentReconciles: 1,
+		DefaultReconcilePolicy:  "detach-on-delete",
+		EnableDriftDetection:    true,
+		ChangeEvents: config.ChangeEvents{
+			BindAddress: ":8083",
+		},
+	}
+
+	g.Expect(vKoChangeEventsKey.Validate().Error()).Error().Should(Equal("missing value for CHANGE_EVENTS_KEY"))
 }
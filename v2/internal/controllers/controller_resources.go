@@ -34,6 +34,7 @@ import (
 	postgresqlv1webhook "github.com/Azure/azure-service-operator/v2/api/dbforpostgresql/v1/webhook"
 	entrav1 "github.com/Azure/azure-service-operator/v2/api/entra/v1"
 	entrav1webhook "github.com/Azure/azure-service-operator/v2/api/entra/v1/webhook"
+	serviceoperatorv1 "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
 	azuresqlv1 "github.com/Azure/azure-service-operator/v2/api/sql/v1"
 	azuresqlv1webhook "github.com/Azure/azure-service-operator/v2/api/sql/v1/webhook"
 	"github.com/Azure/azure-service-operator/v2/internal/identity"
@@ -61,6 +62,8 @@ type ClientsProvider struct {
 	KubeClient             kubeclient.Client
 	ARMConnectionFactory   arm.ARMConnectionFactory
 	EntraConnectionFactory entrareconciler.EntraConnectionFactory
+	// Resolver is the resolver shared by the reconcilers. If nil, a new one is created.
+	Resolver *resolver.Resolver
 }
 
 func GetKnownStorageTypes(
@@ -71,7 +74,11 @@ func GetKnownStorageTypes(
 	expressionEvaluator asocel.ExpressionEvaluator,
 	options generic.Options,
 ) ([]*registration.StorageType, error) {
-	resourceResolver := resolver.NewResolver(clients.KubeClient)
+	resourceResolver := clients.Resolver
+	if resourceResolver == nil {
+		resourceResolver = resolver.NewResolver(clients.KubeClient)
+	}
+
 	knownStorageTypes, err := getGeneratedStorageTypes(
 		schemer,
 		clients.ARMConnectionFactory,
@@ -190,6 +197,66 @@ func GetKnownStorageTypes(
 			Watches:   []registration.Watch{},
 		})
 
+	// serviceoperator
+	armResource := &registration.StorageType{
+		Obj:  &serviceoperatorv1.ARMResource{},
+		Name: "serviceoperator_armresource",
+		Reconciler: newAzureDeploymentReconciler(
+			clients.ARMConnectionFactory,
+			clients.KubeClient,
+			resourceResolver,
+			positiveConditions,
+			expressionEvaluator,
+			options,
+			nil),
+		Predicate: makeStandardPredicate(),
+		Indexes:   []registration.Index{resolver.DeletionProtectedIndex()},
+		Watches:   []registration.Watch{},
+	}
+
+	// Allows ARMResources to be found by their ARM ID when they're changed in Azure
+	if options.Config.ChangeEvents.Enabled() {
+		armResource.Indexes = append(armResource.Indexes, resolver.ARMIDIndex())
+	}
+
+	// ARMResources may own each other, so must be known to the resolver
+	err = resourceResolver.IndexStorageTypes(schemer.GetScheme(), []*registration.StorageType{armResource})
+	if err != nil {
+		return nil, eris.Wrap(err, "failed add ARMResource to resource resolver")
+	}
+
+	knownStorageTypes = append(
+		knownStorageTypes,
+		armResource,
+		&registration.StorageType{
+			Obj:  &serviceoperatorv1.ResourceLookup{},
+			Name: "serviceoperator_resourcelookup",
+			Reconciler: newAzureDeploymentReconciler(
+				clients.ARMConnectionFactory,
+				clients.KubeClient,
+				resourceResolver,
+				positiveConditions,
+				expressionEvaluator,
+				options,
+				nil),
+			Predicate: makeStandardPredicate(),
+			Indexes:   []registration.Index{},
+			Watches:   []registration.Watch{},
+		},
+		&registration.StorageType{
+			Obj:  &serviceoperatorv1.ARMAction{},
+			Name: "serviceoperator_armaction",
+			Reconciler: arm.NewARMActionReconciler(
+				clients.ARMConnectionFactory,
+				clients.KubeClient,
+				resourceResolver,
+				positiveConditions,
+				expressionEvaluator),
+			Predicate: makeStandardPredicate(),
+			Indexes:   []registration.Index{},
+			Watches:   []registration.Watch{},
+		})
+
 	return knownStorageTypes, nil
 }
 
@@ -224,6 +291,14 @@ func getGeneratedStorageTypes(
 		}
 		extension := extensions[gvk]
 
+		// Allows resources to be found by their ARM ID when they're changed in Azure
+		if options.Config.ChangeEvents.Enabled() {
+			t.Indexes = append(t.Indexes, resolver.ARMIDIndex())
+		}
+
+		// Allows the resources protected from deletion to be found by the resources containing them in Azure
+		t.Indexes = append(t.Indexes, resolver.DeletionProtectedIndex())
+
 		augmentWithARMReconciler(
 			armConnectionFactory,
 			kubeClient,
@@ -248,7 +323,28 @@ func augmentWithARMReconciler(
 	extension genruntime.ResourceExtension,
 	t *registration.StorageType,
 ) {
-	t.Reconciler = arm.NewAzureDeploymentReconciler(
+	t.Reconciler = newAzureDeploymentReconciler(
+		armConnectionFactory,
+		kubeClient,
+		resourceResolver,
+		positiveConditions,
+		expressionEvaluator,
+		options,
+		extension)
+}
+
+// newAzureDeploymentReconciler returns a reconciler for resources in Azure Resource Manager, using the optional
+// features enabled by options
+func newAzureDeploymentReconciler(
+	armConnectionFactory arm.ARMConnectionFactory,
+	kubeClient kubeclient.Client,
+	resourceResolver *resolver.Resolver,
+	positiveConditions *conditions.PositiveConditionBuilder,
+	expressionEvaluator asocel.ExpressionEvaluator,
+	options generic.Options,
+	extension genruntime.ResourceExtension,
+) *arm.AzureDeploymentReconciler {
+	result := arm.NewAzureDeploymentReconciler(
 		armConnectionFactory,
 		kubeClient,
 		resourceResolver,
@@ -256,6 +352,10 @@ func augmentWithARMReconciler(
 		expressionEvaluator,
 		options.Config,
 		extension)
+	result.ApplyPayloadMutationPolicies = options.ApplyPayloadMutationPolicies
+	result.RecordResourcePlans = options.RecordResourcePlans
+
+	return result
 }
 
 func augmentWithPredicate(t *registration.StorageType) {
@@ -348,6 +448,7 @@ func CreateScheme() *runtime.Scheme {
 	_ = postgresqlv1.AddToScheme(scheme)
 	_ = azuresqlv1.AddToScheme(scheme)
 	_ = entrav1.AddToScheme(scheme)
+	_ = serviceoperatorv1.AddToScheme(scheme)
 	return scheme
 }
 
@@ -160,8 +160,8 @@ func findRefsAndCreateSecrets(tc *testcommon.KubePerTestContext, resources []cli
 	// Find orphaned references
 	orphanRefs := set.Make[genruntime.SecretReference]()
 	for _, ref := range allReferences.Values() {
-		matchingDestination := genruntime.SecretDestination(ref)
-		matchingSecret := fmt.Sprintf("%s/%s", tc.Namespace, ref.Name)
+		matchingDestination := genruntime.SecretDestination{Name: ref.Name, Key: ref.Key}
+		matchingSecret := fmt.Sprintf("%s/%s", ref.TargetNamespace(tc.Namespace), ref.Name)
 		if allSecrets.Contains(matchingSecret) {
 			continue
 		}
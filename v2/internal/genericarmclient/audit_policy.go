@@ -0,0 +1,120 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/Azure/azure-service-operator/v2/internal/audit"
+)
+
+type auditPolicy struct {
+	sink       audit.Sink
+	credential string
+}
+
+var _ policy.Policy = auditPolicy{}
+
+// NewAuditPolicy creates a new policy.Policy which writes an audit record to sink for every request that modifies
+// resources in Azure (PUT, PATCH, DELETE and POST, other than read-only POSTs). credential identifies the credential used
+// by the pipeline.
+func NewAuditPolicy(sink audit.Sink, credential string) policy.Policy {
+	return auditPolicy{
+		sink:       sink,
+		credential: credential,
+	}
+}
+
+func (p auditPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	if !modifiesResources(raw.Method, raw.URL.Path) {
+		return req.Next()
+	}
+
+	record := audit.Record{
+		Time:       time.Now().UTC(),
+		Credential: p.credential,
+		Method:     raw.Method,
+		ARMID:      raw.URL.Path,
+		APIVersion: raw.URL.Query().Get("api-version"),
+	}
+
+	if resource, ok := audit.ResourceFromContext(raw.Context()); ok {
+		record.Resource = &resource
+	}
+
+	if body := req.Body(); body != nil {
+		payload, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+
+		record.PayloadHash = audit.HashPayload(payload)
+		err = req.RewindBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := req.Next()
+	record.Duration = time.Since(record.Time)
+
+	// ARM echoes back the correlation request ID we sent, or the one it generated if we didn't
+	record.CorrelationID = raw.Header.Get(CorrelationRequestIDHeader)
+	if resp != nil {
+		if correlationID := resp.Header.Get(CorrelationRequestIDHeader); correlationID != "" {
+			record.CorrelationID = correlationID
+		}
+
+		record.RequestID = resp.Header.Get(RequestIDHeader)
+		record.StatusCode = resp.StatusCode
+	}
+
+	switch {
+	case err != nil:
+		record.Outcome = audit.OutcomeFailed
+		record.Error = err.Error()
+	case resp.StatusCode >= http.StatusBadRequest:
+		record.Outcome = audit.OutcomeFailed
+	case resp.StatusCode == http.StatusAccepted:
+		record.Outcome = audit.OutcomeAccepted
+	default:
+		record.Outcome = audit.OutcomeSucceeded
+	}
+
+	p.sink.Write(raw.Context(), record)
+
+	return resp, err
+}
+
+// modifiesResources returns true if requests with the specified method and path may modify resources in Azure.
+func modifiesResources(method string, path string) bool {
+	switch method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return !isReadOnlyPost(path)
+	default:
+		return false
+	}
+}
+
+// isReadOnlyPost returns true if a POST to the specified path is known not to modify resources. POST is used for
+// queries as well as actions: Resource Graph queries, and list actions (such as listKeys) which return secrets too
+// large or sensitive for a GET.
+func isReadOnlyPost(path string) bool {
+	if strings.EqualFold(path, resourceGraphPath) {
+		return true
+	}
+
+	action := path[strings.LastIndex(path, "/")+1:]
+	return strings.HasPrefix(strings.ToLower(action), "list")
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/internal/audit"
+)
+
+type recordingSink struct {
+	lock    sync.Mutex
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(_ context.Context, record audit.Record) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, record)
+}
+
+func newAuditedPipeline(sink audit.Sink, transport policy.Transporter) runtime.Pipeline {
+	return runtime.NewPipeline(
+		"test",
+		"v1",
+		runtime.PipelineOptions{PerCall: []policy.Policy{NewAuditPolicy(sink, "default/aso-credential")}},
+		&policy.ClientOptions{
+			Transport: transport,
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		})
+}
+
+func Test_AuditPolicy_RecordsPut(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	sink := &recordingSink{}
+	transport := &echoTransport{}
+	pipeline := newAuditedPipeline(sink, transport)
+
+	resource := audit.Resource{
+		Group:     "resources.azure.com",
+		Kind:      "ResourceGroup",
+		Namespace: "default",
+		Name:      "myrg",
+		Actor:     "alice@example.com",
+	}
+	ctx := audit.WithResource(context.Background(), resource)
+
+	resourceID := "/subscriptions/" + testSubscription + "/resourceGroups/myrg"
+	req, err := runtime.NewRequest(ctx, http.MethodPut, "https://management.azure.com"+resourceID+"?api-version=2020-06-01")
+	g.Expect(err).ToNot(HaveOccurred())
+	req.Raw().Header.Set(CorrelationRequestIDHeader, "correlation-id")
+
+	payload := `{"location":"westus"}`
+	g.Expect(req.SetBody(streaming.NopCloser(strings.NewReader(payload)), "application/json")).To(Succeed())
+
+	resp, err := pipeline.Do(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+
+	g.Expect(sink.records).To(HaveLen(1))
+	record := sink.records[0]
+	g.Expect(record.Resource).To(Equal(&resource))
+	g.Expect(record.Credential).To(Equal("default/aso-credential"))
+	g.Expect(record.Method).To(Equal(http.MethodPut))
+	g.Expect(record.ARMID).To(Equal(resourceID))
+	g.Expect(record.APIVersion).To(Equal("2020-06-01"))
+	g.Expect(record.PayloadHash).To(Equal(audit.HashPayload([]byte(payload))))
+	g.Expect(record.CorrelationID).To(Equal("correlation-id"))
+	g.Expect(record.RequestID).To(Equal("request-id"))
+	g.Expect(record.Outcome).To(Equal(audit.OutcomeSucceeded))
+}
+
+func Test_AuditPolicy_IgnoresGet(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	sink := &recordingSink{}
+	pipeline := newAuditedPipeline(sink, &echoTransport{})
+
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://management.azure.com/subscriptions/"+testSubscription)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	resp, err := pipeline.Do(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+
+	g.Expect(sink.records).To(BeEmpty())
+}
+
+func Test_AuditPolicy_IgnoresReadOnlyPosts(t *testing.T) {
+	t.Parallel()
+
+	storageAccountID := "/subscriptions/" + testSubscription + "/resourceGroups/myrg/providers/Microsoft.Storage/storageAccounts/mysa"
+	cases := map[string]struct {
+		path          string
+		expectRecords int
+	}{
+		"Resource Graph query": {path: resourceGraphPath, expectRecords: 0},
+		"List action":          {path: storageAccountID + "/listKeys", expectRecords: 0},
+		"Other action":         {path: storageAccountID + "/regenerateKey", expectRecords: 1},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			sink := &recordingSink{}
+			pipeline := newAuditedPipeline(sink, &echoTransport{})
+
+			req, err := runtime.NewRequest(context.Background(), http.MethodPost, "https://management.azure.com"+c.path)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			resp, err := pipeline.Do(req)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(resp.Body.Close()).To(Succeed())
+
+			g.Expect(sink.records).To(HaveLen(c.expectRecords))
+		})
+	}
+}
@@ -7,6 +7,7 @@ package genericarmclient
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/rotisserie/eris"
 
+	"github.com/Azure/azure-service-operator/v2/internal/audit"
 	"github.com/Azure/azure-service-operator/v2/internal/metrics"
 	"github.com/Azure/azure-service-operator/v2/internal/version"
 )
@@ -26,6 +28,7 @@ import (
 const (
 	CreatePollerID = "GenericClient.CreateOrUpdateByID"
 	DeletePollerID = "GenericClient.DeleteByID"
+	ActionPollerID = "GenericClient.PostByID"
 )
 
 // NOTE: All of these methods (and types) were adapted from
@@ -33,10 +36,12 @@ const (
 // which was then moved to here: https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/resourcemanager/resources/armresources/client.go
 
 type GenericClient struct {
-	endpoint string
-	pl       runtime.Pipeline
-	creds    azcore.TokenCredential
-	opts     *arm.ClientOptions
+	endpoint   string
+	pl         runtime.Pipeline
+	creds      azcore.TokenCredential
+	opts       *arm.ClientOptions
+	rateLimits *RateLimitTracker
+	credential string
 }
 
 // TODO: Need to do retryAfter detection in each call?
@@ -46,6 +51,14 @@ type GenericClientOptions struct {
 	Metrics           *metrics.ARMClientMetrics
 	UserAgent         string
 	AdditionalTenants []string
+
+	// RateLimits, if set, tracks the ARM request budget remaining for each subscription, as reported by ARM.
+	// Requests are paced as the budget runs low.
+	RateLimits *RateLimitTracker
+	// Audit, if set, receives an audit record of every request that modifies resources in Azure.
+	Audit audit.Sink
+	// CredentialName identifies the credential used by the client when tracking RateLimits and in audit records.
+	CredentialName string
 }
 
 // NewGenericClient creates a new instance of GenericClient
@@ -103,20 +116,30 @@ func NewGenericClient(
 		return nil, eris.Wrapf(err, "failed to create rp registration policy")
 	}
 
-	opts.PerCallPolicies = append([]policy.Policy{rpRegistrationPolicy}, opts.PerCallPolicies...)
+	// Tracing comes first, so that the span for each request includes any time spent registering resource providers
+	opts.PerCallPolicies = append([]policy.Policy{NewTracingPolicy(), rpRegistrationPolicy, NewRequestRecorderPolicy()}, opts.PerCallPolicies...)
 	if options.Metrics != nil {
 		opts.PerCallPolicies = append(opts.PerCallPolicies, metrics.NewMetricsPolicy(options.Metrics))
 	}
+	if options.RateLimits != nil {
+		opts.PerCallPolicies = append(opts.PerCallPolicies, NewRateLimitPolicy(options.RateLimits, options.CredentialName))
+	}
+	if options.Audit != nil {
+		// Audit last, so that records are only written for requests actually sent to ARM
+		opts.PerCallPolicies = append(opts.PerCallPolicies, NewAuditPolicy(options.Audit, options.CredentialName))
+	}
 	pipeline, err := armruntime.NewPipeline("generic", version.BuildVersion, creds, runtime.PipelineOptions{}, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &GenericClient{
-		endpoint: rmConfig.Endpoint,
-		pl:       pipeline,
-		creds:    creds,
-		opts:     opts,
+		endpoint:   rmConfig.Endpoint,
+		pl:         pipeline,
+		creds:      creds,
+		opts:       opts,
+		rateLimits: options.RateLimits,
+		credential: options.CredentialName,
 	}, nil
 }
 
@@ -132,6 +155,21 @@ func (client *GenericClient) ClientOptions() *arm.ClientOptions {
 	return client.opts
 }
 
+// RateLimitDelay returns how long a request against the specified resource should be deferred for, given the ARM
+// request budget remaining for its subscription. Returns zero if there's budget to spare, or if it's not known.
+func (client *GenericClient) RateLimitDelay(resourceID string, write bool) time.Duration {
+	if client.rateLimits == nil {
+		return 0
+	}
+
+	subscription := subscriptionFromPath(resourceID)
+	if subscription == "" {
+		return 0
+	}
+
+	return client.rateLimits.Delay(subscription, client.credential, write)
+}
+
 func (client *GenericClient) BeginCreateOrUpdateByID(
 	ctx context.Context,
 	resourceID string,
@@ -304,6 +342,10 @@ func (client *GenericClient) checkExistenceByIDImpl(
 	if !runtime.HasStatusCode(resp, http.StatusNoContent, http.StatusNotFound) {
 		return retryAfter, client.handleError(resp)
 	}
+	if resp.StatusCode == http.StatusNotFound {
+		// HEAD responses have no body, so there's no error detail to unmarshal
+		return retryAfter, runtime.NewResponseError(resp)
+	}
 	return zeroDuration, nil
 }
 
@@ -502,6 +544,91 @@ func (client *GenericClient) deleteByIDCreateRequest(ctx context.Context, resour
 	return req, nil
 }
 
+// BeginPostByID - Invokes an action (such as regenerateKey or restart) on a resource by ID. The body is omitted if nil.
+// If the operation fails it returns the *CloudError error type.
+func (client *GenericClient) BeginPostByID(
+	ctx context.Context,
+	resourceID string,
+	action string,
+	apiVersion string,
+	body interface{},
+) (*PollerResponse[json.RawMessage], error) {
+	// The linter doesn't realize that the response is closed in the course of
+	// the autorest.NewPoller call below. Suppressing it as it is a false positive.
+	//nolint:bodyclose
+	resp, err := client.postByID(ctx, resourceID, action, apiVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := PollerResponse[json.RawMessage]{
+		RawResponse:  resp,
+		ID:           ActionPollerID,
+		ErrorHandler: client.handleError,
+	}
+	pt, err := runtime.NewPoller[json.RawMessage](resp, client.pl, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Poller = pt
+	return &result, nil
+}
+
+func (client *GenericClient) postByID(
+	ctx context.Context,
+	resourceID string,
+	action string,
+	apiVersion string,
+	body interface{},
+) (*http.Response, error) {
+	req, err := client.postByIDCreateRequest(ctx, resourceID, action, apiVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent) {
+		return nil, client.handleError(resp)
+	}
+
+	return resp, nil
+}
+
+// postByIDCreateRequest creates the PostByID request.
+func (client *GenericClient) postByIDCreateRequest(
+	ctx context.Context,
+	resourceID string,
+	action string,
+	apiVersion string,
+	body interface{},
+) (*policy.Request, error) {
+	if resourceID == "" {
+		return nil, eris.New("parameter resourceID cannot be empty")
+	}
+
+	if action == "" {
+		return nil, eris.New("parameter action cannot be empty")
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(client.endpoint, resourceID, action))
+	if err != nil {
+		return nil, err
+	}
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+	if body == nil {
+		return req, nil
+	}
+
+	return req, runtime.MarshalAsJSON(req, body)
+}
+
 func (client *GenericClient) CheckExistenceWithGetByID(ctx context.Context, resourceID string, apiVersion string) (bool, time.Duration, error) {
 	if resourceID == "" {
 		return false, zeroDuration, eris.New("parameter resourceID cannot be empty")
@@ -520,6 +647,20 @@ func (client *GenericClient) CheckExistenceWithGetByID(ctx context.Context, reso
 	}
 }
 
+// NewNotFoundError returns an error reporting that the resource with the specified ID doesn't exist, for which
+// IsNotFoundError returns true. Use it where we find a resource is missing without an error response from ARM to hand,
+// such as from CheckExistenceByID.
+func NewNotFoundError(resourceID string) error {
+	resp := &http.Response{
+		Status:     http.StatusText(http.StatusNotFound),
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+	}
+
+	return eris.Wrapf(runtime.NewResponseErrorWithErrorCode(resp, "ResourceNotFound"), "resource %q not found", resourceID)
+}
+
 func IsNotFoundError(err error) bool {
 	var typedError *azcore.ResponseError
 	if eris.As(err, &typedError) {
@@ -538,3 +679,7 @@ func (client *GenericClient) ResumeDeletePoller(id string) *PollerResponse[Gener
 func (client *GenericClient) ResumeCreatePoller(id string) *PollerResponse[GenericResource] {
 	return &PollerResponse[GenericResource]{ID: id, ErrorHandler: client.handleError}
 }
+
+func (client *GenericClient) ResumeActionPoller(id string) *PollerResponse[json.RawMessage] {
+	return &PollerResponse[json.RawMessage]{ID: id, ErrorHandler: client.handleError}
+}
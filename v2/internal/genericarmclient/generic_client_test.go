@@ -337,3 +337,92 @@ func Test_NewResourceGroup_ErrorIncludesRequestID(t *testing.T) {
 	g.Expect(cloudError.Error()).To(ContainSubstring("123459999"))
 	g.Expect(cloudError.RequestID()).To(Equal("123459999"))
 }
+
+func Test_PostByID_CompletedSynchronously_ReturnsResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if r.URL.Path == "/subscriptions/12345/resourceGroups/myrg/providers/Microsoft.Fake/fakeResource/fake/regenerateKey" {
+				g.Expect(r.URL.Query().Get("api-version")).To(Equal("2019-01-01"))
+				w.WriteHeader(http.StatusOK)
+				g.Expect(w.Write([]byte(`{"keys":[{"keyName":"key1","value":"secret"}]}`))).ToNot(BeZero())
+				return
+			}
+		}
+
+		g.Fail(fmt.Sprintf("unknown request attempted. Method: %s, URL: %s", r.Method, r.URL))
+	}))
+	defer server.Close()
+
+	cfg := cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: server.URL,
+				Audience: cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+			},
+		},
+	}
+
+	options := &genericarmclient.GenericClientOptions{
+		HTTPClient: server.Client(),
+		Metrics:    asometrics.NewARMClientMetrics(),
+	}
+	client, err := genericarmclient.NewGenericClient(cfg, creds.MockTokenCredential{}, options)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	resourceURI := "/subscriptions/12345/resourceGroups/myrg/providers/Microsoft.Fake/fakeResource/fake"
+	body := map[string]string{"keyName": "key1"}
+
+	poller, err := client.BeginPostByID(ctx, resourceURI, "regenerateKey", "2019-01-01", body)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(poller.ID).To(Equal(genericarmclient.ActionPollerID))
+	g.Expect(poller.Poller.Done()).To(BeTrue())
+
+	result, err := poller.Poller.Result(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(result)).To(ContainSubstring(`"keyName":"key1"`))
+}
+
+func Test_PostByID_Conflict_ReturnsCloudError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if r.URL.Path == "/subscriptions/12345/resourceGroups/myrg/providers/Microsoft.Fake/fakeResource/fake/restart" {
+				w.WriteHeader(http.StatusConflict)
+				g.Expect(w.Write([]byte(rpMalformedConflictError))).ToNot(BeZero())
+				return
+			}
+		}
+
+		g.Fail(fmt.Sprintf("unknown request attempted. Method: %s, URL: %s", r.Method, r.URL))
+	}))
+	defer server.Close()
+
+	cfg := cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: server.URL,
+				Audience: cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+			},
+		},
+	}
+
+	options := &genericarmclient.GenericClientOptions{
+		HTTPClient: server.Client(),
+		Metrics:    asometrics.NewARMClientMetrics(),
+	}
+	client, err := genericarmclient.NewGenericClient(cfg, creds.MockTokenCredential{}, options)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	resourceURI := "/subscriptions/12345/resourceGroups/myrg/providers/Microsoft.Fake/fakeResource/fake"
+
+	_, err = client.BeginPostByID(ctx, resourceURI, "restart", "2019-01-01", nil)
+	g.Expect(err).To(MatchError(ContainSubstring("409 Conflict")))
+	g.Expect(err).To(MatchError(ContainSubstring("OperationNotAllowed")))
+}
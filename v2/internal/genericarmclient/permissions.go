@@ -0,0 +1,34 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"context"
+	"strings"
+)
+
+const permissionsAPIVersion = "2022-04-01"
+
+// Permission is a set of actions granted to the caller at a scope by one of its role assignments.
+// See https://learn.microsoft.com/rest/api/authorization/permissions
+type Permission struct {
+	// Actions are the management plane actions allowed, which may include wildcards
+	Actions []string `json:"actions,omitempty"`
+	// NotActions are the management plane actions excluded from Actions
+	NotActions []string `json:"notActions,omitempty"`
+	// DataActions are the data plane actions allowed
+	DataActions []string `json:"dataActions,omitempty"`
+	// NotDataActions are the data plane actions excluded from DataActions
+	NotDataActions []string `json:"notDataActions,omitempty"`
+}
+
+// ListPermissions returns the permissions the caller has at the specified scope, which may be a subscription,
+// resource group or resource ID.
+// If the operation fails it returns the *CloudError error type.
+func (client *GenericClient) ListPermissions(ctx context.Context, scope string) ([]Permission, error) {
+	containerID := strings.TrimSuffix(scope, "/") + "/providers/Microsoft.Authorization/permissions"
+	return ListByContainerID[Permission](ctx, client, containerID, permissionsAPIVersion)
+}
@@ -25,6 +25,9 @@ type PollerResponse[T any] struct {
 	// RawResponse contains the underlying HTTP response.
 	RawResponse *http.Response
 
+	// Result is the result of the operation, set by Resume once the operation is done.
+	Result T
+
 	ErrorHandler func(resp *http.Response) error
 }
 
@@ -49,10 +52,11 @@ func (l *PollerResponse[T]) Resume(ctx context.Context, client *GenericClient, t
 	}
 
 	if poller.Done() {
-		// TODO: In some cases this actually ends up issuing a GET on the resource, which we ignore the response of.
-		// TODO: Ideally we would have a way to use the response here to fill out the status without needing to issue
-		// TODO: a separate request, but for now not worrying about that
-		_, err = poller.Result(ctx)
+		// TODO: In some cases this actually ends up issuing a GET on the resource. The result is kept for the callers that
+		// TODO: need it (such as actions), but resources still fill out their status with a separate request; ideally
+		// TODO: we would use the response here instead, but for now not worrying about that
+		var result T
+		result, err = poller.Result(ctx)
 		if err != nil {
 			var typedError *azcore.ResponseError
 			if eris.As(err, &typedError) {
@@ -62,6 +66,8 @@ func (l *PollerResponse[T]) Resume(ctx context.Context, client *GenericClient, t
 			}
 			return err
 		}
+
+		l.Result = result
 	}
 
 	l.Poller = poller
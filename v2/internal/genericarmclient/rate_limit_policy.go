@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// maxPacingDelay caps how long the rate limit policy will hold an individual request. Longer delays are left to the
+// reconciler, which can requeue the resource rather than tie up a worker.
+const maxPacingDelay = 5 * time.Second
+
+type rateLimitPolicy struct {
+	tracker    *RateLimitTracker
+	credential string
+}
+
+var _ policy.Policy = rateLimitPolicy{}
+
+// NewRateLimitPolicy creates a new policy.Policy which records the ARM request budget reported in each response with
+// the tracker, and paces requests once the budget for the subscription is running low.
+// credential identifies the credential used by the pipeline, as budgets are tracked per subscription and credential.
+func NewRateLimitPolicy(tracker *RateLimitTracker, credential string) policy.Policy {
+	return rateLimitPolicy{
+		tracker:    tracker,
+		credential: credential,
+	}
+}
+
+func (p rateLimitPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	subscription := subscriptionFromPath(raw.URL.Path)
+	if subscription == "" {
+		return req.Next()
+	}
+
+	delay := min(p.tracker.Delay(subscription, p.credential, isWrite(raw.Method)), maxPacingDelay)
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-raw.Context().Done():
+			timer.Stop()
+			return nil, raw.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	resp, err := req.Next()
+	if resp != nil {
+		p.record(subscription, resp.Header)
+	}
+
+	return resp, err
+}
+
+func (p rateLimitPolicy) record(subscription string, header http.Header) {
+	if reads, ok := parseRemaining(header, RemainingReadsHeader); ok {
+		p.tracker.RecordReads(subscription, p.credential, reads)
+	}
+
+	if writes, ok := parseRemaining(header, RemainingWritesHeader); ok {
+		p.tracker.RecordWrites(subscription, p.credential, writes)
+	}
+}
+
+func parseRemaining(header http.Header, name string) (int, bool) {
+	value := header.Get(name)
+	if value == "" {
+		return 0, false
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return result, true
+}
+
+// isWrite returns true if requests with the specified method count against ARM's write budget.
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/Azure/azure-service-operator/v2/internal/metrics"
+)
+
+const (
+	// RemainingReadsHeader is the header ARM uses to report the number of reads left in the subscription's budget
+	RemainingReadsHeader = "x-ms-ratelimit-remaining-subscription-reads"
+	// RemainingWritesHeader is the header ARM uses to report the number of writes left in the subscription's budget
+	RemainingWritesHeader = "x-ms-ratelimit-remaining-subscription-writes"
+)
+
+// RateLimitTrackerOptions configures when a RateLimitTracker starts asking callers to slow down.
+type RateLimitTrackerOptions struct {
+	// ReadThreshold is the number of remaining reads below which reads are slowed down.
+	ReadThreshold int
+	// WriteThreshold is the number of remaining writes below which writes are slowed down.
+	WriteThreshold int
+	// MaxDelay is the delay requested when the budget is exhausted. The delay scales linearly from zero at the
+	// threshold up to MaxDelay when nothing remains.
+	MaxDelay time.Duration
+	// Expiry is how long an observed budget is trusted for. ARM refills budgets over time, so old observations
+	// say little about the budget now.
+	Expiry time.Duration
+}
+
+// DefaultRateLimitTrackerOptions are used by NewRateLimitTracker.
+var DefaultRateLimitTrackerOptions = RateLimitTrackerOptions{
+	ReadThreshold:  100,
+	WriteThreshold: 50,
+	MaxDelay:       1 * time.Minute,
+	Expiry:         5 * time.Minute,
+}
+
+// RateLimitTracker tracks the ARM request budget remaining for each subscription and credential, as reported by ARM
+// in the x-ms-ratelimit-remaining-subscription-* headers. It's safe for concurrent use.
+type RateLimitTracker struct {
+	lock    sync.Mutex
+	budgets map[rateLimitKey]*rateLimitBudget
+	options RateLimitTrackerOptions
+	metrics *metrics.ARMClientMetrics
+	clock   clock.Clock
+}
+
+type rateLimitKey struct {
+	subscription string
+	credential   string
+}
+
+type rateLimitBudget struct {
+	reads      int
+	readsSeen  time.Time
+	writes     int
+	writesSeen time.Time
+}
+
+// NewRateLimitTracker creates a new RateLimitTracker. armMetrics may be nil, in which case no metrics are recorded.
+func NewRateLimitTracker(armMetrics *metrics.ARMClientMetrics) *RateLimitTracker {
+	return NewRateLimitTrackerWithOptions(armMetrics, DefaultRateLimitTrackerOptions, clock.New())
+}
+
+// NewRateLimitTrackerWithOptions creates a new RateLimitTracker with the specified options and clock.
+func NewRateLimitTrackerWithOptions(
+	armMetrics *metrics.ARMClientMetrics,
+	options RateLimitTrackerOptions,
+	clk clock.Clock,
+) *RateLimitTracker {
+	return &RateLimitTracker{
+		budgets: make(map[rateLimitKey]*rateLimitBudget),
+		options: options,
+		metrics: armMetrics,
+		clock:   clk,
+	}
+}
+
+// RecordReads records the number of reads ARM reported as remaining for the subscription and credential.
+func (t *RateLimitTracker) RecordReads(subscription string, credential string, remaining int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	budget := t.budget(subscription, credential)
+	budget.reads = remaining
+	budget.readsSeen = t.clock.Now()
+
+	if t.metrics != nil {
+		t.metrics.RecordAzureRateLimitRemaining(subscription, credential, "reads", remaining)
+	}
+}
+
+// RecordWrites records the number of writes ARM reported as remaining for the subscription and credential.
+func (t *RateLimitTracker) RecordWrites(subscription string, credential string, remaining int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	budget := t.budget(subscription, credential)
+	budget.writes = remaining
+	budget.writesSeen = t.clock.Now()
+
+	if t.metrics != nil {
+		t.metrics.RecordAzureRateLimitRemaining(subscription, credential, "writes", remaining)
+	}
+}
+
+// Delay returns how long a read (or write) against the subscription using the credential should be held back for,
+// given the budget ARM last reported. Returns zero if there's plenty of budget left, or if we don't know.
+func (t *RateLimitTracker) Delay(subscription string, credential string, write bool) time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	budget, ok := t.budgets[t.key(subscription, credential)]
+	if !ok {
+		return 0
+	}
+
+	remaining, seen, threshold := budget.reads, budget.readsSeen, t.options.ReadThreshold
+	if write {
+		remaining, seen, threshold = budget.writes, budget.writesSeen, t.options.WriteThreshold
+	}
+
+	if seen.IsZero() || t.clock.Since(seen) > t.options.Expiry {
+		return 0
+	}
+
+	if remaining >= threshold || threshold <= 0 {
+		return 0
+	}
+
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return t.options.MaxDelay * time.Duration(threshold-remaining) / time.Duration(threshold)
+}
+
+// budget returns the budget for the subscription and credential, creating it if needed. The lock must be held.
+func (t *RateLimitTracker) budget(subscription string, credential string) *rateLimitBudget {
+	key := t.key(subscription, credential)
+	result, ok := t.budgets[key]
+	if !ok {
+		result = &rateLimitBudget{}
+		t.budgets[key] = result
+	}
+
+	return result
+}
+
+func (t *RateLimitTracker) key(subscription string, credential string) rateLimitKey {
+	return rateLimitKey{
+		subscription: strings.ToLower(subscription),
+		credential:   credential,
+	}
+}
+
+// subscriptionFromPath returns the subscription ID from an ARM request path, or "" if the path isn't scoped to a
+// subscription.
+func subscriptionFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "subscriptions") {
+			return segments[i+1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testSubscription = "00000000-0000-0000-0000-000000000000"
+	testCredential   = "default/aso-credential"
+)
+
+func newTestRateLimitTracker() (*RateLimitTracker, *clock.Mock) {
+	clk := clock.NewMock()
+	options := RateLimitTrackerOptions{
+		ReadThreshold:  100,
+		WriteThreshold: 10,
+		MaxDelay:       time.Minute,
+		Expiry:         5 * time.Minute,
+	}
+
+	return NewRateLimitTrackerWithOptions(nil, options, clk), clk
+}
+
+func Test_RateLimitTracker_UnknownBudget_NoDelay(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tracker, _ := newTestRateLimitTracker()
+	g.Expect(tracker.Delay(testSubscription, testCredential, true)).To(BeZero())
+	g.Expect(tracker.Delay(testSubscription, testCredential, false)).To(BeZero())
+}
+
+func Test_RateLimitTracker_DelayScalesAsBudgetRunsOut(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tracker, _ := newTestRateLimitTracker()
+
+	tracker.RecordWrites(testSubscription, testCredential, 10)
+	g.Expect(tracker.Delay(testSubscription, testCredential, true)).To(BeZero())
+
+	tracker.RecordWrites(testSubscription, testCredential, 5)
+	g.Expect(tracker.Delay(testSubscription, testCredential, true)).To(Equal(30 * time.Second))
+
+	tracker.RecordWrites(testSubscription, testCredential, 0)
+	g.Expect(tracker.Delay(testSubscription, testCredential, true)).To(Equal(time.Minute))
+
+	// Reads are tracked separately
+	g.Expect(tracker.Delay(testSubscription, testCredential, false)).To(BeZero())
+}
+
+func Test_RateLimitTracker_BudgetsTrackedPerCredential(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tracker, _ := newTestRateLimitTracker()
+	tracker.RecordReads(testSubscription, testCredential, 0)
+
+	g.Expect(tracker.Delay(testSubscription, testCredential, false)).To(Equal(time.Minute))
+	g.Expect(tracker.Delay(testSubscription, "other/credential", false)).To(BeZero())
+}
+
+func Test_RateLimitTracker_StaleBudgetIgnored(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tracker, clk := newTestRateLimitTracker()
+	tracker.RecordWrites(testSubscription, testCredential, 0)
+	g.Expect(tracker.Delay(testSubscription, testCredential, true)).To(Equal(time.Minute))
+
+	clk.Add(6 * time.Minute)
+	g.Expect(tracker.Delay(testSubscription, testCredential, true)).To(BeZero())
+}
+
+func Test_SubscriptionFromPath(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"/subscriptions/" + testSubscription + "/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet": testSubscription,
+		"/SUBSCRIPTIONS/" + testSubscription:                  testSubscription,
+		"/providers/Microsoft.Management/managementGroups/mg": "",
+		"/subscriptions": "",
+		"/subscriptions/" + testSubscription + "/providers/Microsoft.Resources": testSubscription,
+	}
+
+	for path, expected := range cases {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(subscriptionFromPath(path)).To(Equal(expected))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// RecordedRequest captures the details of a request sent to ARM
+type RecordedRequest struct {
+	Method        string
+	StartTime     time.Time
+	EndTime       time.Time
+	StatusCode    int
+	CorrelationID string
+	RequestID     string
+}
+
+// RequestRecorder collects the requests that modify resources in Azure (PUT, PATCH, DELETE and POST, other than
+// read-only POSTs) which are sent using a context returned by WithRequestRecorder.
+type RequestRecorder struct {
+	lock     sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRequestRecorder creates a new, empty, RequestRecorder
+func NewRequestRecorder() *RequestRecorder {
+	return &RequestRecorder{}
+}
+
+// Requests returns the requests recorded so far, in the order they were sent
+func (r *RequestRecorder) Requests() []RecordedRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	result := make([]RecordedRequest, len(r.requests))
+	copy(result, r.requests)
+	return result
+}
+
+func (r *RequestRecorder) add(request RecordedRequest) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests = append(r.requests, request)
+}
+
+type requestRecorderKey struct{}
+
+// WithRequestRecorder returns a context which records requests sent with it to the provided recorder
+func WithRequestRecorder(ctx context.Context, recorder *RequestRecorder) context.Context {
+	return context.WithValue(ctx, requestRecorderKey{}, recorder)
+}
+
+func requestRecorderFromContext(ctx context.Context) (*RequestRecorder, bool) {
+	recorder, ok := ctx.Value(requestRecorderKey{}).(*RequestRecorder)
+	return recorder, ok && recorder != nil
+}
+
+type requestRecorderPolicy struct{}
+
+var _ policy.Policy = requestRecorderPolicy{}
+
+// NewRequestRecorderPolicy creates a new policy.Policy which records requests that modify resources in Azure with the
+// RequestRecorder of the request context, if any.
+func NewRequestRecorderPolicy() policy.Policy {
+	return requestRecorderPolicy{}
+}
+
+func (p requestRecorderPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	recorder, ok := requestRecorderFromContext(raw.Context())
+	if !ok || !modifiesResources(raw.Method, raw.URL.Path) {
+		return req.Next()
+	}
+
+	recorded := RecordedRequest{
+		Method:    raw.Method,
+		StartTime: time.Now(),
+	}
+
+	resp, err := req.Next()
+	recorded.EndTime = time.Now()
+
+	// ARM echoes back the correlation request ID we sent, or the one it generated if we didn't
+	recorded.CorrelationID = raw.Header.Get(CorrelationRequestIDHeader)
+	if resp != nil {
+		if correlationID := resp.Header.Get(CorrelationRequestIDHeader); correlationID != "" {
+			recorded.CorrelationID = correlationID
+		}
+
+		recorded.RequestID = resp.Header.Get(RequestIDHeader)
+		recorded.StatusCode = resp.StatusCode
+	}
+
+	recorder.add(recorded)
+
+	return resp, err
+}
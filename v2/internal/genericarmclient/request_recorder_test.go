@@ -0,0 +1,73 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+)
+
+func Test_RequestRecorderPolicy_RecordsMutatingRequests(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	pipeline := runtime.NewPipeline(
+		"test",
+		"v1",
+		runtime.PipelineOptions{PerCall: []policy.Policy{NewRequestRecorderPolicy()}},
+		&policy.ClientOptions{
+			Transport: &echoTransport{},
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		})
+
+	recorder := NewRequestRecorder()
+	ctx := WithRequestRecorder(context.Background(), recorder)
+	url := "https://management.azure.com/subscriptions/" + testSubscription + "/resourceGroups/myrg"
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		req, err := runtime.NewRequest(ctx, method, url)
+		g.Expect(err).ToNot(HaveOccurred())
+		req.Raw().Header.Set(CorrelationRequestIDHeader, "correlation-id")
+
+		resp, err := pipeline.Do(req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(resp.Body.Close()).To(Succeed())
+	}
+
+	requests := recorder.Requests()
+	g.Expect(requests).To(HaveLen(2))
+	g.Expect(requests[0].Method).To(Equal(http.MethodPut))
+	g.Expect(requests[1].Method).To(Equal(http.MethodDelete))
+	g.Expect(requests[1].StatusCode).To(Equal(http.StatusOK))
+	g.Expect(requests[1].CorrelationID).To(Equal("correlation-id"))
+	g.Expect(requests[1].RequestID).To(Equal("request-id"))
+}
+
+func Test_RequestRecorderPolicy_WithoutRecorder_DoesNothing(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	pipeline := runtime.NewPipeline(
+		"test",
+		"v1",
+		runtime.PipelineOptions{PerCall: []policy.Policy{NewRequestRecorderPolicy()}},
+		&policy.ClientOptions{
+			Transport: &echoTransport{},
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		})
+
+	req, err := runtime.NewRequest(context.Background(), http.MethodPut, "https://management.azure.com/subscriptions/"+testSubscription)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	resp, err := pipeline.Do(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+}
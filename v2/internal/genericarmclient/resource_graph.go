@@ -0,0 +1,108 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/rotisserie/eris"
+)
+
+const resourceGraphAPIVersion = "2022-10-01"
+
+// resourceGraphPath is the path Resource Graph queries are POSTed to
+const resourceGraphPath = "/providers/Microsoft.ResourceGraph/resources"
+
+// ResourceGraphRequest is a query against Azure Resource Graph.
+// See https://learn.microsoft.com/rest/api/azureresourcegraph/resourcegraph/resources/resources
+type ResourceGraphRequest struct {
+	// Subscriptions is the set of subscriptions the query is scoped to
+	Subscriptions []string `json:"subscriptions,omitempty"`
+	// Query is the Kusto (KQL) query to run
+	Query string `json:"query"`
+	// Options controls paging of the results
+	Options *ResourceGraphRequestOptions `json:"options,omitempty"`
+}
+
+// ResourceGraphRequestOptions controls paging of the results of a Resource Graph query.
+type ResourceGraphRequestOptions struct {
+	// Top is the maximum number of rows to return
+	Top *int32 `json:"$top,omitempty"`
+	// SkipToken continues a previous query from where it left off
+	SkipToken string `json:"$skipToken,omitempty"`
+	// ResultFormat must be objectArray, as we expect one JSON object per row
+	ResultFormat string `json:"resultFormat,omitempty"`
+}
+
+// ResourceGraphResponse is the response to a Resource Graph query.
+type ResourceGraphResponse struct {
+	// TotalRecords is the total number of rows matching the query
+	TotalRecords int64 `json:"totalRecords"`
+	// Count is the number of rows in this response
+	Count int64 `json:"count"`
+	// Data contains one entry per row
+	Data []json.RawMessage `json:"data"`
+	// SkipToken, if set, is used to request the next page of results
+	SkipToken string `json:"$skipToken,omitempty"`
+}
+
+// QueryResourceGraph runs a query against Azure Resource Graph, returning a single page of results.
+func (client *GenericClient) QueryResourceGraph(
+	ctx context.Context,
+	request ResourceGraphRequest,
+) (*ResourceGraphResponse, error) {
+	if request.Options == nil {
+		request.Options = &ResourceGraphRequestOptions{}
+	}
+	request.Options.ResultFormat = "objectArray"
+
+	req, err := client.queryResourceGraphCreateRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// The linter doesn't realize that the response is closed in the course of
+	// the runtime.UnmarshalAsJSON call below. Suppressing it as it is a false positive.
+	//nolint:bodyclose
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, client.handleError(resp)
+	}
+
+	var result ResourceGraphResponse
+	if err := runtime.UnmarshalAsJSON(resp, &result); err != nil {
+		return nil, eris.Wrap(err, "unmarshalling Resource Graph response")
+	}
+
+	return &result, nil
+}
+
+func (client *GenericClient) queryResourceGraphCreateRequest(
+	ctx context.Context,
+	request ResourceGraphRequest,
+) (*policy.Request, error) {
+	if request.Query == "" {
+		return nil, eris.New("parameter query cannot be empty")
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(client.endpoint, resourceGraphPath))
+	if err != nil {
+		return nil, err
+	}
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", resourceGraphAPIVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+	return req, runtime.MarshalAsJSON(req, request)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
+)
+
+const (
+	// CorrelationRequestIDHeader is used by ARM to correlate all the operations that make up a request
+	CorrelationRequestIDHeader = "x-ms-correlation-request-id"
+	// RequestIDHeader uniquely identifies each request to ARM
+	RequestIDHeader = "x-ms-request-id"
+)
+
+type tracingPolicy struct{}
+
+var _ policy.Policy = tracingPolicy{}
+
+// NewTracingPolicy creates a new policy.Policy which creates a span for each request to ARM. The trace context is
+// propagated to ARM, and, unless a correlation request ID has already been set, the trace ID is used as the
+// correlation request ID so that ARM activity logs can be found from the trace.
+func NewTracingPolicy() policy.Policy {
+	return tracingPolicy{}
+}
+
+func (p tracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	ctx, span := tracing.Start(
+		raw.Context(),
+		fmt.Sprintf("ARM %s", raw.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", raw.Method),
+			attribute.String("server.address", raw.URL.Host),
+			attribute.String("url.path", raw.URL.Path)))
+	defer span.End()
+
+	spanContext := span.SpanContext()
+	if spanContext.IsValid() {
+		propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(raw.Header))
+		if raw.Header.Get(CorrelationRequestIDHeader) == "" {
+			// Trace IDs are 16 bytes, the same as a UUID
+			raw.Header.Set(CorrelationRequestIDHeader, uuid.UUID(spanContext.TraceID()).String())
+		}
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	// ARM echoes back the correlation request ID we sent, or the one it generated if we didn't
+	correlationID := resp.Header.Get(CorrelationRequestIDHeader)
+	if correlationID == "" {
+		correlationID = raw.Header.Get(CorrelationRequestIDHeader)
+	}
+
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", resp.StatusCode),
+		tracing.CorrelationIDKey.String(correlationID),
+		tracing.RequestIDKey.String(resp.Header.Get(RequestIDHeader)))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genericarmclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
+)
+
+// echoTransport responds to every request with 200 OK, echoing the correlation request ID as ARM does
+type echoTransport struct {
+	requests []*http.Request
+}
+
+func (t *echoTransport) Do(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	header := http.Header{}
+	header.Set(CorrelationRequestIDHeader, req.Header.Get(CorrelationRequestIDHeader))
+	header.Set(RequestIDHeader, "request-id")
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+//nolint:paralleltest // Replaces the global tracer provider
+func Test_TracingPolicy_UsesTraceIDAsCorrelationRequestID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	transport := &echoTransport{}
+	pipeline := runtime.NewPipeline(
+		"test",
+		"v1",
+		runtime.PipelineOptions{PerCall: []policy.Policy{NewTracingPolicy()}},
+		&policy.ClientOptions{
+			Transport: transport,
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		})
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+	req, err := runtime.NewRequest(ctx, http.MethodGet, "https://management.azure.com/subscriptions/"+testSubscription)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	resp, err := pipeline.Do(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resp.Body.Close()).To(Succeed())
+	parent.End()
+
+	traceID := parent.SpanContext().TraceID()
+	expectedCorrelationID := uuid.UUID(traceID).String()
+
+	g.Expect(transport.requests).To(HaveLen(1))
+	sent := transport.requests[0]
+	g.Expect(sent.Header.Get(CorrelationRequestIDHeader)).To(Equal(expectedCorrelationID))
+	g.Expect(sent.Header.Get("traceparent")).To(ContainSubstring(traceID.String()))
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(2))
+	armSpan := spans[0]
+	g.Expect(armSpan.Name()).To(Equal("ARM GET"))
+	g.Expect(armSpan.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	g.Expect(armSpan.Attributes()).To(ContainElement(tracing.CorrelationIDKey.String(expectedCorrelationID)))
+	g.Expect(armSpan.Attributes()).To(ContainElement(attribute.Int("http.response.status_code", http.StatusOK)))
+}
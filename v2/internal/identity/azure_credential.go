@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package identity
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/config"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/retry"
+)
+
+// ReasonAzureCredentialNotFound is used when the AzureCredential of a resource doesn't exist, or can't be used by it.
+var ReasonAzureCredentialNotFound = conditions.MakeReason("AzureCredentialNotFound", retry.Fast)
+
+// getCredentialFromAzureCredentialAnnotation creates a Credential from the AzureCredential referenced by the
+// azure-credential annotation of obj or, if obj doesn't have one, of its namespace.
+// If neither has the annotation, a nil credential is returned.
+func (c *credentialProvider) getCredentialFromAzureCredentialAnnotation(ctx context.Context, obj genruntime.MetaObject) (*Credential, error) {
+	reference, ok := obj.GetAnnotations()[annotations.AzureCredential]
+	if !ok && obj.GetNamespace() != "" {
+		namespace, err := kubeclient.GetNamespace(ctx, c.kubeClient, obj.GetNamespace())
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, eris.Wrapf(err, "getting namespace %q", obj.GetNamespace())
+		}
+
+		if namespace != nil {
+			reference, ok = namespace.GetAnnotations()[annotations.AzureCredential]
+		}
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	credentialName, err := parseAzureCredentialReference(reference, obj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &serviceoperator.AzureCredential{}
+	err = c.kubeClient.Get(ctx, credentialName, credential)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, conditions.NewReadyConditionImpactingError(
+				eris.Wrapf(err, "AzureCredential %q not found", credentialName),
+				conditions.ConditionSeverityWarning,
+				ReasonAzureCredentialNotFound)
+		}
+
+		return nil, eris.Wrapf(err, "getting AzureCredential %q", credentialName)
+	}
+
+	if !credential.AllowsNamespace(obj.GetNamespace()) {
+		return nil, conditions.NewReadyConditionImpactingError(
+			eris.Errorf("AzureCredential %q does not allow use by resources in namespace %q", credentialName, obj.GetNamespace()),
+			conditions.ConditionSeverityWarning,
+			ReasonAzureCredentialNotFound)
+	}
+
+	return c.newCredentialFromAzureCredential(ctx, credential)
+}
+
+// newCredentialFromAzureCredential creates a Credential from an AzureCredential, reading any secrets it refers to.
+func (c *credentialProvider) newCredentialFromAzureCredential(
+	ctx context.Context,
+	credential *serviceoperator.AzureCredential,
+) (*Credential, error) {
+	spec := credential.Spec
+
+	data := map[string][]byte{
+		config.AzureSubscriptionID: []byte(spec.SubscriptionID),
+		config.AzureTenantID:       []byte(spec.TenantID),
+		config.AzureClientID:       []byte(spec.ClientID),
+	}
+
+	if len(spec.AdditionalTenants) > 0 {
+		data[config.AzureAdditionalTenants] = []byte(strings.Join(spec.AdditionalTenants, ","))
+	}
+
+	if spec.ClientSecret != nil {
+		value, err := c.getSecretKey(ctx, credential.Namespace, *spec.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		data[config.AzureClientSecret] = value
+	} else if spec.ClientCertificate != nil {
+		value, err := c.getSecretKey(ctx, credential.Namespace, spec.ClientCertificate.Certificate)
+		if err != nil {
+			return nil, err
+		}
+
+		data[config.AzureClientCertificate] = value
+
+		if spec.ClientCertificate.Password != nil {
+			value, err = c.getSecretKey(ctx, credential.Namespace, *spec.ClientCertificate.Password)
+			if err != nil {
+				return nil, err
+			}
+
+			data[config.AzureClientCertificatePassword] = value
+		}
+	}
+
+	// Otherwise, newCredentialFromData defaults to workload identity
+	return c.newCredentialFromData(azureCredentialFrom(credential), data)
+}
+
+// azureCredentialKindPrefix distinguishes credentials from AzureCredentials from those from secrets of the same name.
+// Kubernetes names can't contain "/", so the prefixed name can't be that of a secret.
+const azureCredentialKindPrefix = "AzureCredential/"
+
+// azureCredentialFrom returns the CredentialFrom of a credential created from an AzureCredential
+func azureCredentialFrom(credential *serviceoperator.AzureCredential) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: credential.Namespace,
+		Name:      azureCredentialKindPrefix + credential.Name,
+	}
+}
+
+// getSecretKey returns the value of the specified key of a secret in namespace.
+func (c *credentialProvider) getSecretKey(ctx context.Context, namespace string, ref serviceoperator.SecretKeyReference) ([]byte, error) {
+	nsName := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+
+	secret, err := c.getSecret(ctx, namespace, ref.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, core.NewSecretNotFoundError(nsName, eris.Wrapf(err, "credential secret not found"))
+		}
+		return nil, err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, core.NewSecretNotFoundError(nsName, eris.Errorf("credential Secret %q does not contain key %q", nsName, ref.Key))
+	}
+
+	return value, nil
+}
+
+// parseAzureCredentialReference parses the value of the azure-credential annotation, which is either "name" for an
+// AzureCredential in namespace, or "namespace/name".
+func parseAzureCredentialReference(reference string, namespace string) (types.NamespacedName, error) {
+	parts := strings.Split(reference, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return types.NamespacedName{Namespace: namespace, Name: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+	default:
+		return types.NamespacedName{}, eris.Errorf(
+			"%s must be either name or namespace/name, but was %q",
+			annotations.AzureCredential,
+			reference)
+	}
+}
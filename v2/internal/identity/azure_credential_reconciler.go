@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/rotisserie/eris"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+)
+
+const (
+	// azureCredentialCheckInterval is how often we check that a token can be acquired with each AzureCredential
+	azureCredentialCheckInterval = 15 * time.Minute
+
+	ReasonInvalidCredential      = "InvalidCredential"
+	ReasonTokenAcquisitionFailed = "TokenAcquisitionFailed"
+)
+
+// PermissionChecker checks that a credential has the access needed by the resources that use it.
+type PermissionChecker interface {
+	// CheckPermissions returns the permissions missing from credential.
+	CheckPermissions(ctx context.Context, credential *Credential) ([]serviceoperator.MissingPermission, error)
+}
+
+// AzureCredentialReconciler checks that a token can be acquired with each AzureCredential, reporting the result in
+// its status, so that broken credentials are visible before resources using them fail.
+type AzureCredentialReconciler struct {
+	kubeClient            kubeclient.Client
+	provider              *credentialProvider
+	readyConditions       *conditions.ReadyConditionBuilder
+	permissionsConditions *conditions.PermissionsConditionBuilder
+	permissionChecker     PermissionChecker
+}
+
+var _ reconcile.Reconciler = &AzureCredentialReconciler{}
+
+// NewAzureCredentialReconciler creates a new AzureCredentialReconciler
+func NewAzureCredentialReconciler(
+	kubeClient kubeclient.Client,
+	positiveConditions *conditions.PositiveConditionBuilder,
+	opts *CredentialProviderOptions,
+) *AzureCredentialReconciler {
+	// There's no global credential, we only create credentials from AzureCredentials
+	provider := NewCredentialProvider(nil, kubeClient, opts).(*credentialProvider)
+
+	return &AzureCredentialReconciler{
+		kubeClient:            kubeClient,
+		provider:              provider,
+		readyConditions:       conditions.NewReadyConditionBuilder(positiveConditions),
+		permissionsConditions: conditions.NewPermissionsConditionBuilder(positiveConditions),
+	}
+}
+
+// SetPermissionChecker configures the checker used for AzureCredentials with checkPermissions enabled.
+func (r *AzureCredentialReconciler) SetPermissionChecker(checker PermissionChecker) {
+	r.permissionChecker = checker
+}
+
+// SetupWithManager registers the reconciler with mgr
+func (r *AzureCredentialReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&serviceoperator.AzureCredential{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.credentialsUsingSecret)).
+		WithOptions(options).
+		Named("azurecredential").
+		Complete(r)
+	if err != nil {
+		return eris.Wrap(err, "unable to build AzureCredential controller")
+	}
+
+	return nil
+}
+
+func (r *AzureCredentialReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	credential := &serviceoperator.AzureCredential{}
+	err := r.kubeClient.Get(ctx, req.NamespacedName, credential)
+	if err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	credential.Status.SubscriptionID = credential.Spec.SubscriptionID
+	cred, ready := r.checkCredential(ctx, credential)
+	conditions.SetCondition(credential, ready)
+
+	if cred != nil && credential.Spec.CheckPermissions && r.permissionChecker != nil {
+		conditions.SetCondition(credential, r.checkPermissions(ctx, credential, cred))
+	}
+
+	err = r.kubeClient.Status().Update(ctx, credential)
+	if err != nil {
+		return reconcile.Result{}, eris.Wrapf(err, "updating status of AzureCredential %q", req.NamespacedName)
+	}
+
+	return reconcile.Result{RequeueAfter: azureCredentialCheckInterval}, nil
+}
+
+// checkCredential acquires a token for Azure Resource Manager with the credential, returning its Ready condition, and
+// the credential if a token was acquired.
+func (r *AzureCredentialReconciler) checkCredential(
+	ctx context.Context,
+	credential *serviceoperator.AzureCredential,
+) (*Credential, conditions.Condition) {
+	generation := credential.GetGeneration()
+
+	cred, err := r.provider.newCredentialFromAzureCredential(ctx, credential)
+	if err != nil {
+		reason := ReasonInvalidCredential
+		var secretNotFound *core.SecretNotFound
+		if eris.As(err, &secretNotFound) {
+			reason = conditions.ReasonSecretNotFound.Name
+		}
+
+		return nil, r.readyConditions.ReadyCondition(conditions.ConditionSeverityError, generation, reason, err.Error())
+	}
+
+	resourceManager := r.provider.cloud.Services[cloud.ResourceManager]
+	_, err = cred.TokenCredential().GetToken(ctx, policy.TokenRequestOptions{
+		Scopes:   []string{resourceManager.Audience + "/.default"},
+		TenantID: credential.Spec.TenantID,
+	})
+	if err != nil {
+		return nil, r.readyConditions.ReadyCondition(conditions.ConditionSeverityError, generation, ReasonTokenAcquisitionFailed, err.Error())
+	}
+
+	now := metav1.Now()
+	credential.Status.TenantID = credential.Spec.TenantID
+	credential.Status.LastTokenAcquired = &now
+
+	return cred, r.readyConditions.Succeeded(generation)
+}
+
+// checkPermissions checks the credential has the access needed by the resources using it, recording any missing
+// permissions in its status and returning its PermissionsVerified condition.
+func (r *AzureCredentialReconciler) checkPermissions(
+	ctx context.Context,
+	credential *serviceoperator.AzureCredential,
+	cred *Credential,
+) conditions.Condition {
+	generation := credential.GetGeneration()
+
+	missing, err := r.permissionChecker.CheckPermissions(ctx, cred)
+	if err != nil {
+		return r.permissionsConditions.CheckFailed(generation, err)
+	}
+
+	credential.Status.MissingPermissions = missing
+	if len(missing) == 0 {
+		return r.permissionsConditions.Verified(generation)
+	}
+
+	var descriptions []string
+	for _, permission := range missing {
+		for _, action := range permission.Actions {
+			descriptions = append(descriptions, fmt.Sprintf("%s at %s", action, permission.Scope))
+		}
+	}
+
+	return r.permissionsConditions.MissingPermissions(generation, descriptions)
+}
+
+// credentialsUsingSecret returns requests for the AzureCredentials which use the specified secret, so that they're
+// checked again when it changes.
+func (r *AzureCredentialReconciler) credentialsUsingSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	var credentials serviceoperator.AzureCredentialList
+	err := r.kubeClient.List(ctx, &credentials, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "listing AzureCredentials", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, credential := range credentials.Items {
+		if usesSecret(&credential.Spec, obj.GetName()) {
+			result = append(result, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: credential.Namespace, Name: credential.Name},
+			})
+		}
+	}
+
+	return result
+}
+
+// usesSecret returns true if the spec refers to the secret with the specified name.
+func usesSecret(spec *serviceoperator.AzureCredentialSpec, name string) bool {
+	if spec.ClientSecret != nil && spec.ClientSecret.Name == name {
+		return true
+	}
+
+	if cert := spec.ClientCertificate; cert != nil {
+		if cert.Certificate.Name == name {
+			return true
+		}
+
+		if cert.Password != nil && cert.Password.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
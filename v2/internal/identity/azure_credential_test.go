@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package identity
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/google/uuid"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+func newAzureCredential(namespacedName types.NamespacedName, clientSecret string) (*serviceoperator.AzureCredential, *v1.Secret) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespacedName.Namespace,
+			Name:      namespacedName.Name + "-secret",
+		},
+		Data: map[string][]byte{
+			"clientSecret": []byte(clientSecret),
+		},
+	}
+
+	credential := &serviceoperator.AzureCredential{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespacedName.Namespace,
+			Name:      namespacedName.Name,
+		},
+		Spec: serviceoperator.AzureCredentialSpec{
+			SubscriptionID: testSubscriptionID,
+			TenantID:       fakeID,
+			ClientID:       fakeID,
+			ClientSecret: &serviceoperator.SecretKeyReference{
+				Name: secret.Name,
+				Key:  "clientSecret",
+			},
+		},
+	}
+
+	return credential, secret
+}
+
+func TestCredentialProvider_AzureCredentialOnResource_IsReturned(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	res, err := testCredentialProviderSetup(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	clientSecret := uuid.New().String()
+	credential, secret := newAzureCredential(types.NamespacedName{Namespace: "test-namespace", Name: "team-a"}, clientSecret)
+	g.Expect(res.kubeClient.Create(ctx, secret)).To(Succeed())
+	g.Expect(res.kubeClient.Create(ctx, credential)).To(Succeed())
+
+	rg := newResourceGroup("test-namespace")
+	rg.Annotations = map[string]string{annotations.AzureCredential: "team-a"}
+	g.Expect(res.kubeClient.Create(ctx, rg)).To(Succeed())
+
+	cred, err := res.Provider.GetCredential(ctx, rg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cred.SubscriptionID()).To(Equal(testSubscriptionID))
+	g.Expect(cred.CredentialFrom()).To(Equal(types.NamespacedName{Namespace: "test-namespace", Name: "AzureCredential/team-a"}))
+	g.Expect(res.fakeTokenCredentialProvider.ClientSecret).To(Equal(clientSecret))
+}
+
+func TestCredentialProvider_AzureCredentialAndSecretWithSameName_AreDistinct(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	res, err := testCredentialProviderSetup(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	nsName := types.NamespacedName{Namespace: "test-namespace", Name: "team-a"}
+	credential, secret := newAzureCredential(nsName, uuid.New().String())
+	g.Expect(res.kubeClient.Create(ctx, secret)).To(Succeed())
+	g.Expect(res.kubeClient.Create(ctx, credential)).To(Succeed())
+	g.Expect(res.kubeClient.Create(ctx, newSecret(nsName))).To(Succeed())
+
+	fromCredential := newResourceGroup("test-namespace")
+	fromCredential.Annotations = map[string]string{annotations.AzureCredential: nsName.Name}
+	credentialCred, err := res.Provider.GetCredential(ctx, fromCredential)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	fromSecret := newResourceGroup("test-namespace")
+	fromSecret.Annotations = map[string]string{annotations.PerResourceSecret: nsName.Name}
+	secretCred, err := res.Provider.GetCredential(ctx, fromSecret)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Clients are cached by CredentialFrom, so the two mustn't share a client
+	g.Expect(credentialCred.CredentialFrom()).ToNot(Equal(secretCred.CredentialFrom()))
+}
+
+func TestCredentialProvider_AzureCredentialOnNamespace_IsReturned(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	res, err := testCredentialProviderSetup(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	credential, secret := newAzureCredential(types.NamespacedName{Namespace: "platform", Name: "team-a"}, uuid.New().String())
+	credential.Spec.AllowedNamespaces = []string{"test-namespace"}
+	g.Expect(res.kubeClient.Create(ctx, secret)).To(Succeed())
+	g.Expect(res.kubeClient.Create(ctx, credential)).To(Succeed())
+
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-namespace",
+			Annotations: map[string]string{annotations.AzureCredential: "platform/team-a"},
+		},
+	}
+	g.Expect(res.kubeClient.Create(ctx, namespace)).To(Succeed())
+
+	// Takes precedence over the namespace secret
+	g.Expect(res.kubeClient.Create(ctx, newSecret(types.NamespacedName{Namespace: "test-namespace", Name: NamespacedSecretName}))).To(Succeed())
+
+	rg := newResourceGroup("test-namespace")
+	g.Expect(res.kubeClient.Create(ctx, rg)).To(Succeed())
+
+	cred, err := res.Provider.GetCredential(ctx, rg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cred.CredentialFrom()).To(Equal(types.NamespacedName{Namespace: "platform", Name: "AzureCredential/team-a"}))
+}
+
+func TestCredentialProvider_AzureCredentialInOtherNamespace_NotAllowed_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	res, err := testCredentialProviderSetup(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	credential, secret := newAzureCredential(types.NamespacedName{Namespace: "platform", Name: "team-a"}, uuid.New().String())
+	credential.Spec.AllowedNamespaces = []string{"other-namespace"}
+	g.Expect(res.kubeClient.Create(ctx, secret)).To(Succeed())
+	g.Expect(res.kubeClient.Create(ctx, credential)).To(Succeed())
+
+	rg := newResourceGroup("test-namespace")
+	rg.Annotations = map[string]string{annotations.AzureCredential: "platform/team-a"}
+	g.Expect(res.kubeClient.Create(ctx, rg)).To(Succeed())
+
+	_, err = res.Provider.GetCredential(ctx, rg)
+	g.Expect(err).To(MatchError(ContainSubstring(`does not allow use by resources in namespace "test-namespace"`)))
+}
+
+func TestCredentialProvider_AzureCredentialDoesNotExist_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	res, err := testCredentialProviderSetup(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rg := newResourceGroup("test-namespace")
+	rg.Annotations = map[string]string{annotations.AzureCredential: "missing"}
+	g.Expect(res.kubeClient.Create(ctx, rg)).To(Succeed())
+
+	_, err = res.Provider.GetCredential(ctx, rg)
+	g.Expect(err).To(MatchError(ContainSubstring(`AzureCredential "test-namespace/missing" not found`)))
+}
+
+func TestParseAzureCredentialReference(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		reference string
+		expected  types.NamespacedName
+		err       string
+	}{
+		"name": {
+			reference: "team-a",
+			expected:  types.NamespacedName{Namespace: "ns", Name: "team-a"},
+		},
+		"namespace and name": {
+			reference: "platform/team-a",
+			expected:  types.NamespacedName{Namespace: "platform", Name: "team-a"},
+		},
+		"empty": {
+			reference: "",
+			err:       "must be either name or namespace/name",
+		},
+		"too many parts": {
+			reference: "a/b/c",
+			err:       "must be either name or namespace/name",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			actual, err := parseAzureCredentialReference(c.reference, "ns")
+			if c.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.err)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(c.expected))
+		})
+	}
+}
@@ -130,9 +130,11 @@ func NewCredentialProvider(
 // procedure:
 //  1. Per-Resource credential specified as an annotation ("serviceoperator.azure.com/credential-from") directly on
 //     the resource.
-//  2. Per-Namespace credential provided at the namespace scope (a secret named "aso-credential" in the resource's
+//  2. AzureCredential specified as an annotation ("serviceoperator.azure.com/azure-credential") on the resource, or
+//     on the resource's namespace.
+//  3. Per-Namespace credential provided at the namespace scope (a secret named "aso-credential" in the resource's
 //     namespace).
-//  3. Global credential for the operator.
+//  4. Global credential for the operator.
 //
 // If no matching credential can be found, an error is returned.
 func (c *credentialProvider) GetCredential(ctx context.Context, obj genruntime.MetaObject) (*Credential, error) {
@@ -145,6 +147,15 @@ func (c *credentialProvider) GetCredential(ctx context.Context, obj genruntime.M
 		return cred, nil
 	}
 
+	// AzureCredential
+	cred, err = c.getCredentialFromAzureCredentialAnnotation(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if cred != nil {
+		return cred, nil
+	}
+
 	// Namespaced secret
 	cred, err = c.getCredentialFromNamespaceSecret(ctx, obj.GetNamespace())
 	if err != nil {
@@ -223,11 +234,16 @@ func (c *credentialProvider) getCredentialFromSecret(ctx context.Context, secret
 }
 
 func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Credential, error) {
-	var errs []error
-
 	nsName := types.NamespacedName{Namespace: secret.GetNamespace(), Name: secret.GetName()}
+	return c.newCredentialFromData(nsName, secret.Data)
+}
+
+// newCredentialFromData creates a Credential from data keyed in the same way as a credential secret.
+// nsName identifies where the data came from.
+func (c *credentialProvider) newCredentialFromData(nsName types.NamespacedName, data map[string][]byte) (*Credential, error) {
+	var errs []error
 
-	subscriptionIDBytes, ok := secret.Data[config.AzureSubscriptionID]
+	subscriptionIDBytes, ok := data[config.AzureSubscriptionID]
 	if !ok {
 		err := core.NewSecretNotFoundError(
 			nsName,
@@ -239,7 +255,7 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 		errs = append(errs, err)
 	}
 
-	tenantIDBytes, ok := secret.Data[config.AzureTenantID]
+	tenantIDBytes, ok := data[config.AzureTenantID]
 	if !ok {
 		err := core.NewSecretNotFoundError(
 			nsName,
@@ -251,7 +267,7 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 		errs = append(errs, err)
 	}
 
-	clientIDBytes, ok := secret.Data[config.AzureClientID]
+	clientIDBytes, ok := data[config.AzureClientID]
 	if !ok {
 		err := core.NewSecretNotFoundError(
 			nsName,
@@ -269,7 +285,7 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 
 	// Read optional fields
 	var additionalTenants []string
-	additionalTenantsBytes, ok := secret.Data[config.AzureAdditionalTenants]
+	additionalTenantsBytes, ok := data[config.AzureAdditionalTenants]
 	if ok {
 		additionalTenants = config.ParseCommaCollection(string(additionalTenantsBytes))
 	}
@@ -282,7 +298,7 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 		return nil, kerrors.NewAggregate(errs)
 	}
 
-	if clientSecret, hasClientSecret := secret.Data[config.AzureClientSecret]; hasClientSecret {
+	if clientSecret, hasClientSecret := data[config.AzureClientSecret]; hasClientSecret {
 		tokenCredential, err := c.tokenCredentialProvider.NewClientSecretCredential(
 			tenantID,
 			clientID,
@@ -302,13 +318,13 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 			subscriptionID:    subscriptionID,
 			credentialFrom:    nsName,
 			additionalTenants: additionalTenants,
-			secretData:        secret.Data,
+			secretData:        data,
 		}, nil
 	}
 
-	if clientCert, hasClientCert := secret.Data[config.AzureClientCertificate]; hasClientCert {
+	if clientCert, hasClientCert := data[config.AzureClientCertificate]; hasClientCert {
 		var clientCertPassword []byte
-		if p, hasClientCertPassword := secret.Data[config.AzureClientCertificatePassword]; hasClientCertPassword {
+		if p, hasClientCertPassword := data[config.AzureClientCertificatePassword]; hasClientCertPassword {
 			clientCertPassword = p
 		}
 
@@ -332,14 +348,14 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 			subscriptionID:    subscriptionID,
 			credentialFrom:    nsName,
 			additionalTenants: additionalTenants,
-			secretData:        secret.Data,
+			secretData:        data,
 		}, nil
 	}
 
 	// This authentication type is similar to user assigned managed identity authentication combined with client certificate
 	// authentication. As a 1st party Microsoft application, one has access to pull a user assigned managed identity's backing
 	// certificate information from the MSI data plane. Using this data, a user can authenticate to Azure Cloud.
-	if userAssignedCredentialsPath, hasUserAssignedCredentials := secret.Data[config.AzureUserAssignedIdentityCredentials]; hasUserAssignedCredentials {
+	if userAssignedCredentialsPath, hasUserAssignedCredentials := data[config.AzureUserAssignedIdentityCredentials]; hasUserAssignedCredentials {
 		// Default to AzurePublic
 		options := azcore.ClientOptions{
 			Cloud: c.cloud,
@@ -354,11 +370,11 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 			tokenCredential: tokenCredential,
 			subscriptionID:  string(subscriptionID),
 			credentialFrom:  nsName,
-			secretData:      secret.Data,
+			secretData:      data,
 		}, nil
 	}
 
-	if value, hasAuthMode := secret.Data[config.AuthMode]; hasAuthMode {
+	if value, hasAuthMode := data[config.AuthMode]; hasAuthMode {
 		authMode, err := authModeOrDefault(string(value))
 		if err != nil {
 			return nil, eris.Wrap(err, eris.Errorf("invalid identity auth mode for %q encountered", nsName).Error())
@@ -381,7 +397,7 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 				tokenCredential: tokenCredential,
 				subscriptionID:  subscriptionID,
 				credentialFrom:  nsName,
-				secretData:      secret.Data,
+				secretData:      data,
 			}, nil
 		}
 	}
@@ -414,7 +430,7 @@ func (c *credentialProvider) newCredentialFromSecret(secret *v1.Secret) (*Creden
 		subscriptionID:    subscriptionID,
 		credentialFrom:    nsName,
 		additionalTenants: additionalTenants,
-		secretData:        secret.Data,
+		secretData:        data,
 	}, nil
 }
 
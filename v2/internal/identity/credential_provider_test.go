@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/internal/util/to"
@@ -551,6 +552,7 @@ func createTestScheme() *runtime.Scheme {
 
 	_ = v1.AddToScheme(s)
 	_ = resources.AddToScheme(s)
+	_ = serviceoperator.AddToScheme(s)
 
 	return s
 }
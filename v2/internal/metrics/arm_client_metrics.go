@@ -17,6 +17,7 @@ type ARMClientMetrics struct {
 	azureSuccessfulRequestsTotal *prometheus.CounterVec
 	azureFailedRequestsTotal     *prometheus.CounterVec
 	azureRequestsTime            *prometheus.HistogramVec
+	azureRateLimitRemaining      *prometheus.GaugeVec
 }
 
 var _ Metrics = &ARMClientMetrics{}
@@ -37,16 +38,26 @@ func NewARMClientMetrics() *ARMClientMetrics {
 		Help: "Length of time per ARM request",
 	}, []string{"resource", "requestType"})
 
+	azureRateLimitRemaining := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "azure_ratelimit_remaining_requests",
+		Help: "Number of ARM requests remaining in the subscription's budget, as last reported by ARM",
+	}, []string{"subscription", "credential", "requestType"})
+
 	return &ARMClientMetrics{
 		azureSuccessfulRequestsTotal: azureSuccessfulRequestsTotal,
 		azureFailedRequestsTotal:     azureFailedRequestsTotal,
 		azureRequestsTime:            azureRequestsTime,
+		azureRateLimitRemaining:      azureRateLimitRemaining,
 	}
 }
 
 // RegisterMetrics registers the collectors with prometheus server.
 func (a *ARMClientMetrics) RegisterMetrics() {
-	metrics.Registry.MustRegister(a.azureRequestsTime, a.azureSuccessfulRequestsTotal, a.azureFailedRequestsTotal)
+	metrics.Registry.MustRegister(
+		a.azureRequestsTime,
+		a.azureSuccessfulRequestsTotal,
+		a.azureFailedRequestsTotal,
+		a.azureRateLimitRemaining)
 }
 
 // RecordAzureSuccessRequestsTotal records the total successful number requests to ARM by increasing the counter.
@@ -63,3 +74,9 @@ func (a *ARMClientMetrics) RecordAzureFailedRequestsTotal(resourceName string, m
 func (a *ARMClientMetrics) RecordAzureRequestsTime(resourceName string, requestTime time.Duration, method string) {
 	a.azureRequestsTime.WithLabelValues(resourceName, method).Observe(requestTime.Seconds())
 }
+
+// RecordAzureRateLimitRemaining records the number of requests of the specified type (reads or writes) remaining in
+// the ARM budget for the subscription and credential.
+func (a *ARMClientMetrics) RecordAzureRateLimitRemaining(subscription string, credential string, requestType string, remaining int) {
+	a.azureRateLimitRemaining.WithLabelValues(subscription, credential, requestType).Set(float64(remaining))
+}
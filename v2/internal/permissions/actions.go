@@ -0,0 +1,75 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+)
+
+// Allows returns true if the permissions grant the specified management plane action. Matching follows Azure RBAC:
+// actions are case-insensitive, '*' matches any sequence of characters, and an action is only granted by a
+// permission if it matches one of its Actions and none of its NotActions.
+func Allows(permissions []genericarmclient.Permission, action string) bool {
+	for _, permission := range permissions {
+		if matchesAny(permission.Actions, action) && !matchesAny(permission.NotActions, action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MissingActions returns those actions not granted by the permissions, in the order given.
+func MissingActions(permissions []genericarmclient.Permission, actions []string) []string {
+	var result []string
+	for _, action := range actions {
+		if !Allows(permissions, action) {
+			result = append(result, action)
+		}
+	}
+
+	return result
+}
+
+func matchesAny(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if matches(pattern, action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches returns true if the action matches the pattern, which may contain any number of '*' wildcards.
+func matches(pattern string, action string) bool {
+	pattern = strings.ToLower(pattern)
+	action = strings.ToLower(action)
+
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == action
+	}
+
+	// The first part must be a prefix, and the last a suffix, with the others in order between them
+	if !strings.HasPrefix(action, parts[0]) {
+		return false
+	}
+
+	remaining := action[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(remaining, part)
+		if index < 0 {
+			return false
+		}
+
+		remaining = remaining[index+len(part):]
+	}
+
+	return strings.HasSuffix(remaining, parts[len(parts)-1])
+}
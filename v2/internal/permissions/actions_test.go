@@ -0,0 +1,87 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+)
+
+func Test_Matches(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		pattern  string
+		action   string
+		expected bool
+	}{
+		"Exact match":                       {"Microsoft.Storage/storageAccounts/write", "Microsoft.Storage/storageAccounts/write", true},
+		"Differs only by case":              {"microsoft.storage/storageaccounts/WRITE", "Microsoft.Storage/storageAccounts/write", true},
+		"Different action":                  {"Microsoft.Storage/storageAccounts/read", "Microsoft.Storage/storageAccounts/write", false},
+		"Everything":                        {"*", "Microsoft.Storage/storageAccounts/write", true},
+		"Provider wildcard":                 {"Microsoft.Storage/*", "Microsoft.Storage/storageAccounts/write", true},
+		"Other provider wildcard":           {"Microsoft.Network/*", "Microsoft.Storage/storageAccounts/write", false},
+		"Any read":                          {"*/read", "Microsoft.Storage/storageAccounts/read", true},
+		"Any read doesn't allow write":      {"*/read", "Microsoft.Storage/storageAccounts/write", false},
+		"Wildcard in the middle":            {"Microsoft.Network/*/join/action", "Microsoft.Network/virtualNetworks/subnets/join/action", true},
+		"Wildcard in the middle, no suffix": {"Microsoft.Network/*/join/action", "Microsoft.Network/virtualNetworks/subnets/read", false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(matches(c.pattern, c.action)).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_MissingActions_ExcludesNotActions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	permissions := []genericarmclient.Permission{
+		{
+			Actions:    []string{"*"},
+			NotActions: []string{"Microsoft.Authorization/*/write", "*/delete"},
+		},
+	}
+
+	missing := MissingActions(
+		permissions,
+		[]string{
+			"Microsoft.Authorization/roleAssignments/write",
+			"Microsoft.Storage/storageAccounts/write",
+			"Microsoft.Storage/storageAccounts/delete",
+		})
+
+	g.Expect(missing).To(Equal([]string{
+		"Microsoft.Authorization/roleAssignments/write",
+		"Microsoft.Storage/storageAccounts/delete",
+	}))
+}
+
+func Test_MissingActions_GrantedByAnyPermission(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	permissions := []genericarmclient.Permission{
+		{
+			Actions:    []string{"*"},
+			NotActions: []string{"*/delete"},
+		},
+		{
+			Actions: []string{"Microsoft.Storage/storageAccounts/delete"},
+		},
+	}
+
+	missing := MissingActions(permissions, []string{"Microsoft.Storage/storageAccounts/delete"})
+	g.Expect(missing).To(BeEmpty())
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+)
+
+// PermissionsClient lists the permissions the caller has at a scope. It's implemented by
+// genericarmclient.GenericClient.
+type PermissionsClient interface {
+	ListPermissions(ctx context.Context, scope string) ([]genericarmclient.Permission, error)
+}
+
+var _ PermissionsClient = &genericarmclient.GenericClient{}
+
+// MissingPermission is a Requirement that isn't met.
+type MissingPermission struct {
+	// Resource describes the resource needing the access
+	Resource string
+	// Scope is the ARM ID at which the actions are needed
+	Scope string
+	// Actions are the actions needed but not granted
+	Actions []string
+}
+
+// Checker checks that the caller has the access needed by a set of resources, using ARM's
+// Microsoft.Authorization/permissions API.
+type Checker struct {
+	client      PermissionsClient
+	permissions map[string][]genericarmclient.Permission // cached by lowercase scope
+}
+
+// NewChecker creates a new Checker using client.
+func NewChecker(client PermissionsClient) *Checker {
+	return &Checker{
+		client:      client,
+		permissions: make(map[string][]genericarmclient.Permission),
+	}
+}
+
+// Check returns the requirements not met by the permissions of the caller.
+func (c *Checker) Check(ctx context.Context, requirements []Requirement) ([]MissingPermission, error) {
+	var result []MissingPermission
+	for _, requirement := range requirements {
+		permissions, err := c.permissionsAt(ctx, requirement.Scope)
+		if err != nil {
+			return nil, err
+		}
+
+		missing := MissingActions(permissions, requirement.Actions)
+		if len(missing) > 0 {
+			result = append(result, MissingPermission{
+				Resource: requirement.Resource,
+				Scope:    requirement.Scope,
+				Actions:  missing,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// permissionsAt returns the permissions of the caller at scope. Resources that don't exist yet have no permissions
+// of their own, so if scope isn't found we use the permissions at its nearest parent, which the resource will
+// inherit once created.
+func (c *Checker) permissionsAt(ctx context.Context, scope string) ([]genericarmclient.Permission, error) {
+	key := strings.ToLower(scope)
+	if permissions, ok := c.permissions[key]; ok {
+		return permissions, nil
+	}
+
+	permissions, err := c.client.ListPermissions(ctx, scope)
+	if err != nil {
+		if !genericarmclient.IsNotFoundError(err) {
+			return nil, eris.Wrapf(err, "listing permissions at %s", scope)
+		}
+
+		parent, ok := parentScope(scope)
+		if !ok {
+			return nil, eris.Wrapf(err, "listing permissions at %s", scope)
+		}
+
+		permissions, err = c.permissionsAt(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.permissions[key] = permissions
+	return permissions, nil
+}
+
+// parentScope returns the parent of scope, if it's below a subscription.
+func parentScope(scope string) (string, bool) {
+	id, err := arm.ParseResourceID(scope)
+	if err != nil || id.Parent == nil {
+		return "", false
+	}
+
+	if id.ResourceType.String() == arm.SubscriptionResourceType.String() ||
+		id.ResourceType.String() == arm.TenantResourceType.String() {
+		return "", false
+	}
+
+	return id.Parent.String(), true
+}
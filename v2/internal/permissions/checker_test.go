@@ -0,0 +1,146 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+)
+
+const (
+	subscriptionID = "/subscriptions/00000000-0000-0000-0000-000000000000"
+	resourceGroup  = subscriptionID + "/resourceGroups/myrg"
+	storageAccount = resourceGroup + "/providers/Microsoft.Storage/storageAccounts/mystorage"
+)
+
+// fakePermissionsClient returns permissions for the scopes it knows about, and a not found error for others
+type fakePermissionsClient struct {
+	permissions map[string][]genericarmclient.Permission
+	requests    []string
+}
+
+var _ PermissionsClient = &fakePermissionsClient{}
+
+func (f *fakePermissionsClient) ListPermissions(_ context.Context, scope string) ([]genericarmclient.Permission, error) {
+	f.requests = append(f.requests, scope)
+	if permissions, ok := f.permissions[strings.ToLower(scope)]; ok {
+		return permissions, nil
+	}
+
+	return nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}
+}
+
+func Test_Checker_ReportsMissingActions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client := &fakePermissionsClient{
+		permissions: map[string][]genericarmclient.Permission{
+			strings.ToLower(storageAccount): {
+				{Actions: []string{"Microsoft.Storage/storageAccounts/read"}},
+			},
+		},
+	}
+
+	checker := NewChecker(client)
+	missing, err := checker.Check(
+		context.TODO(),
+		[]Requirement{
+			{
+				Resource: "mystorage",
+				Scope:    storageAccount,
+				Actions:  ResourceActions("Microsoft.Storage/storageAccounts", "manage"),
+			},
+		})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(missing).To(Equal([]MissingPermission{
+		{
+			Resource: "mystorage",
+			Scope:    storageAccount,
+			Actions: []string{
+				"Microsoft.Storage/storageAccounts/write",
+				"Microsoft.Storage/storageAccounts/delete",
+			},
+		},
+	}))
+}
+
+func Test_Checker_ScopeNotFound_UsesParentScope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client := &fakePermissionsClient{
+		permissions: map[string][]genericarmclient.Permission{
+			strings.ToLower(subscriptionID): {
+				{Actions: []string{"Microsoft.Storage/*"}},
+			},
+		},
+	}
+
+	checker := NewChecker(client)
+	missing, err := checker.Check(
+		context.TODO(),
+		[]Requirement{
+			{
+				Resource: "mystorage",
+				Scope:    storageAccount,
+				Actions:  ResourceActions("Microsoft.Storage/storageAccounts", "manage"),
+			},
+		})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(missing).To(BeEmpty())
+	g.Expect(client.requests).To(Equal([]string{storageAccount, resourceGroup, subscriptionID}))
+}
+
+func Test_Checker_CachesPermissionsByScope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client := &fakePermissionsClient{
+		permissions: map[string][]genericarmclient.Permission{
+			strings.ToLower(resourceGroup): {
+				{Actions: []string{"*"}},
+			},
+		},
+	}
+
+	requirement := Requirement{
+		Resource: "myrg",
+		Scope:    resourceGroup,
+		Actions:  []string{"Microsoft.Resources/subscriptions/resourceGroups/read"},
+	}
+
+	checker := NewChecker(client)
+	_, err := checker.Check(context.TODO(), []Requirement{requirement, requirement})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(client.requests).To(HaveLen(1))
+}
+
+func Test_Checker_SubscriptionNotFound_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	checker := NewChecker(&fakePermissionsClient{})
+	_, err := checker.Check(
+		context.TODO(),
+		[]Requirement{
+			{
+				Resource: "mystorage",
+				Scope:    storageAccount,
+				Actions:  []string{"Microsoft.Storage/storageAccounts/read"},
+			},
+		})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(subscriptionID))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/identity"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// CredentialChecker checks that a credential has the access needed by the ASO resources in the cluster that use it.
+type CredentialChecker struct {
+	kubeClient         kubeclient.Client
+	resolver           *resolver.Resolver
+	credentialProvider identity.CredentialProvider
+	cloud              cloud.Configuration
+	lists              []func() client.ObjectList
+}
+
+var _ identity.PermissionChecker = &CredentialChecker{}
+
+// NewCredentialChecker creates a new CredentialChecker for the resources of each of the storage types.
+func NewCredentialChecker(
+	kubeClient kubeclient.Client,
+	resolver *resolver.Resolver,
+	credentialProvider identity.CredentialProvider,
+	cloud cloud.Configuration,
+	storageTypes []*registration.StorageType,
+) (*CredentialChecker, error) {
+	scheme := kubeClient.Scheme()
+	lists := make([]func() client.ObjectList, 0, len(storageTypes))
+	for _, storageType := range storageTypes {
+		gvk, err := apiutil.GVKForObject(storageType.Obj, scheme)
+		if err != nil {
+			return nil, eris.Wrapf(err, "finding GVK of %T", storageType.Obj)
+		}
+
+		listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+		if !scheme.Recognizes(listGVK) {
+			return nil, eris.Errorf("scheme doesn't recognize %s", listGVK)
+		}
+
+		lists = append(lists, func() client.ObjectList {
+			// We've already checked the scheme recognizes the list type
+			obj, _ := scheme.New(listGVK)
+			return obj.(client.ObjectList)
+		})
+	}
+
+	return &CredentialChecker{
+		kubeClient:         kubeClient,
+		resolver:           resolver,
+		credentialProvider: credentialProvider,
+		cloud:              cloud,
+		lists:              lists,
+	}, nil
+}
+
+// CheckPermissions returns the permissions credential is missing for the resources that use it.
+func (c *CredentialChecker) CheckPermissions(
+	ctx context.Context,
+	credential *identity.Credential,
+) ([]serviceoperator.MissingPermission, error) {
+	objs, err := c.resourcesUsing(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	var requirements []Requirement
+	for _, obj := range objs {
+		reqs, err := RequirementsFor(ctx, c.resolver, obj, credential.SubscriptionID())
+		if err != nil {
+			// The resource isn't ready to be reconciled (perhaps its owner doesn't exist yet), so it will report its
+			// own problem; we check the others
+			continue
+		}
+
+		requirements = append(requirements, reqs...)
+	}
+
+	armClient, err := genericarmclient.NewGenericClient(c.cloud, credential.TokenCredential(), nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "creating ARM client")
+	}
+
+	missing, err := NewChecker(armClient).Check(ctx, requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]serviceoperator.MissingPermission, 0, len(missing))
+	for _, m := range missing {
+		result = append(result, serviceoperator.MissingPermission{
+			Resource: m.Resource,
+			Scope:    m.Scope,
+			Actions:  m.Actions,
+		})
+	}
+
+	return result, nil
+}
+
+// resourcesUsing returns the ASO resources in the cluster managed with credential.
+func (c *CredentialChecker) resourcesUsing(
+	ctx context.Context,
+	credential *identity.Credential,
+) ([]genruntime.ARMMetaObject, error) {
+	var result []genruntime.ARMMetaObject
+	for _, newList := range c.lists {
+		list := newList()
+		err := c.kubeClient.List(ctx, list)
+		if err != nil {
+			return nil, eris.Wrapf(err, "listing %T", list)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, eris.Wrapf(err, "extracting items of %T", list)
+		}
+
+		for _, item := range items {
+			obj, ok := item.(genruntime.ARMMetaObject)
+			if !ok || !obj.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+
+			used, err := c.credentialProvider.GetCredential(ctx, obj)
+			if err != nil || used.CredentialFrom() != credential.CredentialFrom() {
+				continue
+			}
+
+			result = append(result, obj)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+// Requirement is a set of management plane actions needed at a scope.
+type Requirement struct {
+	// Resource describes the resource needing the access, for reporting
+	Resource string
+	// Scope is the ARM ID at which the actions are needed
+	Scope string
+	// Actions are the actions needed at the scope
+	Actions []string
+}
+
+// linkedActions are the actions needed to link a resource to an existing resource of each type, keyed by the
+// lowercase resource type. Without these ARM rejects the request with a LinkedAuthorizationFailed error.
+var linkedActions = map[string]string{
+	"microsoft.network/applicationsecuritygroups":              "Microsoft.Network/applicationSecurityGroups/joinIpConfiguration/action",
+	"microsoft.network/loadbalancers/backendaddresspools":      "Microsoft.Network/loadBalancers/backendAddressPools/join/action",
+	"microsoft.network/loadbalancers/frontendipconfigurations": "Microsoft.Network/loadBalancers/frontendIPConfigurations/join/action",
+	"microsoft.network/loadbalancers/inboundnatpools":          "Microsoft.Network/loadBalancers/inboundNatPools/join/action",
+	"microsoft.network/loadbalancers/inboundnatrules":          "Microsoft.Network/loadBalancers/inboundNatRules/join/action",
+	"microsoft.network/networkinterfaces":                      "Microsoft.Network/networkInterfaces/join/action",
+	"microsoft.network/networksecuritygroups":                  "Microsoft.Network/networkSecurityGroups/join/action",
+	"microsoft.network/privatednszones":                        "Microsoft.Network/privateDnsZones/join/action",
+	"microsoft.network/publicipaddresses":                      "Microsoft.Network/publicIPAddresses/join/action",
+	"microsoft.network/publicipprefixes":                       "Microsoft.Network/publicIPPrefixes/join/action",
+	"microsoft.network/routetables":                            "Microsoft.Network/routeTables/join/action",
+	"microsoft.network/virtualnetworks/subnets":                "Microsoft.Network/virtualNetworks/subnets/join/action",
+	"microsoft.managedidentity/userassignedidentities":         "Microsoft.ManagedIdentity/userAssignedIdentities/assign/action",
+}
+
+// actionPrefixes are the prefixes of actions on resource types whose actions don't start with the type itself, keyed
+// by the lowercase resource type.
+var actionPrefixes = map[string]string{
+	"microsoft.resources/resourcegroups": "Microsoft.Resources/subscriptions/resourceGroups",
+}
+
+// ResourceActions returns the actions needed to reconcile a resource of the specified ARM type with the specified
+// reconcile policy.
+func ResourceActions(resourceType string, policy annotations.ReconcilePolicyValue) []string {
+	prefix := actionPrefix(resourceType)
+	result := []string{prefix + "/read"}
+	if policy.AllowsModify() {
+		result = append(result, prefix+"/write")
+	}
+
+	if policy.AllowsDelete() {
+		result = append(result, prefix+"/delete")
+	}
+
+	return result
+}
+
+// ReferenceActions returns the actions needed on a resource of the specified ARM type for another resource to refer
+// to it.
+func ReferenceActions(resourceType string) []string {
+	if action, ok := linkedActions[strings.ToLower(resourceType)]; ok {
+		return []string{action}
+	}
+
+	return []string{actionPrefix(resourceType) + "/read"}
+}
+
+func actionPrefix(resourceType string) string {
+	if prefix, ok := actionPrefixes[strings.ToLower(resourceType)]; ok {
+		return prefix
+	}
+
+	return resourceType
+}
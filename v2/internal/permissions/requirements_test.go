@@ -0,0 +1,77 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+func Test_ResourceActions(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		policy   annotations.ReconcilePolicyValue
+		expected []string
+	}{
+		"Manage": {
+			annotations.ReconcilePolicyManage,
+			[]string{
+				"Microsoft.Storage/storageAccounts/read",
+				"Microsoft.Storage/storageAccounts/write",
+				"Microsoft.Storage/storageAccounts/delete",
+			},
+		},
+		"Detach on delete": {
+			annotations.ReconcilePolicyDetachOnDelete,
+			[]string{
+				"Microsoft.Storage/storageAccounts/read",
+				"Microsoft.Storage/storageAccounts/write",
+			},
+		},
+		"Skip": {
+			annotations.ReconcilePolicySkip,
+			[]string{
+				"Microsoft.Storage/storageAccounts/read",
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(ResourceActions("Microsoft.Storage/storageAccounts", c.policy)).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_ReferenceActions(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		resourceType string
+		expected     []string
+	}{
+		"Subnet":                 {"Microsoft.Network/virtualNetworks/subnets", []string{"Microsoft.Network/virtualNetworks/subnets/join/action"}},
+		"Subnet, differing case": {"microsoft.network/virtualnetworks/subnets", []string{"Microsoft.Network/virtualNetworks/subnets/join/action"}},
+		"User assigned identity": {"Microsoft.ManagedIdentity/userAssignedIdentities", []string{"Microsoft.ManagedIdentity/userAssignedIdentities/assign/action"}},
+		"Anything else is read":  {"Microsoft.KeyVault/vaults", []string{"Microsoft.KeyVault/vaults/read"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(ReferenceActions(c.resourceType)).To(Equal(c.expected))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"context"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+)
+
+// ResourceSet is a HierarchyResolver for a fixed set of resources, such as those read from YAML files, allowing their
+// ARM IDs to be calculated without a cluster. Owners and references must either be in the set or use ARM IDs.
+type ResourceSet struct {
+	resources map[resourceKey]genruntime.ARMMetaObject
+}
+
+var _ HierarchyResolver = &ResourceSet{}
+
+type resourceKey struct {
+	groupKind schema.GroupKind
+	name      types.NamespacedName
+}
+
+// NewResourceSet creates a new ResourceSet containing objs.
+func NewResourceSet(scheme *runtime.Scheme, objs []genruntime.ARMMetaObject) (*ResourceSet, error) {
+	result := &ResourceSet{
+		resources: make(map[resourceKey]genruntime.ARMMetaObject, len(objs)),
+	}
+
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, eris.Wrapf(err, "finding GVK of %s", obj.GetName())
+		}
+
+		key := resourceKey{
+			groupKind: gvk.GroupKind(),
+			name:      types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		result.resources[key] = obj
+	}
+
+	return result, nil
+}
+
+// ResolveResourceHierarchy returns the hierarchy of obj, from its uppermost owner in the set down to obj itself.
+func (s *ResourceSet) ResolveResourceHierarchy(ctx context.Context, obj genruntime.ARMMetaObject) (resolver.ResourceHierarchy, error) {
+	owner := obj.Owner()
+	if owner == nil || owner.IsDirectARMReference() {
+		return resolver.ResourceHierarchy{obj}, nil
+	}
+
+	ownerObj, err := s.ResolveReference(ctx, owner.AsNamespacedRef(owner.TargetNamespace(obj.GetNamespace())))
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := s.ResolveResourceHierarchy(ctx, ownerObj)
+	if err != nil {
+		return nil, eris.Wrapf(err, "getting owners for %s", ownerObj.GetName())
+	}
+
+	return append(owners, obj), nil
+}
+
+// ResolveReference returns the resource in the set that ref refers to.
+func (s *ResourceSet) ResolveReference(_ context.Context, ref genruntime.NamespacedResourceReference) (genruntime.ARMMetaObject, error) {
+	name := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	key := resourceKey{
+		groupKind: ref.GroupKind(),
+		name:      name,
+	}
+
+	if obj, ok := s.resources[key]; ok {
+		return obj, nil
+	}
+
+	return nil, core.NewReferenceNotFoundError(
+		name,
+		eris.Errorf("%s is not one of the resources provided", ref.GroupKind()))
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// HierarchyResolver finds the resources related to a resource. It's implemented by resolver.Resolver for resources
+// in a cluster, and by ResourceSet for resources that haven't been applied to a cluster.
+type HierarchyResolver interface {
+	ResolveResourceHierarchy(ctx context.Context, obj genruntime.ARMMetaObject) (resolver.ResourceHierarchy, error)
+	ResolveReference(ctx context.Context, ref genruntime.NamespacedResourceReference) (genruntime.ARMMetaObject, error)
+}
+
+var _ HierarchyResolver = &resolver.Resolver{}
+
+// RequirementsFor returns the access needed to reconcile obj with a credential for the specified subscription:
+// access to the resource itself, and to each resource it refers to.
+func RequirementsFor(
+	ctx context.Context,
+	res HierarchyResolver,
+	obj genruntime.ARMMetaObject,
+	subscriptionID string,
+) ([]Requirement, error) {
+	description := describe(obj)
+
+	armID, err := fullyQualifiedARMID(ctx, res, obj, subscriptionID)
+	if err != nil {
+		return nil, eris.Wrapf(err, "finding ARM ID of %s", description)
+	}
+
+	policy, err := reconcilers.ReconcilePolicyOf(obj, annotations.ReconcilePolicyManage)
+	if err != nil {
+		return nil, eris.Wrapf(err, "checking reconcile policy of %s", description)
+	}
+
+	result := []Requirement{
+		{
+			Resource: description,
+			Scope:    armID,
+			Actions:  ResourceActions(obj.GetType(), policy),
+		},
+	}
+
+	if policy.IsReadOnly() {
+		// We never send the references to ARM
+		return result, nil
+	}
+
+	refs, err := reflecthelpers.FindResourceReferences(obj)
+	if err != nil {
+		return nil, eris.Wrapf(err, "finding references of %s", description)
+	}
+
+	for ref := range refs {
+		refID := ref.ARMID
+		if ref.IsKubernetesReference() {
+			namespacedRef := ref.AsNamespacedRef(ref.TargetNamespace(obj.GetNamespace()))
+			refObj, err := res.ResolveReference(ctx, namespacedRef)
+			if err != nil {
+				return nil, eris.Wrapf(err, "resolving reference %s of %s", ref.String(), description)
+			}
+
+			refID, err = fullyQualifiedARMID(ctx, res, refObj, subscriptionID)
+			if err != nil {
+				return nil, eris.Wrapf(err, "finding ARM ID of reference %s of %s", ref.String(), description)
+			}
+		}
+
+		id, err := arm.ParseResourceID(refID)
+		if err != nil {
+			return nil, eris.Wrapf(err, "parsing ARM ID of reference %s of %s", ref.String(), description)
+		}
+
+		result = append(result, Requirement{
+			Resource: description,
+			Scope:    refID,
+			Actions:  ReferenceActions(id.ResourceType.String()),
+		})
+	}
+
+	return result, nil
+}
+
+func fullyQualifiedARMID(
+	ctx context.Context,
+	res HierarchyResolver,
+	obj genruntime.ARMMetaObject,
+	subscriptionID string,
+) (string, error) {
+	hierarchy, err := res.ResolveResourceHierarchy(ctx, obj)
+	if err != nil {
+		return "", err
+	}
+
+	// A resource group that hasn't been created yet doesn't have its ID recorded, so we work it out
+	root := hierarchy[0]
+	if _, ok := genruntime.GetResourceID(root); !ok && root.GetResourceScope() == genruntime.ResourceScopeLocation {
+		root = root.DeepCopyObject().(genruntime.ARMMetaObject)
+		genruntime.SetResourceID(root, genericarmclient.MakeResourceGroupID(subscriptionID, root.AzureName()))
+		hierarchy = append(resolver.ResourceHierarchy{root}, hierarchy[1:]...)
+	}
+
+	return hierarchy.FullyQualifiedARMID(subscriptionID)
+}
+
+// describe returns a description of obj for reporting, as the objects we get from a cluster don't reliably have
+// their TypeMeta populated.
+func describe(obj genruntime.ARMMetaObject) string {
+	return fmt.Sprintf("%s %s/%s", obj.GetType(), obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package permissions
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	network "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+const testNamespace = "testnamespace"
+
+func createTestResources() []genruntime.ARMMetaObject {
+	rg := &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "myrg", Namespace: testNamespace},
+		Spec: resources.ResourceGroup_Spec{
+			AzureName: "myrg",
+			Location:  to.Ptr("westus"),
+		},
+	}
+
+	vnet := &network.VirtualNetwork{
+		ObjectMeta: metav1.ObjectMeta{Name: "myvnet", Namespace: testNamespace},
+		Spec: network.VirtualNetwork_Spec{
+			AzureName: "myvnet",
+			Owner:     &genruntime.KnownResourceReference{Name: "myrg"},
+		},
+	}
+
+	subnet := &network.VirtualNetworksSubnet{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysubnet", Namespace: testNamespace},
+		Spec: network.VirtualNetworksSubnet_Spec{
+			AzureName: "mysubnet",
+			Owner:     &genruntime.KnownResourceReference{Name: "myvnet"},
+		},
+	}
+
+	nic := &network.NetworkInterface{
+		ObjectMeta: metav1.ObjectMeta{Name: "mynic", Namespace: testNamespace},
+		Spec: network.NetworkInterface_Spec{
+			AzureName: "mynic",
+			Owner:     &genruntime.KnownResourceReference{Name: "myrg"},
+			IpConfigurations: []network.NetworkInterfaceIPConfiguration_NetworkInterface_SubResourceEmbedded{
+				{
+					Name: to.Ptr("ipconfig"),
+					Subnet: &network.Subnet_NetworkInterface_SubResourceEmbedded{
+						Reference: &genruntime.ResourceReference{
+							Group: network.GroupVersion.Group,
+							Kind:  "VirtualNetworksSubnet",
+							Name:  "mysubnet",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return []genruntime.ARMMetaObject{rg, vnet, subnet, nic}
+}
+
+func createTestResourceSet(g *WithT, objs []genruntime.ARMMetaObject) *ResourceSet {
+	scheme := runtime.NewScheme()
+	g.Expect(resources.AddToScheme(scheme)).To(Succeed())
+	g.Expect(network.AddToScheme(scheme)).To(Succeed())
+
+	set, err := NewResourceSet(scheme, objs)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return set
+}
+
+func Test_RequirementsFor_IncludesReferences(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	objs := createTestResources()
+	set := createTestResourceSet(g, objs)
+
+	requirements, err := RequirementsFor(context.TODO(), set, objs[3], "00000000-0000-0000-0000-000000000000")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requirements).To(Equal([]Requirement{
+		{
+			Resource: "Microsoft.Network/networkInterfaces testnamespace/mynic",
+			Scope:    resourceGroup + "/providers/Microsoft.Network/networkInterfaces/mynic",
+			Actions: []string{
+				"Microsoft.Network/networkInterfaces/read",
+				"Microsoft.Network/networkInterfaces/write",
+				"Microsoft.Network/networkInterfaces/delete",
+			},
+		},
+		{
+			Resource: "Microsoft.Network/networkInterfaces testnamespace/mynic",
+			Scope:    resourceGroup + "/providers/Microsoft.Network/virtualNetworks/myvnet/subnets/mysubnet",
+			Actions:  []string{"Microsoft.Network/virtualNetworks/subnets/join/action"},
+		},
+	}))
+}
+
+func Test_RequirementsFor_ReadOnlyPolicy_OnlyNeedsRead(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	objs := createTestResources()
+	objs[3].SetAnnotations(map[string]string{annotations.ReconcilePolicy: string(annotations.ReconcilePolicySkip)})
+	set := createTestResourceSet(g, objs)
+
+	requirements, err := RequirementsFor(context.TODO(), set, objs[3], "00000000-0000-0000-0000-000000000000")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requirements).To(HaveLen(1))
+	g.Expect(requirements[0].Actions).To(Equal([]string{"Microsoft.Network/networkInterfaces/read"}))
+}
+
+func Test_RequirementsFor_OwnerNotInSet_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	objs := createTestResources()
+	set := createTestResourceSet(g, objs[1:]) // Without the resource group
+
+	_, err := RequirementsFor(context.TODO(), set, objs[3], "00000000-0000-0000-0000-000000000000")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("testnamespace/myrg does not exist"))
+}
@@ -10,4 +10,9 @@ const (
 	PollerResumeTokenAnnotation = "serviceoperator.azure.com/poller-resume-token"
 	PollerResumeIDAnnotation    = "serviceoperator.azure.com/poller-resume-id"
 	LatestReconciledGeneration  = "serviceoperator.azure.com/latest-reconciled-generation"
+	LatestReconciledSecrets     = "serviceoperator.azure.com/latest-reconciled-secrets"
+	LastAppliedAnnotation       = "serviceoperator.azure.com/last-applied"
+	CreationClaimAnnotation     = "serviceoperator.azure.com/creation-claim"
+	ActionRunAnnotation         = "serviceoperator.azure.com/action-run"
+	ActionExportAnnotation      = "serviceoperator.azure.com/action-export-pending"
 )
@@ -6,8 +6,11 @@ Licensed under the MIT license.
 package arm
 
 import (
+	"encoding/json"
 	"strconv"
 
+	"github.com/rotisserie/eris"
+
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 )
@@ -31,6 +34,8 @@ func ClearPollerResumeToken(obj genruntime.MetaObject) {
 	genruntime.RemoveAnnotation(obj, reconcilers.PollerResumeIDAnnotation)
 }
 
+// SetLatestReconciledGeneration records that the current generation of the resource has been applied to Azure, either
+// by a PUT that Azure has accepted, or because the resource in Azure already matched it.
 func SetLatestReconciledGeneration(obj genruntime.MetaObject) {
 	genruntime.AddAnnotation(obj, reconcilers.LatestReconciledGeneration, strconv.FormatInt(obj.GetGeneration(), 10))
 }
@@ -43,3 +48,65 @@ func GetLatestReconciledGeneration(obj genruntime.MetaObject) (int64, bool) {
 	}
 	return int64(gen), hasGeneration
 }
+
+// SetLatestReconciledSecrets records a hash of the secret values applied to Azure along with the generation (see
+// SetLatestReconciledGeneration). The record is removed if the resource refers to no secrets.
+func SetLatestReconciledSecrets(obj genruntime.MetaObject, secretsHash string) {
+	if secretsHash == "" {
+		genruntime.RemoveAnnotation(obj, reconcilers.LatestReconciledSecrets)
+		return
+	}
+
+	genruntime.AddAnnotation(obj, reconcilers.LatestReconciledSecrets, secretsHash)
+}
+
+// GetLatestReconciledSecrets returns the hash of the secret values last applied to Azure, see SetLatestReconciledSecrets
+func GetLatestReconciledSecrets(obj genruntime.MetaObject) string {
+	return obj.GetAnnotations()[reconcilers.LatestReconciledSecrets]
+}
+
+// SetCreationClaim records that we're about to create the resource in Azure. The UID of the resource is recorded, so
+// that a copy of the resource (with its annotations) doesn't inherit the claim.
+func SetCreationClaim(obj genruntime.MetaObject) {
+	genruntime.AddAnnotation(obj, reconcilers.CreationClaimAnnotation, string(obj.GetUID()))
+}
+
+// HasCreationClaim returns true if we've previously claimed the creation of the resource in Azure, see SetCreationClaim.
+func HasCreationClaim(obj genruntime.MetaObject) bool {
+	uid, ok := obj.GetAnnotations()[reconcilers.CreationClaimAnnotation]
+	return ok && uid == string(obj.GetUID())
+}
+
+// SetLastApplied records what was sent to Azure at the merge paths of the resource, keyed by path (see
+// jsondiff.Applied). Only the names of properties are recorded, never their values, as the annotation is stored in
+// plain text and values may be secrets. The record is removed if nothing was sent.
+func SetLastApplied(obj genruntime.MetaObject, applied map[string][]string) error {
+	if len(applied) == 0 {
+		genruntime.RemoveAnnotation(obj, reconcilers.LastAppliedAnnotation)
+		return nil
+	}
+
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return eris.Wrap(err, "serializing last applied properties")
+	}
+
+	genruntime.AddAnnotation(obj, reconcilers.LastAppliedAnnotation, string(data))
+	return nil
+}
+
+// GetLastApplied returns what was last sent to Azure at the merge paths of the resource, keyed by path
+func GetLastApplied(obj genruntime.MetaObject) (map[string][]string, error) {
+	val, ok := obj.GetAnnotations()[reconcilers.LastAppliedAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var result map[string][]string
+	err := json.Unmarshal([]byte(val), &result)
+	if err != nil {
+		return nil, eris.Wrapf(err, "parsing %s annotation", reconcilers.LastAppliedAnnotation)
+	}
+
+	return result, nil
+}
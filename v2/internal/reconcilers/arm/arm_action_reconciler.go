@@ -0,0 +1,598 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/go-logr/logr"
+	"github.com/rotisserie/eris"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm/errorclassification"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/internal/util/schedule"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+const (
+	armActionBegin    = "BeginAction"
+	armActionComplete = "ActionCompleted"
+	armActionFailed   = "ActionFailed"
+)
+
+var _ genruntime.Reconciler = &ARMActionReconciler{}
+
+// ARMActionReconciler reconciles an ARMAction, invoking the action on its owner in Azure each time a run is due.
+type ARMActionReconciler struct {
+	reconcilers.ARMOwnedResourceReconcilerCommon
+	ARMConnectionFactory ARMConnectionFactory
+}
+
+func NewARMActionReconciler(
+	armConnectionFactory ARMConnectionFactory,
+	kubeClient kubeclient.Client,
+	resourceResolver *resolver.Resolver,
+	positiveConditions *conditions.PositiveConditionBuilder,
+	expressionEvaluator asocel.ExpressionEvaluator,
+) *ARMActionReconciler {
+	return &ARMActionReconciler{
+		ARMConnectionFactory: armConnectionFactory,
+		ARMOwnedResourceReconcilerCommon: reconcilers.ARMOwnedResourceReconcilerCommon{
+			ResourceResolver: resourceResolver,
+			ReconcilerCommon: reconcilers.ReconcilerCommon{
+				KubeClient:          kubeClient,
+				PositiveConditions:  positiveConditions,
+				ExpressionEvaluator: expressionEvaluator,
+			},
+		},
+	}
+}
+
+// actionTarget is the resource in Azure an action is invoked on, and how to reach it
+type actionTarget struct {
+	connection Connection
+	id         string
+	apiVersion string
+}
+
+func (r *ARMActionReconciler) CreateOrUpdate(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	obj genruntime.MetaObject,
+) (ctrl.Result, error) {
+	action, err := r.asAction(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// If a run is already underway, check on its progress
+	if _, _, hasToken := GetPollerResumeToken(action); hasToken {
+		return r.monitorRun(ctx, log, eventRecorder, action)
+	}
+
+	// If a run succeeded but its result couldn't be exported, try again
+	if _, pending := action.GetAnnotations()[reconcilers.ActionExportAnnotation]; pending {
+		return r.completeRun(ctx, log, eventRecorder, action)
+	}
+
+	now := time.Now()
+	next, err := nextRunTime(action)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !isRunDue(action, next, now) {
+		return r.waitForNextRun(log, action, next, now), nil
+	}
+
+	// Each run invokes the action at most once. A scheduled run which started is never due again (see nextRunTime), but
+	// a generation may have started before without completing. If so, we can't tell whether Azure acted on it, so we
+	// don't invoke it again.
+	run := runKey(action, now)
+	if action.GetAnnotations()[reconcilers.ActionRunAnnotation] == run {
+		err = eris.Errorf(
+			"action %s was invoked for generation %d but didn't complete successfully. To avoid invoking it twice, it won't be invoked again until the ARMAction changes",
+			action.Spec.Action,
+			action.GetGeneration())
+		return ctrl.Result{}, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	return r.beginRun(ctx, log, eventRecorder, action, run)
+}
+
+func (r *ARMActionReconciler) Delete(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	obj genruntime.MetaObject,
+) (ctrl.Result, error) {
+	// Actions can't be undone, so there's nothing to do in Azure. Any run underway is left to complete.
+	log.V(Status).Info("Deleted ARMAction")
+	return ctrl.Result{}, nil
+}
+
+func (r *ARMActionReconciler) Claim(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	obj genruntime.MetaObject,
+) error {
+	action, err := r.asAction(obj)
+	if err != nil {
+		return err
+	}
+
+	return r.ClaimResource(ctx, log, action)
+}
+
+func (r *ARMActionReconciler) UpdateStatus(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	obj genruntime.MetaObject,
+) error {
+	// The reconcile policy doesn't allow changes in Azure, so runs are paused; there's no state in Azure to refresh
+	log.V(Status).Info("Not running action due to reconcile policy")
+	return nil
+}
+
+func (r *ARMActionReconciler) asAction(obj genruntime.MetaObject) (*serviceoperator.ARMAction, error) {
+	typedObj, ok := obj.(*serviceoperator.ARMAction)
+	if !ok {
+		return nil, eris.Errorf("cannot modify resource that is not of type *serviceoperator.ARMAction. Type is %T", obj)
+	}
+
+	return typedObj, nil
+}
+
+// beginRun invokes the action in Azure, completing the run straight away if Azure does.
+func (r *ARMActionReconciler) beginRun(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	action *serviceoperator.ARMAction,
+	run string,
+) (ctrl.Result, error) {
+	target, err := r.resolveTarget(ctx, action)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// If the subscription is running out of write budget, defer the run so that other work can continue without
+	// tipping the subscription into throttling.
+	if delay := target.connection.Client().RateLimitDelay(target.id, true); delay > 0 {
+		log.V(Status).Info("ARM write budget for subscription is low, deferring action", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	var body interface{}
+	if action.Spec.Body != nil {
+		body = action.Spec.Body
+	}
+
+	// Record the generation being run, so that we know whether it's changed once the run completes
+	SetLatestReconciledGeneration(action)
+
+	// Commit the start of the run before invoking the action, so that the action isn't invoked a second time for the
+	// same run if we fail to record its outcome (for example, if the operator restarts).
+	genruntime.AddAnnotation(action, reconcilers.ActionRunAnnotation, run)
+	err = r.KubeClient.CommitObject(ctx, action, kubeclient.SpecOnly)
+	if err != nil {
+		return ctrl.Result{}, eris.Wrapf(err, "recording start of run of action %s", action.Spec.Action)
+	}
+
+	log.V(Status).Info("About to invoke action", "action", action.Spec.Action, "resourceID", target.id)
+	pollerResp, err := target.connection.Client().BeginPostByID(ctx, target.id, action.Spec.Action, target.apiVersion, body)
+	if err != nil {
+		return r.handleRunFailed(log, eventRecorder, action, err, !wasRejected(err))
+	}
+
+	eventRecorder.Eventf(action, v1.EventTypeNormal, armActionBegin, "Invoked action %s on %q", action.Spec.Action, target.id)
+
+	// If we are done here it means the action succeeded immediately. It can't have failed because if it did
+	// we would have taken the error path above.
+	if pollerResp.Poller.Done() {
+		result, err := pollerResp.Poller.Result(ctx)
+		if err != nil {
+			return r.handleRunFailed(log, eventRecorder, action, err, true)
+		}
+
+		return r.handleRunSucceeded(ctx, log, eventRecorder, action, result)
+	}
+
+	resumeToken, err := pollerResp.Poller.ResumeToken()
+	if err != nil {
+		return ctrl.Result{}, eris.Wrapf(err, "couldn't create POST resume token for action %s on %q", action.Spec.Action, target.id)
+	}
+
+	SetPollerResumeToken(action, pollerResp.ID, resumeToken)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// monitorRun checks on the progress of a run which didn't complete straight away.
+func (r *ARMActionReconciler) monitorRun(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	action *serviceoperator.ARMAction,
+) (ctrl.Result, error) {
+	pollerID, pollerResumeToken, _ := GetPollerResumeToken(action)
+	if pollerID != genericarmclient.ActionPollerID {
+		return ctrl.Result{}, eris.Errorf("cannot monitor action with pollerID=%s", pollerID)
+	}
+
+	target, err := r.resolveTarget(ctx, action)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	poller := target.connection.Client().ResumeActionPoller(pollerID)
+	err = poller.Resume(ctx, target.connection.Client(), pollerResumeToken)
+	if err != nil {
+		return r.handleRunFailed(log, eventRecorder, action, err, true)
+	}
+
+	if poller.Poller.Done() {
+		return r.handleRunSucceeded(ctx, log, eventRecorder, action, poller.Result)
+	}
+
+	// Requeue to check again later
+	retryAfter := genericarmclient.GetRetryAfter(poller.RawResponse)
+	log.V(Debug).Info("Action not complete yet, will check again", "requeueAfter", retryAfter)
+
+	// Normally don't need to set both of these fields but because retryAfter can be 0 we do
+	return ctrl.Result{Requeue: true, RequeueAfter: retryAfter}, nil
+}
+
+// handleRunFailed records the failure of a run. invoked is false if Azure rejected the action outright, in which case
+// the run may be attempted again. Otherwise Azure may have acted on the action, so it isn't invoked again for the same
+// run: an action without a schedule waits to be changed, and one with a schedule waits for its next scheduled time
+// (as does a scheduled action which failed with a fatal error).
+func (r *ARMActionReconciler) handleRunFailed(
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	action *serviceoperator.ARMAction,
+	err error,
+	invoked bool,
+) (ctrl.Result, error) {
+	log.V(Debug).Info(
+		"Action failure",
+		"action", action.Spec.Action,
+		"invoked", invoked,
+		"error", err.Error())
+
+	ClearPollerResumeToken(action)
+	err = errorclassification.MakeReadyConditionImpactingErrorFromError(err, errorclassification.ClassifyCloudError)
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	if !ok {
+		return ctrl.Result{}, err
+	}
+
+	retryable := readyErr.Severity != conditions.ConditionSeverityError
+	if !invoked && (retryable || action.Spec.Schedule == "") {
+		// Azure didn't act on the action, so the run can safely be attempted again
+		genruntime.RemoveAnnotation(action, reconcilers.ActionRunAnnotation)
+		return ctrl.Result{}, err
+	}
+
+	if action.Spec.Schedule == "" {
+		return ctrl.Result{}, err
+	}
+
+	// The run is over, so record its failure and wait for the next scheduled time
+	eventRecorder.Eventf(action, v1.EventTypeWarning, armActionFailed, "Action %s failed: %s", action.Spec.Action, readyErr.Cause().Error())
+
+	now := time.Now()
+	next, nextErr := nextRunTime(action)
+	if nextErr != nil {
+		return ctrl.Result{}, nextErr
+	}
+
+	if next.IsZero() {
+		// No more runs are scheduled, so there's nothing to requeue for
+		action.Status.NextRunTime = nil
+		return ctrl.Result{}, err
+	}
+
+	log.V(Status).Info("Action failed, waiting for next scheduled run", "nextRun", next)
+	action.Status.NextRunTime = &metav1.Time{Time: next}
+	conditions.SetCondition(action, r.PositiveConditions.Ready.ReadyCondition(
+		readyErr.Severity,
+		action.GetGeneration(),
+		readyErr.Reason,
+		readyErr.Cause().Error()))
+
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// handleRunSucceeded records the result of a successful run, and completes the run by exporting it. The result is
+// kept until it has been exported, so that it isn't lost if exporting fails (Azure may have acted on the action in a
+// way that can't be repeated, such as regenerating a key).
+func (r *ARMActionReconciler) handleRunSucceeded(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	action *serviceoperator.ARMAction,
+	result json.RawMessage,
+) (ctrl.Result, error) {
+	ClearPollerResumeToken(action)
+
+	action.Status.Result = actionResult(result)
+	genruntime.AddAnnotation(action, reconcilers.ActionExportAnnotation, action.GetAnnotations()[reconcilers.ActionRunAnnotation])
+
+	return r.completeRun(ctx, log, eventRecorder, action)
+}
+
+// completeRun exports the result of a successful run, recording the run as complete once it has been exported. If
+// exporting fails, the run remains incomplete and the export is attempted again when next reconciled.
+func (r *ARMActionReconciler) completeRun(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	action *serviceoperator.ARMAction,
+) (ctrl.Result, error) {
+	err := r.saveAssociatedKubernetesResources(ctx, log, action)
+	if err != nil {
+		return ctrl.Result{}, eris.Wrapf(err, "exporting result of action %s", action.Spec.Action)
+	}
+
+	now := time.Now()
+	generation, _ := GetLatestReconciledGeneration(action)
+	action.Status.LastRunGeneration = generation
+	action.Status.LastRunTime = &metav1.Time{Time: now}
+	genruntime.RemoveAnnotation(action, reconcilers.ActionExportAnnotation)
+
+	// Results may be sensitive (for example, regenerated keys), so they're only kept in status if asked for
+	if !action.RecordsResult() {
+		action.Status.Result = nil
+	}
+
+	log.V(Status).Info("Action completed", "action", action.Spec.Action)
+	eventRecorder.Eventf(action, v1.EventTypeNormal, armActionComplete, "Action %s completed", action.Spec.Action)
+
+	next, err := nextRunTime(action)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// If the ARMAction changed while it was running, it may need to run again straight away
+	if isRunDue(action, next, now) {
+		log.V(Debug).Info("ARMAction changed while running, requeue-ing the action")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	return r.waitForNextRun(log, action, next, now), nil
+}
+
+// waitForNextRun records when the action is next due to run, returning a result that requeues it at that time.
+// Actions without a schedule don't run again until they're changed.
+func (r *ARMActionReconciler) waitForNextRun(
+	log logr.Logger,
+	action *serviceoperator.ARMAction,
+	next time.Time,
+	now time.Time,
+) ctrl.Result {
+	if next.IsZero() {
+		action.Status.NextRunTime = nil
+		return ctrl.Result{}
+	}
+
+	log.V(Verbose).Info("Action not due to run", "nextRun", next)
+	action.Status.NextRunTime = &metav1.Time{Time: next}
+
+	// As we're requeueing, we need to mark the action as ready ourselves
+	conditions.SetCondition(action, r.PositiveConditions.Ready.Succeeded(action.GetGeneration()))
+	return ctrl.Result{RequeueAfter: next.Sub(now)}
+}
+
+// resolveTarget finds the resource in Azure the action is invoked on. This must be a resource managed by the operator,
+// as the action is invoked with the credential used to manage it.
+func (r *ARMActionReconciler) resolveTarget(ctx context.Context, action *serviceoperator.ARMAction) (actionTarget, error) {
+	ownerDetails, err := r.ResourceResolver.ResolveOwner(ctx, action)
+	if err != nil {
+		return actionTarget{}, reconcilers.ClassifyResolverError(err)
+	}
+
+	if !ownerDetails.FoundKubernetesOwner() {
+		err = eris.Errorf("owner of ARMAction %s must be a resource managed by the operator, given by group, kind and name", action.Name)
+		return actionTarget{}, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	owner := ownerDetails.Owner
+	id, ok := genruntime.GetResourceID(owner)
+	if !ok {
+		err = eris.Errorf("owner %s has not yet been created in Azure", action.Owner().String())
+		return actionTarget{}, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonWaitingForOwner)
+	}
+
+	apiVersion := action.Spec.APIVersion
+	if apiVersion == "" {
+		apiVersion, err = genruntime.GetAPIVersion(owner, r.ResourceResolver.Scheme())
+		if err != nil {
+			return actionTarget{}, eris.Wrapf(err, "getting API version of owner %s", action.Owner().String())
+		}
+	}
+
+	connection, err := r.ARMConnectionFactory(ctx, owner)
+	if err != nil {
+		return actionTarget{}, err
+	}
+
+	return actionTarget{
+		connection: connection,
+		id:         id,
+		apiVersion: apiVersion,
+	}, nil
+}
+
+// saveAssociatedKubernetesResources exports the result of the action to config maps and secrets, as configured by the
+// operatorSpec. If there's nothing to export this method is a no-op.
+func (r *ARMActionReconciler) saveAssociatedKubernetesResources(
+	ctx context.Context,
+	log logr.Logger,
+	action *serviceoperator.ARMAction,
+) error {
+	exporters := []kubernetesResourceExporter{
+		&configMapExpressionExporter{
+			obj:                 action,
+			versionedObj:        action,
+			expressionEvaluator: r.ExpressionEvaluator,
+		},
+		&secretExpressionExporter{
+			obj:                 action,
+			versionedObj:        action,
+			expressionEvaluator: r.ExpressionEvaluator,
+		},
+	}
+
+	var resources []client.Object
+	for _, exporter := range exporters {
+		additionalResources, err := exporter.Export(ctx)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, additionalResources...)
+	}
+
+	if len(resources) == 0 {
+		// No resources to save, nothing to do
+		return nil
+	}
+
+	results, err := genruntime.ApplyObjsAndEnsureOwner(ctx, r.KubeClient, action, resources)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(resources); i++ {
+		resource := resources[i]
+		result := results[i]
+
+		log.V(Debug).Info("Successfully created resource",
+			"namespace", resource.GetNamespace(),
+			"name", resource.GetName(),
+			"type", fmt.Sprintf("%T", resource),
+			"action", result)
+	}
+
+	return nil
+}
+
+// nextRunTime returns when the action is next due to run according to its schedule, or the zero time if it has no
+// schedule (or the schedule has no upcoming times). Runs are scheduled after the most recent run, or after the
+// ARMAction was created if it hasn't yet run. A run which started but didn't complete counts as the most recent run.
+func nextRunTime(action *serviceoperator.ARMAction) (time.Time, error) {
+	if action.Spec.Schedule == "" {
+		return time.Time{}, nil
+	}
+
+	sched, err := schedule.Parse(action.Spec.Schedule)
+	if err != nil {
+		err = eris.Wrap(err, "invalid schedule")
+		return time.Time{}, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	since := action.GetCreationTimestamp().Time
+	if action.Status.LastRunTime != nil {
+		since = action.Status.LastRunTime.Time
+	}
+
+	if started, ok := startedScheduledRun(action); ok && started.After(since) {
+		since = started
+	}
+
+	return sched.Next(since), nil
+}
+
+const (
+	generationRunPrefix = "generation/"
+	scheduledRunPrefix  = "schedule/"
+)
+
+// runKey identifies a run of the action starting at the specified time: the generation being run for an action without
+// a schedule, and the start time for one with (as the next scheduled run is the first after it).
+func runKey(action *serviceoperator.ARMAction, start time.Time) string {
+	if action.Spec.Schedule == "" {
+		return fmt.Sprintf("%s%d", generationRunPrefix, action.GetGeneration())
+	}
+
+	return scheduledRunPrefix + start.UTC().Format(time.RFC3339)
+}
+
+// startedScheduledRun returns when the run most recently started, if the action has a schedule
+func startedScheduledRun(action *serviceoperator.ARMAction) (time.Time, bool) {
+	run, ok := strings.CutPrefix(action.GetAnnotations()[reconcilers.ActionRunAnnotation], scheduledRunPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	started, err := time.Parse(time.RFC3339, run)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return started, true
+}
+
+// isRunDue returns true if the action should be run now. Actions with a schedule are due once the next scheduled time
+// has arrived; actions without are due if the current generation hasn't yet been run.
+func isRunDue(action *serviceoperator.ARMAction, next time.Time, now time.Time) bool {
+	if action.Spec.Schedule == "" {
+		return action.Status.LastRunGeneration != action.GetGeneration()
+	}
+
+	return !next.IsZero() && !now.Before(next)
+}
+
+// wasRejected returns true if the error shows that Azure rejected the request outright, without acting on it. Server
+// errors and failures to get a response at all leave us unsure whether it was acted on.
+func wasRejected(err error) bool {
+	var responseErr *azcore.ResponseError
+	if !eris.As(err, &responseErr) {
+		return false
+	}
+
+	return responseErr.StatusCode >= http.StatusBadRequest && responseErr.StatusCode < http.StatusInternalServerError
+}
+
+// actionResult converts the response to an action into the form recorded in status. Responses which aren't JSON
+// objects are recorded under the key "value".
+func actionResult(response json.RawMessage) map[string]apiextensionsv1.JSON {
+	if len(response) == 0 || string(response) == "null" {
+		return nil
+	}
+
+	var result map[string]apiextensionsv1.JSON
+	if err := json.Unmarshal(response, &result); err == nil {
+		return result
+	}
+
+	return map[string]apiextensionsv1.JSON{
+		"value": {Raw: response},
+	}
+}
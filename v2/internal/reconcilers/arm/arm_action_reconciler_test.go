@@ -0,0 +1,510 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// 2024-06-05 was a Wednesday
+var actionCreated = time.Date(2024, 6, 5, 12, 30, 0, 0, time.UTC)
+
+func newTestARMAction(generation int64, schedule string) *serviceoperator.ARMAction {
+	return &serviceoperator.ARMAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "action",
+			Namespace:         "default",
+			Generation:        generation,
+			CreationTimestamp: metav1.NewTime(actionCreated),
+		},
+		Spec: serviceoperator.ARMActionSpec{
+			Action:   "restart",
+			Schedule: schedule,
+		},
+	}
+}
+
+func Test_NextRunTime_WithoutSchedule_ReturnsZero(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	next, err := nextRunTime(newTestARMAction(1, ""))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(next.IsZero()).To(BeTrue())
+}
+
+func Test_NextRunTime_NotYetRun_ReturnsFirstScheduledTimeAfterCreation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	next, err := nextRunTime(newTestARMAction(1, "0 22 * * *"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(next).To(BeTemporally("==", time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC)))
+}
+
+func Test_NextRunTime_AfterRun_ReturnsFirstScheduledTimeAfterRun(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	action := newTestARMAction(1, "0 22 * * *")
+	action.Status.LastRunTime = &metav1.Time{Time: time.Date(2024, 6, 5, 22, 3, 0, 0, time.UTC)}
+
+	next, err := nextRunTime(action)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(next).To(BeTemporally("==", time.Date(2024, 6, 6, 22, 0, 0, 0, time.UTC)))
+}
+
+func Test_NextRunTime_InvalidSchedule_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	_, err := nextRunTime(newTestARMAction(1, "every night"))
+	g.Expect(err).To(HaveOccurred())
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(readyErr.Severity).To(Equal(conditions.ConditionSeverityError))
+}
+
+func Test_IsRunDue(t *testing.T) {
+	t.Parallel()
+
+	next := time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		generation        int64
+		lastRunGeneration int64
+		schedule          string
+		next              time.Time
+		now               time.Time
+		expected          bool
+	}{
+		"Not yet run": {
+			generation: 1,
+			now:        actionCreated,
+			expected:   true,
+		},
+		"Generation already run": {
+			generation:        2,
+			lastRunGeneration: 2,
+			now:               actionCreated,
+			expected:          false,
+		},
+		"Generation changed since run": {
+			generation:        3,
+			lastRunGeneration: 2,
+			now:               actionCreated,
+			expected:          true,
+		},
+		"Before scheduled time": {
+			generation: 1,
+			schedule:   "0 22 * * *",
+			next:       next,
+			now:        next.Add(-time.Minute),
+			expected:   false,
+		},
+		"At scheduled time": {
+			generation: 1,
+			schedule:   "0 22 * * *",
+			next:       next,
+			now:        next,
+			expected:   true,
+		},
+		"Scheduled, generation changed since run": {
+			generation:        3,
+			lastRunGeneration: 2,
+			schedule:          "0 22 * * *",
+			next:              next,
+			now:               next.Add(-time.Minute),
+			expected:          false,
+		},
+		"No upcoming scheduled time": {
+			generation: 1,
+			schedule:   "0 0 30 FEB *",
+			now:        next,
+			expected:   false,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			action := newTestARMAction(c.generation, c.schedule)
+			action.Status.LastRunGeneration = c.lastRunGeneration
+
+			g.Expect(isRunDue(action, c.next, c.now)).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_ActionResult(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		response json.RawMessage
+		expected map[string]apiextensionsv1.JSON
+	}{
+		"Empty response": {
+			response: nil,
+			expected: nil,
+		},
+		"Null response": {
+			response: json.RawMessage(`null`),
+			expected: nil,
+		},
+		"Object response": {
+			response: json.RawMessage(`{"primaryKey":"abc","secondaryKey":"def"}`),
+			expected: map[string]apiextensionsv1.JSON{
+				"primaryKey":   {Raw: []byte(`"abc"`)},
+				"secondaryKey": {Raw: []byte(`"def"`)},
+			},
+		},
+		"List response": {
+			response: json.RawMessage(`[{"keyName":"key1"}]`),
+			expected: map[string]apiextensionsv1.JSON{
+				"value": {Raw: []byte(`[{"keyName":"key1"}]`)},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(actionResult(c.response)).To(Equal(c.expected))
+		})
+	}
+}
+
+const testActionPath = testResourceGroupID + "/restart"
+
+// newActionTestReconciler returns a reconciler for the provided action, invoked on the test resource group. It talks to
+// a fake ARM server serving requests with the provided handler, and to a fake Kubernetes cluster holding the action.
+func newActionTestReconciler(
+	ctx context.Context,
+	t *testing.T,
+	action *serviceoperator.ARMAction,
+	handler http.HandlerFunc,
+) (*ARMActionReconciler, kubeclient.Client) {
+	g := NewGomegaWithT(t)
+
+	scheme := createTestScheme()
+	g.Expect(serviceoperator.AddToScheme(scheme)).To(Succeed())
+	kubeClient := NewFakeKubeClient(scheme)
+
+	res := resolver.NewResolver(kubeClient)
+	err := res.IndexStorageTypes(scheme, []*registration.StorageType{registration.NewStorageType(new(resources.ResourceGroup))})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rg := newTestResourceGroup()
+	genruntime.SetResourceID(rg, testResourceGroupID)
+	g.Expect(kubeClient.Create(ctx, rg)).To(Succeed())
+
+	action.Spec.Owner = &genruntime.ArbitraryOwnerReference{
+		Group: resources.GroupVersion.Group,
+		Kind:  "ResourceGroup",
+		Name:  rg.Name,
+	}
+	action.Spec.APIVersion = "2020-06-01"
+	g.Expect(kubeClient.Create(ctx, action)).To(Succeed())
+
+	connection := newTestConnection(t, handler)
+	connectionFactory := func(context.Context, genruntime.ARMMetaObject) (Connection, error) {
+		return connection, nil
+	}
+
+	evaluator, err := asocel.NewExpressionEvaluator()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r := NewARMActionReconciler(
+		connectionFactory,
+		kubeClient,
+		res,
+		conditions.NewPositiveConditionBuilder(clock.New()),
+		evaluator)
+	return r, kubeClient
+}
+
+// getActionRun returns the run of the action recorded in the cluster
+func getActionRun(ctx context.Context, g *WithT, kubeClient kubeclient.Client, action *serviceoperator.ARMAction) string {
+	var stored serviceoperator.ARMAction
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(action), &stored)).To(Succeed())
+	return stored.GetAnnotations()[reconcilers.ActionRunAnnotation]
+}
+
+func Test_ARMActionReconciler_CompletesImmediately_RecordsRun(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		recordResult   bool
+		expectedResult map[string]apiextensionsv1.JSON
+	}{
+		"Result not recorded by default": {
+			recordResult:   false,
+			expectedResult: nil,
+		},
+		"Result recorded when requested": {
+			recordResult: true,
+			expectedResult: map[string]apiextensionsv1.JSON{
+				"status": {Raw: []byte(`"restarted"`)},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			ctx := context.Background()
+
+			action := newTestARMAction(1, "")
+			action.Spec.OperatorSpec = &serviceoperator.ARMActionOperatorSpec{RecordResult: c.recordResult}
+
+			var kubeClient kubeclient.Client
+			posts := 0
+			runAtPost := ""
+			r, kubeClient := newActionTestReconciler(ctx, t, action, func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodPost && req.URL.Path == testActionPath {
+					posts++
+					runAtPost = getActionRun(ctx, g, kubeClient, action)
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"status": "restarted"}`))
+					return
+				}
+
+				w.WriteHeader(http.StatusBadRequest)
+			})
+
+			result, err := r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result.IsZero()).To(BeTrue())
+			g.Expect(posts).To(Equal(1))
+
+			// The run was committed before the action was invoked
+			g.Expect(runAtPost).To(Equal(fmt.Sprintf("generation/%d", action.GetGeneration())))
+
+			g.Expect(action.Status.LastRunGeneration).To(Equal(action.GetGeneration()))
+			g.Expect(action.Status.LastRunTime).ToNot(BeNil())
+			g.Expect(action.Status.Result).To(Equal(c.expectedResult))
+		})
+	}
+}
+
+func Test_ARMActionReconciler_LongRunning_CompletesWhenPolled(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	action := newTestARMAction(1, "")
+	posts := 0
+	r, _ := newActionTestReconciler(ctx, t, action, func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == testActionPath:
+			posts++
+			w.Header().Set("Location", fmt.Sprintf("https://%s/operations/restart", req.Host))
+			w.WriteHeader(http.StatusAccepted)
+		case req.Method == http.MethodGet && req.URL.Path == "/operations/restart":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status": "restarted"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	result, err := r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Requeue).To(BeTrue())
+
+	_, _, hasToken := GetPollerResumeToken(action)
+	g.Expect(hasToken).To(BeTrue())
+	g.Expect(action.Status.LastRunTime).To(BeNil())
+
+	// Checking on the run completes it, without invoking the action again
+	result, err = r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.IsZero()).To(BeTrue())
+	g.Expect(posts).To(Equal(1))
+
+	_, _, hasToken = GetPollerResumeToken(action)
+	g.Expect(hasToken).To(BeFalse())
+	g.Expect(action.Status.LastRunGeneration).To(Equal(action.GetGeneration()))
+	g.Expect(action.Status.LastRunTime).ToNot(BeNil())
+}
+
+func Test_ARMActionReconciler_ScheduledRunFails_WaitsForNextScheduledTime(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	action := newTestARMAction(1, "0 22 * * *")
+	posts := 0
+	r, _ := newActionTestReconciler(ctx, t, action, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost && req.URL.Path == testActionPath {
+			posts++
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": "BadRequest", "message": "can't restart"}}`))
+	})
+
+	result, err := r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	g.Expect(result.RequeueAfter).To(BeNumerically("<=", 24*time.Hour))
+	g.Expect(posts).To(Equal(1))
+
+	// The failure is recorded in status
+	ready, ok := conditions.GetCondition(action, conditions.ConditionTypeReady)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(ready.Severity).To(Equal(conditions.ConditionSeverityError))
+	g.Expect(ready.Message).To(ContainSubstring("can't restart"))
+	g.Expect(action.Status.NextRunTime).ToNot(BeNil())
+	g.Expect(action.Status.LastRunTime).To(BeNil())
+
+	// The failed run isn't attempted again
+	_, err = r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(posts).To(Equal(1))
+}
+
+func Test_ARMActionReconciler_RunRejected_IsRetried(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	action := newTestARMAction(1, "")
+	posts := 0
+	r, _ := newActionTestReconciler(ctx, t, action, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost && req.URL.Path == testActionPath {
+			posts++
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error": {"code": "AnotherOperationInProgress", "message": "busy"}}`))
+	})
+
+	for i := 1; i <= 2; i++ {
+		_, err := r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+		g.Expect(err).To(HaveOccurred())
+
+		readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(readyErr.Severity).To(Equal(conditions.ConditionSeverityWarning))
+		g.Expect(posts).To(Equal(i))
+	}
+}
+
+func Test_ARMActionReconciler_RunAlreadyStarted_IsNotInvokedAgain(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	// The run was committed, but the operator stopped before recording its outcome
+	action := newTestARMAction(1, "")
+	genruntime.AddAnnotation(action, reconcilers.ActionRunAnnotation, "generation/1")
+
+	posts := 0
+	r, _ := newActionTestReconciler(ctx, t, action, func(w http.ResponseWriter, req *http.Request) {
+		posts++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	_, err := r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(posts).To(Equal(0))
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(readyErr.Severity).To(Equal(conditions.ConditionSeverityError))
+}
+
+func Test_ARMActionReconciler_ExportFails_IsExportedAgainWithoutInvokingAgain(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	action := newTestARMAction(1, "")
+	action.Spec.OperatorSpec = &serviceoperator.ARMActionOperatorSpec{
+		SecretExpressions: []*core.DestinationExpression{
+			{Name: "action-result", Key: "key", Value: "string(self.status.result.key)"},
+		},
+	}
+
+	posts := 0
+	r, kubeClient := newActionTestReconciler(ctx, t, action, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost && req.URL.Path == testActionPath {
+			posts++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"key": "regenerated"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	// A secret we don't own is in the way, so the result can't be exported
+	userSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "action-result", Namespace: action.Namespace}}
+	g.Expect(kubeClient.Create(ctx, userSecret)).To(Succeed())
+
+	_, err := r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(posts).To(Equal(1))
+
+	// The run isn't complete, and the result is kept so it can be exported later
+	g.Expect(action.Status.LastRunGeneration).To(BeZero())
+	g.Expect(action.Status.LastRunTime).To(BeNil())
+	g.Expect(action.Status.Result).ToNot(BeEmpty())
+
+	// Once the secret is out of the way, the result is exported without invoking the action again
+	g.Expect(kubeClient.Delete(ctx, userSecret)).To(Succeed())
+
+	_, err = r.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), action)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(posts).To(Equal(1))
+
+	g.Expect(action.Status.LastRunGeneration).To(Equal(action.GetGeneration()))
+	g.Expect(action.Status.LastRunTime).ToNot(BeNil())
+	g.Expect(action.Status.Result).To(BeNil())
+
+	var exported v1.Secret
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(userSecret), &exported)).To(Succeed())
+	// The fake client doesn't convert StringData to Data, as the API server does
+	g.Expect(exported.StringData).To(HaveKeyWithValue("key", "regenerated"))
+}
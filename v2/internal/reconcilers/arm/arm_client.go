@@ -17,15 +17,18 @@ import (
 type armClient struct {
 	genericClient *genericarmclient.GenericClient
 	credential    *identity.Credential
+	statusCache   *StatusCache
 }
 
 func newARMClient(
 	client *genericarmclient.GenericClient,
 	credential *identity.Credential,
+	statusCache *StatusCache,
 ) *armClient {
 	return &armClient{
 		genericClient: client,
 		credential:    credential,
+		statusCache:   statusCache,
 	}
 }
 
@@ -45,8 +48,14 @@ func (c *armClient) SubscriptionID() string {
 	return c.credential.SubscriptionID()
 }
 
+func (c *armClient) StatusCache() *StatusCache {
+	return c.statusCache
+}
+
 type Connection interface {
 	Client() *genericarmclient.GenericClient
 	CredentialFrom() types.NamespacedName
 	SubscriptionID() string
+	// StatusCache returns the cache of resource status refreshed in batches, or nil if batched refresh is disabled
+	StatusCache() *StatusCache
 }
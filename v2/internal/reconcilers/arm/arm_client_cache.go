@@ -12,6 +12,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 
+	"github.com/Azure/azure-service-operator/v2/internal/audit"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	"github.com/Azure/azure-service-operator/v2/internal/identity"
 	"github.com/Azure/azure-service-operator/v2/internal/metrics"
@@ -29,6 +30,9 @@ type ARMClientCache struct {
 	kubeClient         kubeclient.Client
 	httpClient         *http.Client
 	armMetrics         *metrics.ARMClientMetrics
+	rateLimits         *genericarmclient.RateLimitTracker
+	statusCache        *StatusCache
+	auditSink          audit.Sink
 }
 
 func NewARMClientCache(
@@ -46,9 +50,26 @@ func NewARMClientCache(
 		credentialProvider: credentialProvider,
 		httpClient:         httpClient,
 		armMetrics:         armMetrics,
+		rateLimits:         genericarmclient.NewRateLimitTracker(armMetrics),
 	}
 }
 
+// SetStatusCache configures the clients created by the cache to refresh resource status in batches using the
+// provided StatusCache. It must be called before any connections are requested.
+func (c *ARMClientCache) SetStatusCache(statusCache *StatusCache) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.statusCache = statusCache
+}
+
+// SetAuditSink configures the clients created by the cache to write an audit record of every request that modifies
+// resources in Azure to the provided sink. It must be called before any connections are requested.
+func (c *ARMClientCache) SetAuditSink(sink audit.Sink) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.auditSink = sink
+}
+
 func (c *ARMClientCache) register(client *armClient) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -87,13 +108,16 @@ func (c *ARMClientCache) getARMClientFromCredential(cred *identity.Credential) (
 		HTTPClient:        c.httpClient,
 		Metrics:           c.armMetrics,
 		AdditionalTenants: cred.AdditionalTenants(),
+		RateLimits:        c.rateLimits,
+		Audit:             c.auditSink,
+		CredentialName:    cred.CredentialFrom().String(),
 	}
 	newClient, err := genericarmclient.NewGenericClient(c.cloudConfig, cred.TokenCredential(), options)
 	if err != nil {
 		return nil, err
 	}
 
-	armClient := newARMClient(newClient, cred)
+	armClient := newARMClient(newClient, cred, c.statusCache)
 	c.register(armClient)
 	return armClient, nil
 }
@@ -20,7 +20,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	dbforpostgresql "github.com/Azure/azure-service-operator/v2/api/dbforpostgresql/v1api20240801"
+	dbforpostgresqlstorage "github.com/Azure/azure-service-operator/v2/api/dbforpostgresql/v1api20240801/storage"
 	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	resourcesstorage "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601/storage"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
 	"github.com/Azure/azure-service-operator/v2/internal/identity"
 	"github.com/Azure/azure-service-operator/v2/internal/metrics"
@@ -82,6 +86,10 @@ func createTestScheme() *runtime.Scheme {
 
 	_ = v1.AddToScheme(s)
 	_ = resources.AddToScheme(s)
+	_ = dbforpostgresql.AddToScheme(s)
+	_ = dbforpostgresqlstorage.AddToScheme(s)
+	_ = resourcesstorage.AddToScheme(s)
+	_ = serviceoperator.AddToScheme(s)
 
 	return s
 }
@@ -47,7 +47,11 @@ type (
 	DeleteActionFunc         = func(ctx context.Context) (ctrl.Result, error)
 )
 
-var _ genruntime.Reconciler = &AzureDeploymentReconciler{}
+var (
+	_ genruntime.Reconciler = &AzureDeploymentReconciler{}
+	_ genruntime.Planner    = &AzureDeploymentReconciler{}
+	_ genruntime.Observer   = &AzureDeploymentReconciler{}
+)
 
 type AzureDeploymentReconciler struct {
 	reconcilers.ARMOwnedResourceReconcilerCommon
@@ -57,6 +61,14 @@ type AzureDeploymentReconciler struct {
 	PositiveConditions   *conditions.PositiveConditionBuilder
 	Config               config.Values
 	Extension            genruntime.ResourceExtension
+
+	// ApplyPayloadMutationPolicies is true if PayloadMutationPolicies are applied to the payloads sent to Azure. Only
+	// set this if the PayloadMutationPolicy CRD is installed.
+	ApplyPayloadMutationPolicies bool
+
+	// RecordResourcePlans is true if plans are recorded in ResourcePlans, as well as in conditions and events. Only set
+	// this if the ResourcePlan CRD is installed.
+	RecordResourcePlans bool
 }
 
 func NewAzureDeploymentReconciler(
@@ -146,6 +158,32 @@ func (r *AzureDeploymentReconciler) Delete(
 	return instance.Delete(ctx)
 }
 
+func (r *AzureDeploymentReconciler) Plan(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	obj genruntime.MetaObject,
+) error {
+	instance, err := r.makeInstance(ctx, log, eventRecorder, obj)
+	if err != nil {
+		return err
+	}
+	return instance.Plan(ctx)
+}
+
+func (r *AzureDeploymentReconciler) Observe(
+	ctx context.Context,
+	log logr.Logger,
+	eventRecorder record.EventRecorder,
+	obj genruntime.MetaObject,
+) error {
+	instance, err := r.makeInstance(ctx, log, eventRecorder, obj)
+	if err != nil {
+		return err
+	}
+	return instance.Observe(ctx)
+}
+
 func (r *AzureDeploymentReconciler) Claim(
 	ctx context.Context,
 	log logr.Logger,
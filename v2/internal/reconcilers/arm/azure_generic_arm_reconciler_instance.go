@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
@@ -16,16 +17,19 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/go-logr/logr"
 	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/Azure/azure-service-operator/v2/internal/config"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm/errorclassification"
 	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/labels"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
@@ -41,6 +45,28 @@ type azureDeploymentReconcilerInstance struct {
 	Recorder      record.EventRecorder
 	Extension     genruntime.ResourceExtension
 	ARMConnection Connection
+	Config        config.Values
+
+	// applyPayloadMutationPolicies is true if PayloadMutationPolicies are applied (see applyPayloadMutations)
+	applyPayloadMutationPolicies bool
+
+	// recordResourcePlans is true if plans are recorded in ResourcePlans (see recordPlan)
+	recordResourcePlans bool
+
+	// live is the resource as retrieved from Azure earlier in this reconcile, if any (see getLiveResource)
+	live *liveResource
+}
+
+// liveResource is the body of a resource retrieved from Azure
+type liveResource struct {
+	id         string
+	apiVersion string
+	body       json.RawMessage
+}
+
+// matches returns true if the live resource is the one with the specified ID, in the shape of the specified API version
+func (l *liveResource) matches(id string, apiVersion string) bool {
+	return l != nil && strings.EqualFold(l.id, id) && l.apiVersion == apiVersion
 }
 
 func newAzureDeploymentReconcilerInstance(
@@ -56,7 +82,10 @@ func newAzureDeploymentReconcilerInstance(
 		Recorder:                         recorder,
 		ARMConnection:                    connection,
 		Extension:                        reconciler.Extension,
+		Config:                           reconciler.Config,
 		ARMOwnedResourceReconcilerCommon: reconciler.ARMOwnedResourceReconcilerCommon,
+		applyPayloadMutationPolicies:     reconciler.ApplyPayloadMutationPolicies,
+		recordResourcePlans:              reconciler.RecordResourcePlans,
 	}
 }
 
@@ -71,7 +100,7 @@ func (r *azureDeploymentReconcilerInstance) CreateOrUpdate(ctx context.Context)
 
 	r.Log.V(Verbose).Info("Determined CreateOrUpdate action", "action", action)
 
-	result, err := actionFunc(ctx)
+	result, err := r.runAction(ctx, string(action), actionFunc)
 	if err != nil {
 		r.Recorder.Event(r.Obj, v1.EventTypeWarning, "CreateOrUpdateActionError", err.Error())
 
@@ -91,7 +120,7 @@ func (r *azureDeploymentReconcilerInstance) Delete(ctx context.Context) (ctrl.Re
 
 	r.Log.V(Verbose).Info("Determined Delete action", "action", action)
 
-	result, err := actionFunc(ctx)
+	result, err := r.runAction(ctx, string(action), actionFunc)
 	if err != nil {
 		r.Recorder.Event(r.Obj, v1.EventTypeWarning, "DeleteActionError", err.Error())
 
@@ -101,6 +130,29 @@ func (r *azureDeploymentReconcilerInstance) Delete(ctx context.Context) (ctrl.Re
 	return result, nil
 }
 
+// runAction runs the specified action within a span, so that the time spent on each action is visible in traces.
+// Requests made to ARM by the action are recorded in the operation history of the resource.
+func (r *azureDeploymentReconcilerInstance) runAction(
+	ctx context.Context,
+	action string,
+	actionFunc func(ctx context.Context) (ctrl.Result, error),
+) (ctrl.Result, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		action,
+		trace.WithAttributes(
+			tracing.ActionKey.String(action),
+			tracing.ResourceIDKey.String(genruntime.GetResourceIDOrDefault(r.Obj))))
+
+	recorder := genericarmclient.NewRequestRecorder()
+	result, err := actionFunc(genericarmclient.WithRequestRecorder(ctx, recorder))
+	tracing.End(span, err)
+
+	r.recordOperations(ctx, action, recorder.Requests(), err)
+
+	return result, err
+}
+
 func (r *azureDeploymentReconcilerInstance) MakeReadyConditionImpactingErrorFromError(azureErr error) error {
 	apiVersion, verr := r.GetAPIVersion()
 	if verr != nil {
@@ -158,10 +210,21 @@ func NoAction(_ context.Context) (ctrl.Result, error) {
 // StartDeleteOfResource will begin deletion of a resource by telling Azure to start deleting it. The resource will be
 // marked with the provisioning state of "Deleting".
 func (r *azureDeploymentReconcilerInstance) StartDeleteOfResource(ctx context.Context) (ctrl.Result, error) {
+	err := r.checkMaintenanceWindow(ctx, "Delete")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = r.checkDescendantDeletionProtection(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	msg := "Starting delete of resource"
 	r.Log.V(Status).Info(msg)
 	r.Recorder.Event(r.Obj, v1.EventTypeNormal, string(DeleteActionBeginDelete), msg)
 
+	r.invalidateCachedStatus(genruntime.GetResourceIDOrDefault(r.Obj))
 	deleter := extensions.CreateDeleter(r.Extension, r.deleteResource)
 	result, err := deleter(ctx, r.Log, r.ResourceResolver, r.ARMConnection.Client(), r.Obj)
 	return result, err
@@ -219,9 +282,6 @@ func (r *azureDeploymentReconcilerInstance) BeginCreateOrUpdateResource(
 				err, conditions.ConditionSeverityError, conditions.ReasonFailed)
 	}
 
-	// We want to set the latest reconciled generation annotation to keep a track of reconciles per generation.
-	SetLatestReconciledGeneration(r.Obj)
-
 	check, err := r.preReconciliationCheck(ctx)
 	if err != nil {
 		// Failed to do the pre-reconciliation check, this is a serious but non-fatal error
@@ -264,6 +324,66 @@ func (r *azureDeploymentReconcilerInstance) BeginCreateOrUpdateResource(
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+
+	payload, err := r.mergeExternalChanges(ctx, armResource)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	secretsHash, err := r.secretsHash(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Properties we're removing from a merged path aren't seen as drift, as drift detection ignores properties that
+	// are only present in Azure, so we know the PUT is needed
+	if r.Config.EnableDriftDetection && len(payload.removed) == 0 {
+		inSync := r.detectDrift(ctx, armResource, r.isCurrentGenerationApplied(secretsHash))
+		if inSync {
+			// The resource exists in Azure, so it's only ours to adopt if the creation mode allows it
+			err = r.checkCreationMode(ctx)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			r.Log.V(Status).Info("Resource in Azure matches desired state, skipping PUT", "id", armResource.GetID())
+			r.recordGenerationApplied(secretsHash)
+			err = SetLastApplied(r.Obj, payload.applied)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, r.handleCreateOrUpdateSuccess(ctx, ManageResource)
+		}
+	}
+
+	// Outside the maintenance window we don't change the resource in Azure, but still keep its status up to date.
+	// This is checked only once we know a PUT is needed, so resources already in sync stay Ready.
+	err = r.checkMaintenanceWindow(ctx, "Create or update")
+	if err != nil {
+		statusErr := r.updateStatus(ctx)
+		if statusErr != nil && !genericarmclient.IsNotFoundError(statusErr) {
+			r.Log.V(Status).Info("Unable to refresh status of resource", "error", statusErr.Error())
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// If the subscription is running out of write budget, defer the PUT so that other work (such as polling
+	// operations already underway) can continue without tipping the subscription into throttling.
+	if delay := r.ARMConnection.Client().RateLimitDelay(armResource.GetID(), true); delay > 0 {
+		r.Log.V(Status).Info("ARM write budget for subscription is low, deferring PUT", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// Must happen immediately before we send the resource to Azure, as it records that we're creating the resource
+	// ourselves. If we claimed it before the PUT was held or deferred, a resource created by someone else in the
+	// meantime would be taken for ours.
+	err = r.checkCreationMode(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Use conditions.SetConditionReasonAware here to override any Warning conditions set earlier in the reconciliation process.
 	// Note that this call should be done after all validation has passed and all that is left to do is send the payload to ARM.
 	conditions.SetConditionReasonAware(r.Obj, r.PositiveConditions.Ready.Reconciling(r.Obj.GetGeneration()))
@@ -272,11 +392,25 @@ func (r *azureDeploymentReconcilerInstance) BeginCreateOrUpdateResource(
 
 	// Try to create the resource
 	spec := armResource.Spec()
-	pollerResp, err := r.ARMConnection.Client().BeginCreateOrUpdateByID(ctx, armResource.GetID(), spec.GetAPIVersion(), spec)
+	r.invalidateCachedStatus(armResource.GetID())
+	pollerResp, err := r.ARMConnection.Client().BeginCreateOrUpdateByID(ctx, armResource.GetID(), spec.GetAPIVersion(), payload.body)
 	if err != nil {
 		return ctrl.Result{}, r.handleCreateOrUpdateFailed(err)
 	}
 
+	// We want to set the latest reconciled generation annotation to keep a track of reconciles per generation.
+	// Drift detection relies on it to tell drift apart from changes the user has asked for, so it's only set once
+	// Azure has accepted the generation, and not when the PUT is held, deferred or fails. Otherwise a change to a
+	// property Azure never returns (such as a password) would be taken as already applied, and never sent.
+	r.recordGenerationApplied(secretsHash)
+
+	// Likewise, merge mode takes the properties recorded as applied to be ours to remove, so they're only recorded once
+	// Azure has accepted them
+	err = SetLastApplied(r.Obj, payload.applied)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	r.Log.V(Status).Info("Successfully sent resource to Azure", "id", armResource.GetID())
 	r.Recorder.Eventf(r.Obj, v1.EventTypeNormal, string(CreateOrUpdateActionBeginCreation), "Successfully sent resource to Azure with ID %q", armResource.GetID())
 
@@ -526,10 +660,12 @@ func (r *azureDeploymentReconcilerInstance) getStatus(ctx context.Context, id st
 		return nil, zeroDuration, eris.Wrapf(verr, "error getting api version for resource %s while getting status", r.Obj.GetName())
 	}
 
-	// Get the resource
-	if genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
+	// Get the resource. If we've already retrieved it during this reconcile, that's fresher than Resource Graph
+	if genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) && !r.live.matches(id, apiVersion) && r.getCachedStatus(id, apiVersion, armStatus) {
+		r.Log.V(Verbose).Info("Using status from Resource Graph", "id", id)
+	} else if genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
 		var retryAfter time.Duration
-		retryAfter, err = r.ARMConnection.Client().GetByID(ctx, id, apiVersion, armStatus)
+		retryAfter, err = r.getLiveResource(ctx, id, apiVersion, armStatus)
 		if err != nil {
 			return nil, retryAfter, eris.Wrapf(err, "getting resource with ID: %q", id)
 		}
@@ -552,7 +688,7 @@ func (r *azureDeploymentReconcilerInstance) getStatus(ctx context.Context, id st
 
 		// We expect the resource to exist
 		if !exists {
-			return nil, retryAfter, eris.Wrapf(err, "getting resource with ID: %q", id)
+			return nil, retryAfter, eris.Wrapf(genericarmclient.NewNotFoundError(id), "getting resource with ID: %q", id)
 		}
 	} else {
 		return nil, zeroDuration, eris.Errorf("resource must support one of GET or HEAD, but it supports neither")
@@ -589,15 +725,98 @@ func (r *azureDeploymentReconcilerInstance) getStatus(ctx context.Context, id st
 	return status, zeroDuration, nil
 }
 
+// getCachedStatus populates armStatus from the batched status cache, returning true if it was able to do so. The
+// cached status is only used if it has the shape of the API version we'd GET.
+func (r *azureDeploymentReconcilerInstance) getCachedStatus(id string, apiVersion string, armStatus genruntime.ARMResourceStatus) bool {
+	cache := r.ARMConnection.StatusCache()
+	if cache == nil {
+		return false
+	}
+
+	cached, ok := cache.Lookup(r.ARMConnection, id)
+	if !ok {
+		return false
+	}
+
+	// Resource Graph returns each resource in the shape of an API version of its own choosing
+	var resource struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	err := json.Unmarshal(cached, &resource)
+	if err != nil || !strings.EqualFold(resource.APIVersion, apiVersion) {
+		r.Log.V(Verbose).Info(
+			"Status from Resource Graph is for a different API version, falling back to GET",
+			"id", id,
+			"apiVersion", apiVersion,
+			"resourceGraphAPIVersion", resource.APIVersion)
+		return false
+	}
+
+	err = json.Unmarshal(cached, armStatus)
+	if err != nil {
+		r.Log.V(Status).Info("Unable to use status from Resource Graph, falling back to GET", "id", id, "error", err.Error())
+		return false
+	}
+
+	return true
+}
+
+// invalidateCachedStatus discards any status for the resource held by the batched status cache, or retrieved earlier
+// in this reconcile, which must happen whenever we change the resource in Azure.
+func (r *azureDeploymentReconcilerInstance) invalidateCachedStatus(id string) {
+	if cache := r.ARMConnection.StatusCache(); cache != nil {
+		cache.Invalidate(r.ARMConnection, id)
+	}
+
+	r.live = nil
+}
+
+// getLiveResource GETs the resource with the specified ID from Azure, unmarshalling it into result. The resource is
+// kept for the rest of the reconcile (until we change it in Azure), so that comparing it with the spec and updating
+// the status share a single GET.
+func (r *azureDeploymentReconcilerInstance) getLiveResource(
+	ctx context.Context,
+	id string,
+	apiVersion string,
+	result any,
+) (time.Duration, error) {
+	if r.live.matches(id, apiVersion) {
+		r.Log.V(Verbose).Info("Using resource already retrieved from Azure", "id", id)
+	} else {
+		var body json.RawMessage
+		retryAfter, err := r.ARMConnection.Client().GetByID(ctx, id, apiVersion, &body)
+		if err != nil {
+			return retryAfter, err
+		}
+
+		r.live = &liveResource{
+			id:         id,
+			apiVersion: apiVersion,
+			body:       body,
+		}
+	}
+
+	err := json.Unmarshal(r.live.body, result)
+	if err != nil {
+		return zeroDuration, eris.Wrapf(err, "unmarshalling resource with ID: %q", id)
+	}
+
+	return zeroDuration, nil
+}
+
 func (r *azureDeploymentReconcilerInstance) setStatus(status genruntime.ConvertibleStatus) error {
 	// Modifications that impact status have to happen after this because this performs a full
-	// replace of status
+	// replace of status. Conditions are ours rather than Azure's, so they're carried over.
 	if status != nil {
+		existing := r.Obj.GetConditions()
+
 		// SetStatus() takes care of any required conversion to the right version
 		err := r.Obj.SetStatus(status)
 		if err != nil {
 			return eris.Wrapf(err, "setting status on %s", r.Obj.GetObjectKind().GroupVersionKind())
 		}
+
+		r.Obj.SetConditions(existing)
 	}
 
 	return nil
@@ -727,7 +946,24 @@ func (r *azureDeploymentReconcilerInstance) ConvertResourceToARMResource(ctx con
 
 	// Run any resource-specific extensions
 	modifier := extensions.CreateARMResourceModifier(r.Extension, r.ARMConnection.Client(), r.KubeClient, r.ResourceResolver, r.Log)
-	return modifier(ctx, metaObject, result)
+	result, err = modifier(ctx, metaObject, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Then any rules configured by cluster administrators
+	result, err = r.applyPayloadMutations(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the tag policy last, so that policy-wins tags can't be overridden by extensions
+	err = r.applyTagPolicy(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // ConvertToARMResourceImpl factored out of AzureDeploymentReconciler.ConvertResourceToARMResource to allow for testing
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbforpostgresql "github.com/Azure/azure-service-operator/v2/api/dbforpostgresql/v1api20240801"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// newCreateOrUpdateTest returns a resource group, and a reconciler instance for it talking to the provided fake server
+// with drift detection enabled
+func newCreateOrUpdateTest(ctx context.Context, t *testing.T, server *fakeResourceServer) (*azureDeploymentReconcilerInstance, genruntime.ARMMetaObject) {
+	g := NewGomegaWithT(t)
+
+	rg := newTestResourceGroup()
+	rg.Spec.AzureName = "myrg"
+	rg.Spec.Location = to.Ptr("westus")
+	genruntime.SetResourceID(rg, testResourceGroupID)
+
+	r := newTestReconcilerInstance(rg, newTestConnection(t, server.ServeHTTP))
+	r.Config = config.Values{EnableDriftDetection: true}
+	g.Expect(r.KubeClient.Create(ctx, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: rg.Namespace}})).To(Succeed())
+	g.Expect(r.KubeClient.Create(ctx, rg)).To(Succeed())
+
+	return r, rg
+}
+
+func liveTestResourceGroup(location string) map[string]any {
+	return map[string]any{
+		"id":         testResourceGroupID,
+		"name":       "myrg",
+		"location":   location,
+		"properties": map[string]any{"provisioningState": "Succeeded"},
+	}
+}
+
+func Test_BeginCreateOrUpdateResource_InSyncResource_SkipsPUT(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	// ARM normalizes the location it returns
+	server := &fakeResourceServer{live: liveTestResourceGroup("WestUS")}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(0))
+	g.Expect(r.isCurrentGenerationApplied("")).To(BeTrue())
+
+	drift, ok := conditions.GetCondition(rg, conditions.ConditionTypeDriftDetected)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(drift.Status).To(Equal(metav1.ConditionFalse))
+}
+
+func Test_BeginCreateOrUpdateResource_InSyncResource_GETsResourceOnce(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{live: liveTestResourceGroup("westus")}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The resource retrieved to check for drift supplies the status too
+	g.Expect(server.requestMethods()).To(Equal([]string{http.MethodGet}))
+	g.Expect(rg.(*resources.ResourceGroup).Status.Location).To(Equal(to.Ptr("westus")))
+}
+
+func Test_BeginCreateOrUpdateResource_PUTAccepted_GETsStatusAgain(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{live: liveTestResourceGroup("eastus")}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The resource retrieved before the PUT is out of date once it's accepted
+	g.Expect(server.requestMethods()).To(Equal([]string{http.MethodGet, http.MethodPut, http.MethodGet}))
+	g.Expect(rg.(*resources.ResourceGroup).Status.Location).To(Equal(to.Ptr("westus")))
+}
+
+func Test_BeginCreateOrUpdateResource_PUTAccepted_RecordsGeneration(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{}
+	r, _ := newCreateOrUpdateTest(ctx, t, server)
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(1))
+	g.Expect(r.isCurrentGenerationApplied("")).To(BeTrue())
+}
+
+func Test_BeginCreateOrUpdateResource_PUTFails_DoesNotRecordGeneration(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{
+		live:      liveTestResourceGroup("eastus"),
+		putStatus: http.StatusBadRequest,
+	}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(1))
+
+	// The generation hasn't reached Azure, so the difference is still the user's change rather than drift
+	_, hasDrift := conditions.GetCondition(rg, conditions.ConditionTypeDriftDetected)
+	g.Expect(hasDrift).To(BeFalse())
+	g.Expect(rg.GetAnnotations()).ToNot(HaveKey(reconcilers.LatestReconciledGeneration))
+}
+
+func Test_BeginCreateOrUpdateResource_MergeMode_RecordsLastAppliedOnlyOncePUTAccepted(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		putStatus         int
+		expectLastApplied bool
+	}{
+		"PUT accepted": {putStatus: 0, expectLastApplied: true},
+		"PUT fails":    {putStatus: http.StatusBadRequest, expectLastApplied: false},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			ctx := context.Background()
+
+			server := &fakeResourceServer{
+				live:      liveTestResourceGroup("westus"),
+				putStatus: c.putStatus,
+			}
+			r, rg := newCreateOrUpdateTest(ctx, t, server)
+			rg.(*resources.ResourceGroup).Spec.Tags = map[string]string{"env": "test"}
+			genruntime.AddAnnotation(rg, annotations.MergePaths, "tags")
+
+			_, err := r.BeginCreateOrUpdateResource(ctx)
+			g.Expect(err != nil).To(Equal(c.putStatus != 0))
+			g.Expect(server.putCount()).To(Equal(1))
+
+			// If the PUT fails, the tags aren't ours to remove once they're removed from the spec
+			if c.expectLastApplied {
+				g.Expect(rg.GetAnnotations()).To(HaveKey(reconcilers.LastAppliedAnnotation))
+			} else {
+				g.Expect(rg.GetAnnotations()).ToNot(HaveKey(reconcilers.LastAppliedAnnotation))
+			}
+		})
+	}
+}
+
+func Test_BeginCreateOrUpdateResource_RotatedSecret_SendsPUT(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	serverID := testResourceGroupID + "/providers/Microsoft.DBforPostgreSQL/flexibleServers/myserver"
+	server := &fakeResourceServer{id: serverID}
+
+	password := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "server-password", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+
+	flexibleServer := &dbforpostgresql.FlexibleServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "myserver",
+			Namespace:  "default",
+			Generation: 1,
+			UID:        "4f0c1d2e-8f6b-4b8e-a2c3-5d9e7f1a2b3c",
+		},
+		Spec: dbforpostgresql.FlexibleServer_Spec{
+			AzureName:                  "myserver",
+			Location:                   to.Ptr("westus"),
+			Owner:                      &genruntime.KnownResourceReference{ARMID: testResourceGroupID},
+			AdministratorLogin:         to.Ptr("admin"),
+			AdministratorLoginPassword: &genruntime.SecretReference{Name: password.Name, Key: "password"},
+		},
+	}
+	genruntime.SetResourceID(flexibleServer, serverID)
+
+	r := newTestReconcilerInstance(flexibleServer, newTestConnection(t, server.ServeHTTP))
+	r.Config = config.Values{EnableDriftDetection: true}
+	g.Expect(r.KubeClient.Create(ctx, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+	g.Expect(r.KubeClient.Create(ctx, password)).To(Succeed())
+	g.Expect(r.KubeClient.Create(ctx, flexibleServer)).To(Succeed())
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(1))
+
+	// Azure doesn't return the password, so the resource is in sync until the password changes
+	_, err = r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(1))
+
+	// Rotating the secret doesn't change the generation of the resource
+	password.Data = map[string][]byte{"password": []byte("correct-horse-battery-staple")}
+	g.Expect(r.KubeClient.Update(ctx, password)).To(Succeed())
+
+	_, err = r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(2))
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// checkCreationMode ensures that we're allowed to PUT (or adopt) the resource, given its creation mode.
+// When the mode is create-only, we refuse to PUT a resource that already exists in Azure unless we've previously
+// claimed its creation ourselves, as otherwise a name collision would silently take over (and overwrite) someone else's
+// resource. Only the claim counts: a copy of the resource carries the annotations recording our earlier PUTs, but not
+// a claim it can use (see SetCreationClaim).
+// The claim is committed immediately before the first PUT is sent, so that if anything goes wrong between the PUT and
+// the commit that follows it, we still recognise the resource as ours.
+func (r *azureDeploymentReconcilerInstance) checkCreationMode(ctx context.Context) error {
+	mode, err := reconcilers.GetCreationMode(r.Obj)
+	if err != nil {
+		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	if mode.AllowsAdoption() {
+		return nil
+	}
+
+	if HasCreationClaim(r.Obj) {
+		// We've claimed the creation of this resource before, so the resource in Azure is ours
+		return nil
+	}
+
+	resourceID, hasResourceID := genruntime.GetResourceID(r.Obj)
+	if !hasResourceID {
+		return eris.Errorf("resource has no resource id")
+	}
+
+	apiVersion, err := r.GetAPIVersion()
+	if err != nil {
+		return eris.Wrapf(err, "error getting api version for resource %s while checking existence", r.Obj.GetName())
+	}
+
+	// As when getting status, we prefer GET, and only use HEAD for resources that don't support it
+	var exists bool
+	if genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
+		exists, _, err = r.ARMConnection.Client().CheckExistenceWithGetByID(ctx, resourceID, apiVersion)
+	} else if genruntime.ResourceOperationHead.IsSupportedBy(r.Obj) {
+		exists, _, err = r.ARMConnection.Client().CheckExistenceByID(ctx, resourceID, apiVersion)
+	} else {
+		return eris.Errorf("resource must support one of GET or HEAD, but it supports neither")
+	}
+
+	if err != nil {
+		return r.MakeReadyConditionImpactingErrorFromError(
+			eris.Wrapf(err, "checking existence of resource with ID: %q", resourceID))
+	}
+
+	if exists {
+		err = eris.Errorf(
+			"resource %q already exists in Azure and was not created by this resource. Refusing to adopt it as %s is %s",
+			resourceID,
+			annotations.CreationMode,
+			annotations.CreationModeCreateOnly)
+		return conditions.NewReadyConditionImpactingError(
+			err,
+			conditions.ConditionSeverityError,
+			conditions.ReasonAzureResourceAlreadyExists)
+	}
+
+	SetCreationClaim(r.Obj)
+	err = r.KubeClient.CommitObject(ctx, r.Obj, kubeclient.SpecOnly)
+	if err != nil {
+		return eris.Wrapf(err, "claiming creation of resource with ID: %q", resourceID)
+	}
+
+	return nil
+}
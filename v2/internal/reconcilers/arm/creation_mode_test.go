@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// headOnlyResourceGroup is a resource group that can only be checked for existence with HEAD, rather than GET
+type headOnlyResourceGroup struct {
+	*resources.ResourceGroup
+}
+
+func (*headOnlyResourceGroup) GetSupportedOperations() []genruntime.ResourceOperation {
+	return []genruntime.ResourceOperation{
+		genruntime.ResourceOperationDelete,
+		genruntime.ResourceOperationHead,
+		genruntime.ResourceOperationPut,
+	}
+}
+
+func newCreateOnlyResourceGroup(ctx context.Context, t *testing.T) (*resources.ResourceGroup, *azureDeploymentReconcilerInstance, *fakeResourceServer) {
+	g := NewGomegaWithT(t)
+
+	rg := newTestResourceGroup()
+	rg.UID = "c9b5fd5e-57a1-4c0a-9a55-2d0e5b1f1a6b"
+	genruntime.AddAnnotation(rg, annotations.CreationMode, string(annotations.CreationModeCreateOnly))
+	genruntime.SetResourceID(rg, testResourceGroupID)
+
+	server := &fakeResourceServer{}
+	r := newTestReconcilerInstance(rg, newTestConnection(t, server.ServeHTTP))
+	g.Expect(r.KubeClient.Create(ctx, rg)).To(Succeed())
+
+	return rg, r, server
+}
+
+func Test_CheckCreationMode_CreateOnly_ClaimIsCommittedBeforeCreation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	rg, r, server := newCreateOnlyResourceGroup(ctx, t)
+
+	g.Expect(r.checkCreationMode(ctx)).To(Succeed())
+
+	// The claim has been committed, before anything is sent to Azure
+	committed := &resources.ResourceGroup{}
+	g.Expect(r.KubeClient.Get(ctx, types.NamespacedName{Namespace: rg.Namespace, Name: rg.Name}, committed)).To(Succeed())
+	g.Expect(HasCreationClaim(committed)).To(BeTrue())
+
+	// If the commit after the PUT is lost, we still recognise the resource as ours
+	server.setLive(liveTestResourceGroup("westus"))
+	r.Obj = committed
+	g.Expect(r.checkCreationMode(ctx)).To(Succeed())
+}
+
+func Test_CheckCreationMode_CreateOnly_RefusesExistingResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	_, r, server := newCreateOnlyResourceGroup(ctx, t)
+	server.setLive(liveTestResourceGroup("westus"))
+
+	err := r.checkCreationMode(ctx)
+	g.Expect(err).To(HaveOccurred())
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(readyErr.Reason).To(Equal(conditions.ReasonAzureResourceAlreadyExists.Name))
+	g.Expect(HasCreationClaim(r.Obj)).To(BeFalse())
+}
+
+func Test_HasCreationClaim_IgnoresClaimOfCopiedResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	rg := newTestResourceGroup()
+	rg.UID = "original"
+	SetCreationClaim(rg)
+	g.Expect(HasCreationClaim(rg)).To(BeTrue())
+
+	rg.UID = "copy"
+	g.Expect(HasCreationClaim(rg)).To(BeFalse())
+}
+
+func Test_CheckCreationMode_CreateOnly_UsesHEADOnlyWhenGETIsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		headOnly       bool
+		expectedMethod string
+	}{
+		"Supports GET": {headOnly: false, expectedMethod: http.MethodGet},
+		"Only HEAD":    {headOnly: true, expectedMethod: http.MethodHead},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			rg := newTestResourceGroup()
+			genruntime.AddAnnotation(rg, annotations.CreationMode, string(annotations.CreationModeCreateOnly))
+			genruntime.SetResourceID(rg, testResourceGroupID)
+
+			var obj genruntime.ARMMetaObject = rg
+			if c.headOnly {
+				obj = &headOnlyResourceGroup{ResourceGroup: rg}
+			}
+
+			server := &fakeResourceServer{live: liveTestResourceGroup("westus")}
+			r := newTestReconcilerInstance(obj, newTestConnection(t, server.ServeHTTP))
+
+			err := r.checkCreationMode(context.Background())
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(server.requestMethods()).To(Equal([]string{c.expectedMethod}))
+		})
+	}
+}
+
+func Test_GetStatus_HEADOnlyResourceMissing_ReturnsNotFound(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	server := &fakeResourceServer{}
+	obj := &headOnlyResourceGroup{ResourceGroup: newTestResourceGroup()}
+	r := newTestReconcilerInstance(obj, newTestConnection(t, server.ServeHTTP))
+
+	status, _, err := r.getStatus(context.Background(), testResourceGroupID)
+	g.Expect(status).To(BeNil())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(genericarmclient.IsNotFoundError(err)).To(BeTrue())
+	g.Expect(server.requestMethods()).To(Equal([]string{http.MethodHead}))
+}
+
+func Test_CheckCreationMode_CreateOnly_CopyOfReconciledResource_RefusesExistingResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	// A copy of a resource we've reconciled before carries the generation we last applied, but not our claim
+	rg, r, server := newCreateOnlyResourceGroup(ctx, t)
+	SetLatestReconciledGeneration(rg)
+	server.setLive(liveTestResourceGroup("westus"))
+
+	err := r.checkCreationMode(ctx)
+	g.Expect(err).To(HaveOccurred())
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(readyErr.Reason).To(Equal(conditions.ReasonAzureResourceAlreadyExists.Name))
+}
+
+func Test_BeginCreateOrUpdateResource_CreateOnly_OutsideMaintenanceWindow_DoesNotClaim(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+	rg.SetUID("c9b5fd5e-57a1-4c0a-9a55-2d0e5b1f1a6b")
+	genruntime.AddAnnotation(rg, annotations.CreationMode, string(annotations.CreationModeCreateOnly))
+	genruntime.AddAnnotation(rg, annotations.MaintenanceWindow, "0 0 1 1 *")
+	genruntime.AddAnnotation(rg, annotations.MaintenanceWindowDuration, "1m")
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(0))
+
+	// Someone else may create the resource before the window opens, so it's not ours yet
+	g.Expect(HasCreationClaim(rg)).To(BeFalse())
+}
+
+func Test_BeginCreateOrUpdateResource_CreateOnly_ExistingResourceInSync_IsNotAdopted(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{live: liveTestResourceGroup("westus")}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+	rg.SetUID("c9b5fd5e-57a1-4c0a-9a55-2d0e5b1f1a6b")
+	genruntime.AddAnnotation(rg, annotations.CreationMode, string(annotations.CreationModeCreateOnly))
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(0))
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(readyErr.Reason).To(Equal(conditions.ReasonAzureResourceAlreadyExists.Name))
+	g.Expect(rg.GetAnnotations()).ToNot(HaveKey(reconcilers.LatestReconciledGeneration))
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/rotisserie/eris"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// checkDescendantDeletionProtection returns a ReadyConditionImpactingError if a resource contained by this one in Azure
+// is protected from deletion, as deleting this resource from Azure would delete it too. This holds even when both are
+// being deleted (for example, along with their namespace), as the protected resource won't be deleted from Azure
+// itself until its deletion is confirmed.
+func (r *azureDeploymentReconcilerInstance) checkDescendantDeletionProtection(ctx context.Context) error {
+	resourceID, hasResourceID := genruntime.GetResourceID(r.Obj)
+	if !hasResourceID || resourceID == "" {
+		// Never created in Azure, so there's nothing to delete
+		return nil
+	}
+
+	descendants, err := r.ResourceResolver.ResolveDeletionProtectedDescendants(ctx, resourceID)
+	if err != nil {
+		return eris.Wrapf(err, "finding resources protected from deletion within %s", resourceID)
+	}
+
+	for _, descendant := range descendants {
+		if reconcilers.CheckDeletionProtection(descendant) == nil {
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(descendant, r.ResourceResolver.Scheme())
+		if err != nil {
+			return eris.Wrapf(err, "creating GVK for obj %T", descendant)
+		}
+
+		r.Log.V(Status).Info(
+			"Deletion blocked by protected resource",
+			"kind", gvk.Kind,
+			"namespace", descendant.GetNamespace(),
+			"name", descendant.GetName())
+
+		err = eris.Errorf(
+			"deletion is blocked by %s on %s %s/%s, which would be deleted from Azure too. To delete it, set %s on it to %q",
+			annotations.DeletionProtection,
+			gvk.Kind,
+			descendant.GetNamespace(),
+			descendant.GetName(),
+			annotations.ConfirmDeletion,
+			descendant.GetName())
+		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonDeletionProtected)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/rotisserie/eris"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbforpostgresql "github.com/Azure/azure-service-operator/v2/api/dbforpostgresql/v1api20240801"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+func Test_StartDeleteOfResource_NamespaceDeleted_BlockedByProtectedDescendant(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	s := createTestScheme()
+	index := resolver.DeletionProtectedIndex()
+	kubeClient := kubeclient.NewClient(
+		fake.NewClientBuilder().
+			WithScheme(s).
+			WithIndex(&resources.ResourceGroup{}, index.Key, index.Func).
+			WithIndex(&dbforpostgresql.FlexibleServer{}, index.Key, index.Func).
+			Build())
+	res := resolver.NewResolver(kubeClient)
+	g.Expect(res.IndexDeletionProtectedTypes(
+		s,
+		[]*registration.StorageType{
+			{Obj: &resources.ResourceGroup{}, Indexes: []registration.Index{index}},
+			{Obj: &dbforpostgresql.FlexibleServer{}, Indexes: []registration.Index{index}},
+		})).To(Succeed())
+
+	// Deleting the namespace deletes both the resource group and the server within it
+	rg := newTestResourceGroup()
+	rg.Finalizers = []string{genruntime.ReconcilerFinalizer}
+	genruntime.SetResourceID(rg, testResourceGroupID)
+	server := &dbforpostgresql.FlexibleServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "myserver",
+			Namespace:  rg.Namespace,
+			Finalizers: []string{genruntime.ReconcilerFinalizer},
+			Annotations: map[string]string{
+				annotations.DeletionProtection: "true",
+			},
+		},
+	}
+	genruntime.SetResourceID(server, testResourceGroupID+"/providers/Microsoft.DBforPostgreSQL/flexibleServers/myserver")
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       rg.Namespace,
+			Finalizers: []string{"kubernetes"},
+		},
+	}
+	g.Expect(kubeClient.Create(ctx, namespace)).To(Succeed())
+	g.Expect(kubeClient.Create(ctx, rg)).To(Succeed())
+	g.Expect(kubeClient.Create(ctx, server)).To(Succeed())
+	g.Expect(kubeClient.Delete(ctx, namespace)).To(Succeed())
+	g.Expect(kubeClient.Delete(ctx, rg)).To(Succeed())
+	g.Expect(kubeClient.Delete(ctx, server)).To(Succeed())
+
+	azure := &fakeResourceServer{live: liveTestResourceGroup("westus")}
+	r := newTestReconcilerInstance(rg, newTestConnection(t, azure.ServeHTTP))
+	r.KubeClient = kubeClient
+	r.ResourceResolver = res
+
+	// The server is protected, so the resource group containing it mustn't be deleted from Azure
+	_, err := r.StartDeleteOfResource(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("myserver"))
+	var readyErr *conditions.ReadyConditionImpactingError
+	g.Expect(eris.As(err, &readyErr)).To(BeTrue())
+	g.Expect(readyErr.Reason).To(Equal(conditions.ReasonDeletionProtected.Name))
+	g.Expect(azure.requestMethods()).ToNot(ContainElement(http.MethodDelete))
+
+	// Once deletion of the server is confirmed, the resource group may be deleted
+	g.Expect(kubeClient.Get(ctx, client.ObjectKey{Namespace: server.Namespace, Name: server.Name}, server)).To(Succeed())
+	server.Annotations[annotations.ConfirmDeletion] = server.Name
+	g.Expect(kubeClient.Update(ctx, server)).To(Succeed())
+
+	_, err = r.StartDeleteOfResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(azure.requestMethods()).To(ContainElement(http.MethodDelete))
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/rotisserie/eris"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// detectDrift compares the payload we're about to PUT with the live resource in Azure, returning true if they
+// already match and the PUT can be skipped.
+// Any failure to retrieve the live resource returns false, so that we fall back to issuing the PUT as we always have.
+// generationApplied indicates whether the current generation of the resource has already been sent to Azure. If it
+// hasn't, differences are the changes requested by the user rather than drift, and are not reported as such.
+func (r *azureDeploymentReconcilerInstance) detectDrift(
+	ctx context.Context,
+	armResource genruntime.ARMResource,
+	generationApplied bool,
+) bool {
+	if !genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
+		// Can't compare what we can't GET
+		return false
+	}
+
+	differences, found, err := r.compareWithAzure(ctx, armResource)
+	if err != nil {
+		r.Log.V(Status).Info("Unable to check resource for drift, continuing with PUT", "error", err.Error())
+		return false
+	}
+
+	if !found {
+		// Resource doesn't exist yet, so it needs to be created
+		return false
+	}
+
+	// ARM never returns write-only properties (such as passwords), so once they've been sent we have no way to tell
+	// whether they've drifted. Any property Azure omits entirely is treated as write-only.
+	// Until the current generation has been sent, they may be changes requested by the user, so we keep them.
+	if generationApplied {
+		differences = jsondiff.Without(differences, jsondiff.ChangeTypeAbsent)
+	}
+
+	if len(differences) == 0 {
+		r.recordDrift(nil)
+		return true
+	}
+
+	if generationApplied {
+		r.recordDrift(differences)
+	}
+
+	return false
+}
+
+// Observe refreshes the status of a resource we only observe, and reports any drift of the resource in Azure from its
+// spec. As the spec is never sent, differences are reported as drift for every generation. Failure to compare the
+// resource is logged rather than returned, as it doesn't stop us observing it.
+func (r *azureDeploymentReconcilerInstance) Observe(ctx context.Context) error {
+	err := r.handleCreateOrUpdateSuccess(ctx, WatchResource)
+	if err != nil {
+		return err
+	}
+
+	if !genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
+		// Can't compare what we can't GET
+		return nil
+	}
+
+	armResource, err := r.ConvertResourceToARMResource(ctx)
+	if err != nil {
+		r.Log.V(Status).Info("Unable to check resource for drift", "error", err.Error())
+		return nil
+	}
+
+	differences, found, err := r.compareWithAzure(ctx, armResource)
+	if err != nil {
+		r.Log.V(Status).Info("Unable to check resource for drift", "error", err.Error())
+		return nil
+	}
+
+	if !found {
+		// handleCreateOrUpdateSuccess would have already reported the resource missing, unless it was deleted since
+		return nil
+	}
+
+	// We've never sent the spec, so properties Azure doesn't return (such as passwords) can't be compared
+	r.recordDrift(jsondiff.Without(differences, jsondiff.ChangeTypeAbsent))
+	return nil
+}
+
+// recordDrift records the differences between the resource in Azure and its desired state in the DriftDetected
+// condition. An event is emitted when the drift first appears or changes, rather than each time it's seen.
+func (r *azureDeploymentReconcilerInstance) recordDrift(differences []jsondiff.Difference) {
+	if len(differences) == 0 {
+		conditions.SetCondition(r.Obj, r.PositiveConditions.Drift.NoDrift(r.Obj.GetGeneration()))
+		return
+	}
+
+	paths := jsondiff.Paths(differences)
+	condition := r.PositiveConditions.Drift.DriftDetected(r.Obj.GetGeneration(), paths)
+
+	previous, ok := conditions.GetCondition(r.Obj, conditions.ConditionTypeDriftDetected)
+	if !ok || previous.Status != condition.Status || previous.Message != condition.Message {
+		r.Log.V(Status).Info("Detected drift of Azure resource from desired state", "paths", paths)
+		r.Recorder.Eventf(
+			r.Obj,
+			v1.EventTypeWarning,
+			conditions.ReasonDriftDetected,
+			"Azure resource differs from the desired state at: %s",
+			strings.Join(paths, ", "))
+	}
+
+	conditions.SetCondition(r.Obj, condition)
+}
+
+// compareWithAzure GETs the resource from Azure (unless already retrieved during this reconcile) and compares it with
+// the provided ARM payload.
+// Returns the differences found, and a bool indicating whether the resource exists in Azure at all.
+func (r *azureDeploymentReconcilerInstance) compareWithAzure(
+	ctx context.Context,
+	armResource genruntime.ARMResource,
+) ([]jsondiff.Difference, bool, error) {
+	return r.comparePayloadWithAzure(ctx, armResource, armResource.Spec())
+}
+
+// comparePayloadWithAzure is compareWithAzure, for the payload we'd PUT for the provided ARM resource (such as one
+// with properties merged into it, see mergeExternalChanges).
+func (r *azureDeploymentReconcilerInstance) comparePayloadWithAzure(
+	ctx context.Context,
+	armResource genruntime.ARMResource,
+	payload any,
+) ([]jsondiff.Difference, bool, error) {
+	spec := armResource.Spec()
+
+	var live any
+	_, err := r.getLiveResource(ctx, armResource.GetID(), spec.GetAPIVersion(), &live)
+	if err != nil {
+		if genericarmclient.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, eris.Wrapf(err, "getting resource with ID: %q", armResource.GetID())
+	}
+
+	desired, err := jsondiff.ToJSONValue(payload)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The name is part of the resource ID so can't drift, but some RPs return it in a different form than it
+	// was sent (e.g. qualified with the names of parent resources), so we don't compare it.
+	if m, ok := desired.(map[string]any); ok {
+		delete(m, "name")
+	}
+
+	return jsondiff.Compare(desired, live, jsondiff.EnumPaths(spec)), true, nil
+}
+
+// isCurrentGenerationApplied returns true if the current generation of the resource, with the current values of the
+// secrets it refers to (see secretsHash), has previously been sent to Azure.
+func (r *azureDeploymentReconcilerInstance) isCurrentGenerationApplied(secretsHash string) bool {
+	generation, hasGeneration := GetLatestReconciledGeneration(r.Obj)
+	return hasGeneration && generation == r.Obj.GetGeneration() && GetLatestReconciledSecrets(r.Obj) == secretsHash
+}
+
+// recordGenerationApplied records that the current generation of the resource, with the secret values hashed in
+// secretsHash, has been applied to Azure.
+func (r *azureDeploymentReconcilerInstance) recordGenerationApplied(secretsHash string) {
+	SetLatestReconciledGeneration(r.Obj)
+	SetLatestReconciledSecrets(r.Obj, secretsHash)
+}
+
+// secretsHash returns a hash of the values of the secrets the resource refers to, or an empty string if it refers to
+// none. Rotating a secret doesn't change the generation of the resource, and as ARM never returns secret values the
+// change can't be seen as drift either, so the hash is recorded along with the generation to tell whether the current
+// values have been sent to Azure.
+func (r *azureDeploymentReconcilerInstance) secretsHash(ctx context.Context) (string, error) {
+	refs, err := reflecthelpers.FindSecretReferences(r.Obj)
+	if err != nil {
+		return "", eris.Wrapf(err, "finding secrets on %q", r.Obj.GetName())
+	}
+
+	mapRefs, err := reflecthelpers.FindSecretMaps(r.Obj)
+	if err != nil {
+		return "", eris.Wrapf(err, "finding secret maps on %q", r.Obj.GetName())
+	}
+
+	if len(refs) == 0 && len(mapRefs) == 0 {
+		return "", nil
+	}
+
+	secrets, err := r.ResourceResolver.ResolveResourceSecretReferences(ctx, r.Obj)
+	if err != nil {
+		return "", reconcilers.ClassifyResolverError(err)
+	}
+
+	secretMaps, err := r.ResourceResolver.ResolveResourceSecretMapReferences(ctx, r.Obj)
+	if err != nil {
+		return "", reconcilers.ClassifyResolverError(err)
+	}
+
+	// The hash is stored in plain text, so it's salted with the UID of the resource to avoid revealing which
+	// resources share a secret value
+	hash := sha256.New()
+	hash.Write([]byte(r.Obj.GetUID()))
+
+	for _, ref := range sortedByString(refs.Values()) {
+		value, lookupErr := secrets.Lookup(ref)
+		if lookupErr != nil {
+			return "", lookupErr
+		}
+
+		fmt.Fprintf(hash, "\x00%s\x00%s", ref, value)
+	}
+
+	for _, ref := range sortedByString(mapRefs.Values()) {
+		values, lookupErr := secretMaps.Lookup(ref)
+		if lookupErr != nil {
+			return "", lookupErr
+		}
+
+		fmt.Fprintf(hash, "\x00%s", ref)
+		for _, key := range slices.Sorted(maps.Keys(values)) {
+			fmt.Fprintf(hash, "\x00%s\x00%s", key, values[key])
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func sortedByString[T fmt.Stringer](values []T) []T {
+	slices.SortFunc(values, func(left T, right T) int {
+		return strings.Compare(left.String(), right.String())
+	})
+
+	return values
+}
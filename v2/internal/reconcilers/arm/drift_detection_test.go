@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+type serverSpec struct {
+	Name       string            `json:"name,omitempty"`
+	Location   string            `json:"location,omitempty"`
+	Properties *serverProperties `json:"properties,omitempty"`
+}
+
+type serverProperties struct {
+	AdministratorLogin         string `json:"administratorLogin,omitempty"`
+	AdministratorLoginPassword string `json:"administratorLoginPassword,omitempty"`
+}
+
+func (s *serverSpec) GetAPIVersion() string { return "2020-01-01" }
+func (s *serverSpec) GetName() string       { return s.Name }
+func (s *serverSpec) GetType() string       { return "Microsoft.Test/servers" }
+
+func newTestResourceGroup() *resources.ResourceGroup {
+	return &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "myrg",
+			Namespace:  "default",
+			Generation: 2,
+		},
+	}
+}
+
+func newDriftTestARMResource() genruntime.ARMResource {
+	spec := &serverSpec{
+		Name:     "myrg",
+		Location: "westus",
+		Properties: &serverProperties{
+			AdministratorLogin:         "admin",
+			AdministratorLoginPassword: "hunter2",
+		},
+	}
+
+	return genruntime.NewARMResource(spec, nil, testResourceGroupID)
+}
+
+func Test_DetectDrift(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		live              string
+		generationApplied bool
+		expectInSync      bool
+		expectDrift       metav1.ConditionStatus
+		expectEvent       bool
+	}{
+		"Matching resource is in sync": {
+			live:              `{"name": "myrg", "location": "westus", "properties": {"administratorLogin": "admin", "administratorLoginPassword": "hunter2"}}`,
+			generationApplied: true,
+			expectInSync:      true,
+			expectDrift:       metav1.ConditionFalse,
+		},
+		"Write-only property not returned is in sync": {
+			live:              `{"name": "myrg", "location": "westus", "properties": {"administratorLogin": "admin"}}`,
+			generationApplied: true,
+			expectInSync:      true,
+			expectDrift:       metav1.ConditionFalse,
+		},
+		"Write-only property not returned for new generation is sent": {
+			live:              `{"name": "myrg", "location": "westus", "properties": {"administratorLogin": "admin"}}`,
+			generationApplied: false,
+			expectInSync:      false,
+		},
+		"Changed property is drift": {
+			live:              `{"name": "myrg", "location": "westus", "properties": {"administratorLogin": "root"}}`,
+			generationApplied: true,
+			expectInSync:      false,
+			expectDrift:       metav1.ConditionTrue,
+			expectEvent:       true,
+		},
+		"Changed property for new generation isn't drift": {
+			live:              `{"name": "myrg", "location": "westus", "properties": {"administratorLogin": "root"}}`,
+			generationApplied: false,
+			expectInSync:      false,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			connection := newTestConnection(t, func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodGet && req.URL.Path == testResourceGroupID {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(c.live))
+					return
+				}
+
+				w.WriteHeader(http.StatusBadRequest)
+			})
+
+			obj := newTestResourceGroup()
+			r := newTestReconcilerInstance(obj, connection)
+			recorder := r.Recorder.(*record.FakeRecorder)
+
+			inSync := r.detectDrift(context.Background(), newDriftTestARMResource(), c.generationApplied)
+			g.Expect(inSync).To(Equal(c.expectInSync))
+
+			drift, ok := conditions.GetCondition(obj, conditions.ConditionTypeDriftDetected)
+			if c.expectDrift == "" {
+				g.Expect(ok).To(BeFalse())
+			} else {
+				g.Expect(ok).To(BeTrue())
+				g.Expect(drift.Status).To(Equal(c.expectDrift))
+			}
+
+			if c.expectEvent {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring("properties.administratorLogin")))
+			} else {
+				g.Expect(recorder.Events).ToNot(Receive())
+			}
+		})
+	}
+}
+
+func Test_RecordDrift_EmitsEventOnlyWhenDriftChanges(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newTestResourceGroup()
+	r := newTestReconcilerInstance(obj, testConnection{})
+	recorder := r.Recorder.(*record.FakeRecorder)
+
+	tlsDrift := []jsondiff.Difference{{Path: "properties.minimumTlsVersion", Type: jsondiff.ChangeTypeModify}}
+	tagDrift := []jsondiff.Difference{{Path: "tags.env", Type: jsondiff.ChangeTypeModify}}
+
+	r.recordDrift(tlsDrift)
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("properties.minimumTlsVersion")))
+
+	// The same drift seen again (as on every resync of an observed resource) isn't reported again
+	r.recordDrift(tlsDrift)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	r.recordDrift(tagDrift)
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("tags.env")))
+
+	r.recordDrift(nil)
+	g.Expect(recorder.Events).ToNot(Receive())
+	drift, ok := conditions.GetCondition(obj, conditions.ConditionTypeDriftDetected)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(drift.Status).To(Equal(metav1.ConditionFalse))
+}
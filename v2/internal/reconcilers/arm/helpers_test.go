@@ -0,0 +1,188 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/benbjohnson/clock"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	asometrics "github.com/Azure/azure-service-operator/v2/internal/metrics"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon/creds"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+const testResourceGroupID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg"
+
+// testConnection is the Connection used by tests. Its client talks to a fake ARM server (see newTestConnection), and
+// it has a status cache only if one is set.
+type testConnection struct {
+	client *genericarmclient.GenericClient
+	cache  *StatusCache
+}
+
+var _ Connection = testConnection{}
+
+func (c testConnection) Client() *genericarmclient.GenericClient { return c.client }
+
+func (testConnection) CredentialFrom() types.NamespacedName {
+	return types.NamespacedName{Namespace: "default", Name: "aso-credential"}
+}
+
+func (testConnection) SubscriptionID() string { return "00000000-0000-0000-0000-000000000000" }
+
+func (c testConnection) StatusCache() *StatusCache { return c.cache }
+
+// newTestConnection returns a Connection to a fake ARM server serving requests with the provided handler. The server
+// is closed when the test completes.
+func newTestConnection(t *testing.T, handler http.HandlerFunc) testConnection {
+	g := NewGomegaWithT(t)
+
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: server.URL,
+				Audience: cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+			},
+		},
+	}
+
+	options := &genericarmclient.GenericClientOptions{
+		HTTPClient: server.Client(),
+		Metrics:    asometrics.NewARMClientMetrics(),
+	}
+	client, err := genericarmclient.NewGenericClient(cfg, creds.MockTokenCredential{}, options)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return testConnection{client: client}
+}
+
+// newTestReconcilerInstance returns a reconciler instance for the provided resource, talking to the provided connection
+// and to a fake Kubernetes cluster
+func newTestReconcilerInstance(obj genruntime.ARMMetaObject, connection Connection) *azureDeploymentReconcilerInstance {
+	kubeClient := NewFakeKubeClient(createTestScheme())
+	return &azureDeploymentReconcilerInstance{
+		Obj:           obj,
+		Log:           logr.Discard(),
+		Recorder:      record.NewFakeRecorder(10),
+		ARMConnection: connection,
+		ARMOwnedResourceReconcilerCommon: reconcilers.ARMOwnedResourceReconcilerCommon{
+			ResourceResolver: resolver.NewResolver(kubeClient),
+			ReconcilerCommon: reconcilers.ReconcilerCommon{
+				KubeClient:         kubeClient,
+				PositiveConditions: conditions.NewPositiveConditionBuilder(clock.New()),
+			},
+		},
+	}
+}
+
+// fakeResourceServer is a fake ARM server hosting a single resource, by default the test resource group. Use its
+// ServeHTTP method as the handler of newTestConnection.
+type fakeResourceServer struct {
+	lock      sync.Mutex
+	id        string         // The ID of the resource, or empty for the test resource group
+	live      map[string]any // The resource in Azure, or nil if it doesn't exist
+	putStatus int            // The status returned for PUTs, or zero to accept them
+	puts      int
+	methods   []string // The methods of the requests received
+}
+
+func (s *fakeResourceServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := s.id
+	if id == "" {
+		id = testResourceGroupID
+	}
+
+	if req.URL.Path != id {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.methods = append(s.methods, req.Method)
+	w.Header().Set("Content-Type", "application/json")
+	switch req.Method {
+	case http.MethodHead:
+		if s.live == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		if s.live == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "ResourceNotFound", "message": "The resource could not be found."}}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(s.live)
+
+	case http.MethodPut:
+		s.puts++
+		if s.putStatus != 0 {
+			w.WriteHeader(s.putStatus)
+			_, _ = w.Write([]byte(`{"error": {"code": "BadRequest", "message": "The request was rejected."}}`))
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		var live map[string]any
+		_ = json.Unmarshal(body, &live)
+		live["id"] = id
+		live["properties"] = map[string]any{"provisioningState": "Succeeded"}
+		s.live = live
+
+		_ = json.NewEncoder(w).Encode(s.live)
+
+	case http.MethodDelete:
+		s.live = nil
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// setLive sets the resource in Azure, or nil if it doesn't exist
+func (s *fakeResourceServer) setLive(live map[string]any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.live = live
+}
+
+func (s *fakeResourceServer) putCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.puts
+}
+
+// requestMethods returns the methods of the requests received, in order
+func (s *fakeResourceServer) requestMethods() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.methods...)
+}
@@ -29,8 +29,8 @@ type kubernetesResourceExporter interface {
 var _ kubernetesResourceExporter = &configMapExpressionExporter{}
 
 type configMapExpressionExporter struct {
-	obj                 genruntime.ARMMetaObject
-	versionedObj        genruntime.ARMMetaObject
+	obj                 genruntime.MetaObject
+	versionedObj        genruntime.MetaObject
 	expressionEvaluator asocel.ExpressionEvaluator
 }
 
@@ -91,8 +91,8 @@ func (c *configMapExpressionExporter) parseConfigMaps(expressions []*core.Destin
 var _ kubernetesResourceExporter = &secretExpressionExporter{}
 
 type secretExpressionExporter struct {
-	obj                 genruntime.ARMMetaObject
-	versionedObj        genruntime.ARMMetaObject
+	obj                 genruntime.MetaObject
+	versionedObj        genruntime.MetaObject
 	rawSecrets          map[string]string
 	expressionEvaluator asocel.ExpressionEvaluator
 }
@@ -118,7 +118,7 @@ func (s *secretExpressionExporter) Export(ctx context.Context) ([]client.Object,
 
 func (s *secretExpressionExporter) parseSecrets(
 	expressions []*core.DestinationExpression,
-	versionedObj genruntime.ARMMetaObject,
+	versionedObj genruntime.MetaObject,
 	rawSecrets map[string]string,
 ) ([]client.Object, error) {
 	collector := secrets.NewCollector(versionedObj.GetNamespace())
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"time"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/util/schedule"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// checkMaintenanceWindow returns a ReadyConditionImpactingError if the maintenance window configured on the resource
+// (or its namespace) means that we can't change the resource in Azure right now.
+// operation describes the change being held, for the benefit of the user.
+func (r *azureDeploymentReconcilerInstance) checkMaintenanceWindow(ctx context.Context, operation string) error {
+	window, hasWindow, err := r.getMaintenanceWindow(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !hasWindow {
+		return nil
+	}
+
+	now := time.Now()
+	if window.Contains(now) {
+		return nil
+	}
+
+	next := window.NextStart(now)
+	if next.IsZero() {
+		err = eris.Errorf("%s postponed, there is no upcoming maintenance window", operation)
+	} else {
+		err = eris.Errorf("%s postponed until the next maintenance window, starting at %s", operation, next.Format(time.RFC3339))
+	}
+
+	r.Log.V(Status).Info("Outside maintenance window", "operation", operation, "nextWindow", next)
+	return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonReconcilePostponed)
+}
+
+// getMaintenanceWindow returns the maintenance window that applies to the resource, if any. A window specified on the
+// resource takes precedence over one specified on its namespace.
+func (r *azureDeploymentReconcilerInstance) getMaintenanceWindow(ctx context.Context) (schedule.Window, bool, error) {
+	source, err := reconcilers.AnnotationsFromObjectOrNamespace(ctx, r.KubeClient, r.Obj, annotations.MaintenanceWindow)
+	if err != nil {
+		return schedule.Window{}, false, err
+	}
+
+	spec, ok := source[annotations.MaintenanceWindow]
+	if !ok {
+		return schedule.Window{}, false, nil
+	}
+
+	window, err := schedule.ParseWindow(spec, source[annotations.MaintenanceWindowDuration])
+	if err != nil {
+		err = eris.Wrapf(err, "invalid %s", annotations.MaintenanceWindow)
+		return schedule.Window{}, false, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	return window, true, nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// mergedPayload is the payload to send to Azure for a resource in merge mode
+type mergedPayload struct {
+	// body is the payload to PUT
+	body any
+	// applied records what we're applying at each merge path (but not the values), to be recorded once it's been sent
+	applied map[string][]string
+	// removed lists the paths of properties we previously applied but are now removing
+	removed []string
+}
+
+// mergeExternalChanges returns the payload to PUT for the resource. If any merge paths apply to the resource, the
+// properties found at those paths in Azure which were set by someone else are merged into the payload so that our
+// PUT doesn't remove them.
+func (r *azureDeploymentReconcilerInstance) mergeExternalChanges(
+	ctx context.Context,
+	armResource genruntime.ARMResource,
+) (mergedPayload, error) {
+	spec := armResource.Spec()
+	paths, err := r.getMergePaths(ctx)
+	if err != nil {
+		return mergedPayload{}, err
+	}
+
+	if len(paths) == 0 {
+		return mergedPayload{body: spec}, nil
+	}
+
+	desired, err := jsondiff.ToJSONValue(spec)
+	if err != nil {
+		return mergedPayload{}, err
+	}
+
+	result := mergedPayload{
+		body:    desired,
+		applied: jsondiff.Applied(desired, paths),
+	}
+
+	if !genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
+		r.Log.V(Info).Info("Resource does not support GET, unable to merge properties set outside the operator")
+		return result, nil
+	}
+
+	// Drift detection and status use the same resource, so it's retrieved only once
+	var live any
+	_, err = r.getLiveResource(ctx, armResource.GetID(), spec.GetAPIVersion(), &live)
+	if genericarmclient.IsNotFoundError(err) {
+		// Nothing to merge with
+		return result, nil
+	} else if err != nil {
+		// We mustn't PUT without merging, or we'd remove the properties we're meant to preserve
+		return mergedPayload{}, r.MakeReadyConditionImpactingErrorFromError(err)
+	}
+
+	lastApplied, err := GetLastApplied(r.Obj)
+	if err != nil {
+		// A corrupt record means we can't tell what we've applied, so assume nothing and keep everything we find
+		r.Log.V(Info).Info("Ignoring invalid record of last applied properties", "error", err.Error())
+		lastApplied = nil
+	}
+
+	result.body, result.removed = jsondiff.Merge(desired, live, lastApplied, paths)
+	if len(result.removed) > 0 {
+		r.Log.V(Verbose).Info("Removing properties no longer in spec", "paths", result.removed)
+	}
+
+	return result, nil
+}
+
+// getMergePaths returns the JSON paths where properties set outside the operator are preserved for the resource,
+// combining those configured for its type with those from the merge-paths annotation on the resource (or its
+// namespace).
+func (r *azureDeploymentReconcilerInstance) getMergePaths(ctx context.Context) ([]string, error) {
+	var paths []string
+	if len(r.Config.MergePaths) > 0 {
+		gvk, err := r.KubeClient.GroupVersionKindFor(r.Obj)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, r.Config.MergePaths.For(gvk.GroupKind())...)
+	}
+
+	source, err := reconcilers.AnnotationsFromObjectOrNamespace(ctx, r.KubeClient, r.Obj, annotations.MergePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	paths = append(paths, config.ParseJSONPaths(source[annotations.MergePaths])...)
+
+	// Remove duplicates, preserving order
+	seen := set.Make[string]()
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if seen.Contains(path) {
+			continue
+		}
+
+		seen.Add(path)
+		result = append(result, path)
+	}
+
+	return result, nil
+}
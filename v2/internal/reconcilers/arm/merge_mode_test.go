@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+func Test_MergeExternalChanges_LastAppliedNeverRecordsValues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	connection := newTestConnection(t, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet && req.URL.Path == testResourceGroupID {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name": "myrg", "location": "westus", "properties": {"administratorLogin": "admin", "publicNetworkAccess": "Disabled"}}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	obj := newTestResourceGroup()
+	genruntime.AddAnnotation(obj, annotations.MergePaths, "properties,properties.administratorLoginPassword")
+	r := newTestReconcilerInstance(obj, connection)
+
+	// The spec includes a password, which must not be written to the annotation
+	payload, err := r.mergeExternalChanges(ctx, newDriftTestARMResource())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(SetLastApplied(obj, payload.applied)).To(Succeed())
+
+	lastApplied := obj.GetAnnotations()[reconcilers.LastAppliedAnnotation]
+	g.Expect(lastApplied).ToNot(BeEmpty())
+	g.Expect(lastApplied).ToNot(ContainSubstring("hunter2"))
+	g.Expect(lastApplied).ToNot(ContainSubstring(`"admin"`))
+
+	// But we still know what we applied, so we can remove it once it's no longer in the spec
+	applied, err := GetLastApplied(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(applied).To(Equal(map[string][]string{
+		"properties":                            {"administratorLogin", "administratorLoginPassword"},
+		"properties.administratorLoginPassword": {},
+	}))
+}
+
+func Test_BeginCreateOrUpdateResource_MergeMode_InSyncResource_GETsResourceOnce(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	live := liveTestResourceGroup("westus")
+	live["tags"] = map[string]any{"owner": "someone-else"}
+	server := &fakeResourceServer{live: live}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+	genruntime.AddAnnotation(rg, annotations.MergePaths, "tags")
+
+	_, err := r.BeginCreateOrUpdateResource(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(server.putCount()).To(Equal(0))
+
+	// Merging, drift detection and status all share the resource retrieved from Azure
+	g.Expect(server.requestMethods()).To(Equal([]string{http.MethodGet}))
+}
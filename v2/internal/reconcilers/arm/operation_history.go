@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/rotisserie/eris"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/ownerutil"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+)
+
+// recordOperations records the requests sent to ARM by an action in the OperationHistory of the resource.
+// Failures are logged rather than returned, as the history is informational and mustn't block reconciliation.
+func (r *azureDeploymentReconcilerInstance) recordOperations(
+	ctx context.Context,
+	action string,
+	requests []genericarmclient.RecordedRequest,
+	actionErr error,
+) {
+	if r.Config.OperationHistoryLength <= 0 {
+		return
+	}
+
+	// Monitoring actions only change the history when the operation they're monitoring completes
+	isMonitor := action == string(CreateOrUpdateActionMonitorCreation) || action == string(DeleteActionMonitorDelete)
+	if len(requests) == 0 && !isMonitor {
+		return
+	}
+
+	err := r.updateOperationHistory(ctx, action, requests, actionErr)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			r.Log.V(Verbose).Info("OperationHistory CRD is not installed, not recording operations")
+			return
+		}
+
+		r.Log.V(Info).Info("Unable to record operation history", "error", err.Error())
+	}
+}
+
+func (r *azureDeploymentReconcilerInstance) updateOperationHistory(
+	ctx context.Context,
+	action string,
+	requests []genericarmclient.RecordedRequest,
+	actionErr error,
+) error {
+	gvk, err := r.KubeClient.GroupVersionKindFor(r.Obj)
+	if err != nil {
+		return err
+	}
+
+	history := &serviceoperator.OperationHistory{}
+	key := types.NamespacedName{
+		Namespace: r.Obj.GetNamespace(),
+		Name:      OperationHistoryName(gvk.GroupKind(), r.Obj.GetName()),
+	}
+
+	exists := true
+	err = r.KubeClient.Get(ctx, key, history)
+	if apierrors.IsNotFound(err) {
+		exists = false
+		history = &serviceoperator.OperationHistory{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+		}
+	} else if err != nil {
+		return eris.Wrapf(err, "getting OperationHistory %s", key)
+	}
+
+	generation, ok := GetLatestReconciledGeneration(r.Obj)
+	if !ok {
+		generation = r.Obj.GetGeneration()
+	}
+
+	_, _, hasPollerToken := GetPollerResumeToken(r.Obj)
+	inProgress := actionErr == nil && hasPollerToken
+
+	operations, changed := appendOperations(
+		history.Operations,
+		action,
+		requests,
+		generation,
+		inProgress,
+		cloudErrorDetailsFromError(actionErr),
+		time.Now(),
+		r.Config.OperationHistoryLength)
+	if !changed {
+		return nil
+	}
+
+	history.Operations = operations
+	history.Resource = serviceoperator.OperationHistoryResource{
+		Group:      gvk.Group,
+		Kind:       gvk.Kind,
+		Name:       r.Obj.GetName(),
+		ResourceID: genruntime.GetResourceIDOrDefault(r.Obj),
+	}
+
+	// Owned by the resource, so that it's garbage collected along with it
+	ownerRef := metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       r.Obj.GetName(),
+		UID:        r.Obj.GetUID(),
+	}
+	history.SetOwnerReferences(ownerutil.EnsureOwnerRef(history.GetOwnerReferences(), ownerRef))
+
+	if exists {
+		return r.KubeClient.Update(ctx, history)
+	}
+
+	return r.KubeClient.Create(ctx, history)
+}
+
+// appendOperations returns the operations of a history updated with the requests made by an action, and whether
+// anything changed. The most recent maxLength operations are kept.
+// inProgress indicates the action left a long-running operation underway, and actionErr is the classified error
+// from the action, if any.
+func appendOperations(
+	operations []serviceoperator.Operation,
+	action string,
+	requests []genericarmclient.RecordedRequest,
+	generation int64,
+	inProgress bool,
+	actionErr *core.CloudErrorDetails,
+	now time.Time,
+	maxLength int,
+) ([]serviceoperator.Operation, bool) {
+	var recorded []serviceoperator.Operation
+	for _, req := range requests {
+		// POSTs are used to retrieve secrets (such as listKeys), they don't change the resource
+		if req.Method == http.MethodPost {
+			continue
+		}
+
+		endTime := metav1.NewTime(req.EndTime)
+		recorded = append(recorded, serviceoperator.Operation{
+			Action:        action,
+			Verb:          req.Method,
+			StartTime:     metav1.NewTime(req.StartTime),
+			EndTime:       &endTime,
+			HTTPStatus:    req.StatusCode,
+			CorrelationID: req.CorrelationID,
+			RequestID:     req.RequestID,
+			Generation:    generation,
+		})
+	}
+
+	if len(recorded) > 0 {
+		// Any error, or long-running operation, belongs to the last request made
+		last := &recorded[len(recorded)-1]
+		last.Error = actionErr
+		if inProgress {
+			last.EndTime = nil
+		}
+
+		operations = append(operations, recorded...)
+	} else {
+		// No new requests, so we've been monitoring the long-running operation started by an earlier action
+		if inProgress {
+			return operations, false
+		}
+
+		index := -1
+		for i := len(operations) - 1; i >= 0; i-- {
+			if operations[i].InProgress() {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			return operations, false
+		}
+
+		endTime := metav1.NewTime(now)
+		operations[index].EndTime = &endTime
+		operations[index].Error = actionErr
+	}
+
+	if len(operations) > maxLength {
+		operations = operations[len(operations)-maxLength:]
+	}
+
+	return operations, true
+}
+
+// cloudErrorDetailsFromError returns the classification of an error returned by an action, or nil if there was no
+// error. Errors from ARM have already been classified into a ReadyConditionImpactingError by the time we see them.
+func cloudErrorDetailsFromError(err error) *core.CloudErrorDetails {
+	if err == nil {
+		return nil
+	}
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	if !ok {
+		return &core.CloudErrorDetails{
+			Classification: core.ErrorRetryable,
+			Code:           core.UnknownErrorCode,
+			Message:        err.Error(),
+		}
+	}
+
+	classification := core.ErrorRetryable
+	if readyErr.Severity == conditions.ConditionSeverityError {
+		classification = core.ErrorFatal
+	}
+
+	return &core.CloudErrorDetails{
+		Classification: classification,
+		Retry:          readyErr.RetryClassification,
+		Code:           readyErr.Reason,
+		Message:        readyErr.Cause().Error(),
+	}
+}
+
+// OperationHistoryName returns the name of the OperationHistory for the resource with the specified kind and name.
+// The kind is included as resources of different kinds may share a name.
+func OperationHistoryName(groupKind schema.GroupKind, name string) string {
+	result := strings.ToLower(fmt.Sprintf("%s.%s.%s", name, groupKind.Kind, groupKind.Group))
+	if len(result) <= validation.DNS1123SubdomainMaxLength {
+		return result
+	}
+
+	// Too long, so truncate and add a hash to keep the name unique
+	hash := sha256.Sum256([]byte(result))
+	suffix := hex.EncodeToString(hash[:])[:16]
+	prefix := strings.TrimRight(result[:validation.DNS1123SubdomainMaxLength-len(suffix)-1], ".-")
+
+	return prefix + "-" + suffix
+}
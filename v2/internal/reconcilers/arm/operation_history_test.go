@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/retry"
+)
+
+var testTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func recordedRequest(method string, status int) genericarmclient.RecordedRequest {
+	return genericarmclient.RecordedRequest{
+		Method:        method,
+		StartTime:     testTime,
+		EndTime:       testTime.Add(time.Second),
+		StatusCode:    status,
+		CorrelationID: "correlation-id",
+		RequestID:     "request-id",
+	}
+}
+
+func Test_AppendOperations_RecordsCompletedRequest(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	operations, changed := appendOperations(
+		nil,
+		string(CreateOrUpdateActionBeginCreation),
+		[]genericarmclient.RecordedRequest{recordedRequest(http.MethodPut, http.StatusOK)},
+		3,
+		false,
+		nil,
+		testTime,
+		10)
+
+	g.Expect(changed).To(BeTrue())
+	g.Expect(operations).To(HaveLen(1))
+	g.Expect(operations[0].Verb).To(Equal(http.MethodPut))
+	g.Expect(operations[0].Generation).To(Equal(int64(3)))
+	g.Expect(operations[0].CorrelationID).To(Equal("correlation-id"))
+	g.Expect(operations[0].InProgress()).To(BeFalse())
+	g.Expect(operations[0].Error).To(BeNil())
+}
+
+func Test_AppendOperations_CompletesLongRunningOperation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	operations, changed := appendOperations(
+		nil,
+		string(CreateOrUpdateActionBeginCreation),
+		[]genericarmclient.RecordedRequest{recordedRequest(http.MethodPut, http.StatusCreated)},
+		1,
+		true,
+		nil,
+		testTime,
+		10)
+	g.Expect(changed).To(BeTrue())
+	g.Expect(operations[0].InProgress()).To(BeTrue())
+
+	// Still running
+	_, changed = appendOperations(operations, string(CreateOrUpdateActionMonitorCreation), nil, 1, true, nil, testTime, 10)
+	g.Expect(changed).To(BeFalse())
+
+	// Failed
+	failure := &core.CloudErrorDetails{
+		Classification: core.ErrorFatal,
+		Code:           "InvalidParameter",
+		Message:        "bad sku",
+	}
+	completedAt := testTime.Add(time.Minute)
+	operations, changed = appendOperations(operations, string(CreateOrUpdateActionMonitorCreation), nil, 1, false, failure, completedAt, 10)
+	g.Expect(changed).To(BeTrue())
+	g.Expect(operations).To(HaveLen(1))
+	g.Expect(operations[0].InProgress()).To(BeFalse())
+	g.Expect(operations[0].EndTime.Time).To(Equal(completedAt))
+	g.Expect(operations[0].Error).To(Equal(failure))
+}
+
+func Test_AppendOperations_KeepsMostRecent(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var operations []serviceoperator.Operation
+	for generation := int64(1); generation <= 5; generation++ {
+		operations, _ = appendOperations(
+			operations,
+			string(CreateOrUpdateActionBeginCreation),
+			[]genericarmclient.RecordedRequest{recordedRequest(http.MethodPut, http.StatusOK)},
+			generation,
+			false,
+			nil,
+			testTime,
+			3)
+	}
+
+	g.Expect(operations).To(HaveLen(3))
+	g.Expect(operations[0].Generation).To(Equal(int64(3)))
+	g.Expect(operations[2].Generation).To(Equal(int64(5)))
+}
+
+func Test_AppendOperations_IgnoresPost(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	operations, changed := appendOperations(
+		nil,
+		string(CreateOrUpdateActionBeginCreation),
+		[]genericarmclient.RecordedRequest{
+			recordedRequest(http.MethodPut, http.StatusOK),
+			recordedRequest(http.MethodPost, http.StatusOK),
+		},
+		1,
+		false,
+		nil,
+		testTime,
+		10)
+
+	g.Expect(changed).To(BeTrue())
+	g.Expect(operations).To(HaveLen(1))
+	g.Expect(operations[0].Verb).To(Equal(http.MethodPut))
+}
+
+func Test_CloudErrorDetailsFromError_UsesClassification(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(cloudErrorDetailsFromError(nil)).To(BeNil())
+
+	err := conditions.NewReadyConditionImpactingError(
+		genericarmclient.NewTestCloudError("InvalidParameter", "bad sku", genericarmclient.WithTestInnerError(eris.New("bad sku"))),
+		conditions.ConditionSeverityError,
+		conditions.MakeReason("InvalidParameter", retry.Slow))
+
+	details := cloudErrorDetailsFromError(err)
+	g.Expect(details.Classification).To(Equal(core.ErrorFatal))
+	g.Expect(details.Code).To(Equal("InvalidParameter"))
+	g.Expect(details.Retry).To(Equal(retry.Slow))
+}
+
+func Test_OperationHistoryName_IsValid(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	gk := schema.GroupKind{Group: "resources.azure.com", Kind: "ResourceGroup"}
+	g.Expect(OperationHistoryName(gk, "myrg")).To(Equal("myrg.resourcegroup.resources.azure.com"))
+
+	long := OperationHistoryName(gk, strings.Repeat("a", 250))
+	g.Expect(validation.IsDNS1123Subdomain(long)).To(BeEmpty())
+	g.Expect(long).ToNot(Equal(OperationHistoryName(gk, strings.Repeat("a", 249))))
+}
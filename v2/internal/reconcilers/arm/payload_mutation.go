@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// applyPayloadMutations applies the rules of any PayloadMutationPolicy matching the resource to the payload we send
+// to ARM, returning the mutated payload.
+func (r *azureDeploymentReconcilerInstance) applyPayloadMutations(
+	ctx context.Context,
+	armResource genruntime.ARMResource,
+) (genruntime.ARMResource, error) {
+	if !r.applyPayloadMutationPolicies {
+		// CRD isn't installed, so there can't be any policies
+		return armResource, nil
+	}
+
+	var policies serviceoperator.PayloadMutationPolicyList
+	err := r.KubeClient.List(ctx, &policies)
+	if err != nil {
+		return nil, eris.Wrap(err, "listing PayloadMutationPolicies")
+	}
+
+	if len(policies.Items) == 0 {
+		return armResource, nil
+	}
+
+	gvk, err := r.KubeClient.GroupVersionKindFor(r.Obj)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := matchingPayloadMutationRules(policies.Items, gvk.GroupKind(), r.Obj.GetNamespace())
+	if len(rules) == 0 {
+		return armResource, nil
+	}
+
+	// Rules are written against the API version of the resource the user sees, not the storage version
+	self, err := genruntime.ObjAsOriginalVersion(r.Obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := jsondiff.ToJSONValue(armResource.Spec())
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := value.(map[string]any)
+	if !ok {
+		return nil, eris.Errorf("expected payload of %s to be an object, but was %T", r.Obj.GetType(), value)
+	}
+
+	for _, rule := range rules {
+		patch, err := r.ExpressionEvaluator.CompileAndRunPatch(rule.Patch, self, payload)
+		if err != nil {
+			err = eris.Wrapf(err, "evaluating payload mutation rule %q", rule.Name)
+			return nil, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+		}
+
+		r.Log.V(Verbose).Info("Applying payload mutation rule", "rule", rule.Name)
+		payload, _ = jsondiff.ApplyMergePatch(payload, patch).(map[string]any)
+	}
+
+	spec, err := specFromJSONValue(armResource.Spec(), payload)
+	if err != nil {
+		err = eris.Wrap(err, "applying payload mutation rules")
+		return nil, conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	return genruntime.NewARMResource(spec, armResource.Status(), armResource.GetID()), nil
+}
+
+// matchingPayloadMutationRules returns the rules from the policies that apply to resources of the specified kind in
+// the specified namespace. Policies are taken in order of name, so that the order rules are applied is predictable.
+func matchingPayloadMutationRules(
+	policies []serviceoperator.PayloadMutationPolicy,
+	groupKind schema.GroupKind,
+	namespace string,
+) []serviceoperator.PayloadMutationRule {
+	sorted := make([]serviceoperator.PayloadMutationPolicy, len(policies))
+	copy(sorted, policies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var result []serviceoperator.PayloadMutationRule
+	for _, policy := range sorted {
+		for _, rule := range policy.Spec.Rules {
+			if rule.Match.Matches(groupKind, namespace) {
+				result = append(result, rule)
+			}
+		}
+	}
+
+	return result
+}
+
+// payloadSpec is implemented by ARM specs which aren't fully described by their JSON payload, such as that of
+// ARMResource, whose ARM type and API version are held alongside it.
+type payloadSpec interface {
+	WithPayload(data []byte) (genruntime.ARMResourceSpec, error)
+}
+
+// specFromJSONValue returns a new spec of the same type as spec, populated from the provided generic JSON value.
+// Properties the spec type doesn't have are an error, rather than being silently dropped.
+func specFromJSONValue(spec genruntime.ARMResourceSpec, value any) (genruntime.ARMResourceSpec, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, eris.Wrap(err, "serializing payload")
+	}
+
+	if ps, ok := spec.(payloadSpec); ok {
+		result, err := ps.WithPayload(data)
+		if err != nil {
+			return nil, eris.Wrap(err, "deserializing payload")
+		}
+
+		return result, nil
+	}
+
+	specType := reflect.TypeOf(spec)
+	if specType.Kind() != reflect.Ptr {
+		return nil, eris.Errorf("expected spec to be a pointer, but was %s", specType)
+	}
+
+	result := reflect.New(specType.Elem()).Interface()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(result)
+	if err != nil {
+		return nil, eris.Wrapf(err, "deserializing payload into %s", specType.Elem().Name())
+	}
+
+	return result.(genruntime.ARMResourceSpec), nil
+}
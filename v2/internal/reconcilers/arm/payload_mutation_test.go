@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+)
+
+func payloadMutationPolicy(name string, rules ...serviceoperator.PayloadMutationRule) serviceoperator.PayloadMutationPolicy {
+	return serviceoperator.PayloadMutationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       serviceoperator.PayloadMutationPolicySpec{Rules: rules},
+	}
+}
+
+func Test_MatchingPayloadMutationRules_FiltersAndOrders(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	policies := []serviceoperator.PayloadMutationPolicy{
+		payloadMutationPolicy(
+			"b-storage",
+			serviceoperator.PayloadMutationRule{
+				Name: "tls",
+				Match: serviceoperator.ResourceMatch{
+					Groups: []string{"storage.azure.com"},
+					Kinds:  []string{"storageaccount"},
+				},
+			},
+			serviceoperator.PayloadMutationRule{
+				Name: "other-namespace",
+				Match: serviceoperator.ResourceMatch{
+					Namespaces: []string{"team-b"},
+				},
+			}),
+		payloadMutationPolicy(
+			"a-everything",
+			serviceoperator.PayloadMutationRule{Name: "all"}),
+		payloadMutationPolicy(
+			"c-keyvault",
+			serviceoperator.PayloadMutationRule{
+				Name:  "keyvault",
+				Match: serviceoperator.ResourceMatch{Groups: []string{"keyvault.azure.com"}},
+			}),
+	}
+
+	gk := schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccount"}
+	rules := matchingPayloadMutationRules(policies, gk, "team-a")
+
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+
+	g.Expect(names).To(Equal([]string{"all", "tls"}))
+}
+
+func Test_SpecFromJSONValue_PopulatesNewSpec(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	original := &taggedSpec{Name: "thing"}
+	spec, err := specFromJSONValue(original, map[string]any{
+		"name": "thing",
+		"tags": map[string]any{"owner": "team-a"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(spec).To(Equal(&taggedSpec{Name: "thing", Tags: map[string]string{"owner": "team-a"}}))
+	g.Expect(original.Tags).To(BeNil())
+}
+
+func Test_SpecFromJSONValue_RejectsUnknownProperties(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	_, err := specFromJSONValue(&taggedSpec{}, map[string]any{
+		"name":       "thing",
+		"properties": map[string]any{"minimumTlsVersion": "TLS1_2"},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("unknown field")))
+}
+
+func Test_SpecFromJSONValue_PreservesTypeAndAPIVersionOfARMResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	original := &serviceoperator.ARMResource_Spec_ARM{
+		APIVersion: "2024-01-01",
+		Type:       "Microsoft.Example/widgets",
+		Name:       "thing",
+	}
+	spec, err := specFromJSONValue(original, map[string]any{
+		"name":     "thing",
+		"location": "westus",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(spec.GetAPIVersion()).To(Equal("2024-01-01"))
+	g.Expect(spec.GetType()).To(Equal("Microsoft.Example/widgets"))
+	g.Expect(spec.GetName()).To(Equal("thing"))
+
+	value, err := jsondiff.ToJSONValue(spec)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(value).To(Equal(map[string]any{"name": "thing", "location": "westus"}))
+}
+
+func Test_ApplyPayloadMutations_CRDNotInstalled_DoesNotLookForPolicies(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// Without the PayloadMutationPolicy type, any attempt to list policies fails
+	s := runtime.NewScheme()
+	g.Expect(resources.AddToScheme(s)).To(Succeed())
+
+	r := newTestReconcilerInstance(newTestResourceGroup(), testConnection{})
+	r.KubeClient = NewFakeKubeClient(s)
+
+	armResource := newDriftTestARMResource()
+	result, err := r.applyPayloadMutations(context.Background(), armResource)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(BeIdenticalTo(armResource))
+
+	r.applyPayloadMutationPolicies = true
+	_, err = r.applyPayloadMutations(context.Background(), armResource)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/rotisserie/eris"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/ownerutil"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// Plan computes the payload we would PUT to Azure and compares it with the live resource, recording the planned
+// change in the ChangesPlanned condition, in the ResourcePlan of the resource, and as an event. The resource in Azure
+// is never modified.
+// The condition and event only have the paths of changed properties. The ResourcePlan has their values too, except
+// where they include the value of a secret.
+func (r *azureDeploymentReconcilerInstance) Plan(ctx context.Context) error {
+	if !genruntime.ResourceOperationGet.IsSupportedBy(r.Obj) {
+		err := eris.Errorf("unable to plan changes for %s as it does not support GET", r.Obj.GetType())
+		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	armResource, err := r.ConvertResourceToARMResource(ctx)
+	if err != nil {
+		return err
+	}
+
+	secretsHash, err := r.secretsHash(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Plan what we'd actually PUT, including the properties merge mode would keep or remove
+	payload, err := r.mergeExternalChanges(ctx, armResource)
+	if err != nil {
+		return err
+	}
+
+	differences, found, err := r.comparePayloadWithAzure(ctx, armResource, payload.body)
+	if err != nil {
+		return r.MakeReadyConditionImpactingErrorFromError(err)
+	}
+
+	// As for drift detection, properties Azure doesn't return (such as passwords) can't be compared once they've
+	// been sent, so wouldn't be changed by a PUT of the current generation
+	if r.isCurrentGenerationApplied(secretsHash) {
+		differences = jsondiff.Without(differences, jsondiff.ChangeTypeAbsent)
+	}
+
+	// Properties present only in Azure aren't compared, so those merge mode would remove are added separately
+	if found && len(payload.removed) > 0 {
+		var live any
+		_, err = r.getLiveResource(ctx, armResource.GetID(), armResource.Spec().GetAPIVersion(), &live)
+		if err != nil {
+			return r.MakeReadyConditionImpactingErrorFromError(err)
+		}
+
+		differences = append(differences, jsondiff.Removals(live, payload.removed)...)
+		slices.SortFunc(differences, func(left jsondiff.Difference, right jsondiff.Difference) int {
+			return strings.Compare(left.Path, right.Path)
+		})
+	}
+
+	secrets, err := r.secretValues(ctx)
+	if err != nil {
+		return err
+	}
+
+	var condition conditions.Condition
+	var action serviceoperator.ResourcePlanAction
+	switch {
+	case !found:
+		action = serviceoperator.ResourcePlanActionCreate
+		condition = r.PositiveConditions.Plan.Create(r.Obj.GetGeneration())
+	case len(differences) == 0:
+		action = serviceoperator.ResourcePlanActionNoOp
+		condition = r.PositiveConditions.Plan.NoOp(r.Obj.GetGeneration())
+	default:
+		changes := make([]string, 0, len(differences))
+		for _, d := range differences {
+			changes = append(changes, d.String())
+		}
+
+		action = serviceoperator.ResourcePlanActionModify
+		condition = r.PositiveConditions.Plan.Modify(r.Obj.GetGeneration(), changes)
+	}
+
+	r.Log.V(Status).Info("Planned changes to resource", "reason", condition.Reason, "message", condition.Message)
+	conditions.SetCondition(r.Obj, condition)
+	r.Recorder.Event(r.Obj, v1.EventTypeNormal, condition.Reason, condition.Message)
+	r.recordPlan(ctx, newResourcePlanStatus(action, differences, secrets, r.Obj.GetGeneration(), time.Now()))
+
+	if !found {
+		// Nothing to refresh status from
+		return nil
+	}
+
+	return r.updateStatus(ctx)
+}
+
+// recordPlan records the full plan for the resource in its ResourcePlan, as the ChangesPlanned condition only has
+// room for a summary. Failures are logged rather than returned, as the condition still records the outcome.
+func (r *azureDeploymentReconcilerInstance) recordPlan(ctx context.Context, status serviceoperator.ResourcePlanStatus) {
+	if !r.recordResourcePlans {
+		r.Log.V(Verbose).Info("ResourcePlan CRD is not installed, not recording plan")
+		return
+	}
+
+	err := r.updateResourcePlan(ctx, status)
+	if err != nil {
+		r.Log.V(Info).Info("Unable to record plan", "error", err.Error())
+	}
+}
+
+func (r *azureDeploymentReconcilerInstance) updateResourcePlan(ctx context.Context, status serviceoperator.ResourcePlanStatus) error {
+	gvk, err := r.KubeClient.GroupVersionKindFor(r.Obj)
+	if err != nil {
+		return err
+	}
+
+	// Named in the same way as the OperationHistory of the resource
+	plan := &serviceoperator.ResourcePlan{}
+	key := types.NamespacedName{
+		Namespace: r.Obj.GetNamespace(),
+		Name:      OperationHistoryName(gvk.GroupKind(), r.Obj.GetName()),
+	}
+
+	exists := true
+	err = r.KubeClient.Get(ctx, key, plan)
+	if apierrors.IsNotFound(err) {
+		exists = false
+		plan = &serviceoperator.ResourcePlan{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+		}
+	} else if err != nil {
+		return eris.Wrapf(err, "getting ResourcePlan %s", key)
+	}
+
+	plan.Status = status
+	plan.Resource = serviceoperator.OperationHistoryResource{
+		Group:      gvk.Group,
+		Kind:       gvk.Kind,
+		Name:       r.Obj.GetName(),
+		ResourceID: genruntime.GetResourceIDOrDefault(r.Obj),
+	}
+
+	// Owned by the resource, so that it's garbage collected along with it
+	ownerRef := metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       r.Obj.GetName(),
+		UID:        r.Obj.GetUID(),
+	}
+	plan.SetOwnerReferences(ownerutil.EnsureOwnerRef(plan.GetOwnerReferences(), ownerRef))
+
+	if exists {
+		return r.KubeClient.Update(ctx, plan)
+	}
+
+	return r.KubeClient.Create(ctx, plan)
+}
+
+// newResourcePlanStatus returns the plan to record for a resource of the specified generation. Values including any of
+// the provided secrets are redacted.
+func newResourcePlanStatus(
+	action serviceoperator.ResourcePlanAction,
+	differences []jsondiff.Difference,
+	secrets []string,
+	generation int64,
+	now time.Time,
+) serviceoperator.ResourcePlanStatus {
+	result := serviceoperator.ResourcePlanStatus{
+		Action:      action,
+		Generation:  generation,
+		PlannedTime: metav1.NewTime(now),
+	}
+
+	if action != serviceoperator.ResourcePlanActionModify {
+		return result
+	}
+
+	result.ChangeCount = len(differences)
+	result.Changes = make([]serviceoperator.PlannedChange, 0, len(differences))
+	for _, d := range differences {
+		change := serviceoperator.PlannedChange{
+			Path: d.Path,
+			Type: string(d.Type),
+		}
+
+		oldValue, oldRedacted := plannedValue(d.Actual, secrets)
+		newValue, newRedacted := plannedValue(d.Desired, secrets)
+		if oldRedacted || newRedacted {
+			change.Redacted = true
+		} else {
+			change.OldValue = oldValue
+			change.NewValue = newValue
+		}
+
+		result.Changes = append(result.Changes, change)
+	}
+
+	return result
+}
+
+// plannedValue returns the value to record for a property in a plan, or true if it must be redacted as it includes
+// any of the provided secrets (or can't be serialized to check). Values we don't have are returned as nil.
+func plannedValue(value any, secrets []string) (*apiextensionsv1.JSON, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, true
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		// Look for the secret as it appears when serialized, in case it has characters that JSON escapes
+		escaped, err := json.Marshal(secret)
+		if err != nil || bytes.Contains(raw, escaped[1:len(escaped)-1]) {
+			return nil, true
+		}
+	}
+
+	return &apiextensionsv1.JSON{Raw: raw}, false
+}
+
+// secretValues returns the values of the secrets the resource refers to, so that they can be kept out of its plan
+func (r *azureDeploymentReconcilerInstance) secretValues(ctx context.Context) ([]string, error) {
+	refs, err := reflecthelpers.FindSecretReferences(r.Obj)
+	if err != nil {
+		return nil, eris.Wrapf(err, "finding secrets on %q", r.Obj.GetName())
+	}
+
+	mapRefs, err := reflecthelpers.FindSecretMaps(r.Obj)
+	if err != nil {
+		return nil, eris.Wrapf(err, "finding secret maps on %q", r.Obj.GetName())
+	}
+
+	if len(refs) == 0 && len(mapRefs) == 0 {
+		return nil, nil
+	}
+
+	secrets, err := r.ResourceResolver.ResolveResourceSecretReferences(ctx, r.Obj)
+	if err != nil {
+		return nil, reconcilers.ClassifyResolverError(err)
+	}
+
+	secretMaps, err := r.ResourceResolver.ResolveResourceSecretMapReferences(ctx, r.Obj)
+	if err != nil {
+		return nil, reconcilers.ClassifyResolverError(err)
+	}
+
+	var result []string
+	for _, ref := range refs.Values() {
+		value, lookupErr := secrets.Lookup(ref)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		result = append(result, value)
+	}
+
+	for _, ref := range mapRefs.Values() {
+		values, lookupErr := secretMaps.Lookup(ref)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		for _, value := range values {
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
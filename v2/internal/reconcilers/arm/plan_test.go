@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/jsondiff"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func Test_NewResourcePlanStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 5, 12, 30, 0, 0, time.UTC)
+	differences := []jsondiff.Difference{
+		{Path: "properties.administratorLoginPassword", Type: jsondiff.ChangeTypeAbsent, Desired: "hunter2"},
+		{Path: "properties.connectionString", Type: jsondiff.ChangeTypeModify, Desired: "Password=hunter2", Actual: "Password=old"},
+		{Path: "properties.minimumTlsVersion", Type: jsondiff.ChangeTypeModify, Desired: "TLS1_2", Actual: "TLS1_0"},
+		{Path: "tags.env", Type: jsondiff.ChangeTypeAdd, Desired: "prod"},
+		{Path: "tags.team", Type: jsondiff.ChangeTypeRemove, Actual: "data"},
+	}
+
+	cases := map[string]struct {
+		action          serviceoperator.ResourcePlanAction
+		expectedChanges []serviceoperator.PlannedChange
+	}{
+		"Create records no changes": {
+			action: serviceoperator.ResourcePlanActionCreate,
+		},
+		"NoOp records no changes": {
+			action: serviceoperator.ResourcePlanActionNoOp,
+		},
+		"Modify records every change, redacting values including secrets": {
+			action: serviceoperator.ResourcePlanActionModify,
+			expectedChanges: []serviceoperator.PlannedChange{
+				{Path: "properties.administratorLoginPassword", Type: "Absent", Redacted: true},
+				{Path: "properties.connectionString", Type: "Modify", Redacted: true},
+				{Path: "properties.minimumTlsVersion", Type: "Modify", OldValue: jsonValue(`"TLS1_0"`), NewValue: jsonValue(`"TLS1_2"`)},
+				{Path: "tags.env", Type: "Add", NewValue: jsonValue(`"prod"`)},
+				{Path: "tags.team", Type: "Remove", OldValue: jsonValue(`"data"`)},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			status := newResourcePlanStatus(c.action, differences, []string{"hunter2"}, 3, now)
+			g.Expect(status.Action).To(Equal(c.action))
+			g.Expect(status.Generation).To(Equal(int64(3)))
+			g.Expect(status.PlannedTime.Time).To(Equal(now))
+			g.Expect(status.Changes).To(Equal(c.expectedChanges))
+			g.Expect(status.ChangeCount).To(Equal(len(c.expectedChanges)))
+		})
+	}
+}
+
+func jsonValue(raw string) *apiextensionsv1.JSON {
+	return &apiextensionsv1.JSON{Raw: []byte(raw)}
+}
+
+func Test_Plan_MergeMode_PlansRemovalOfPropertiesNoLongerInSpec(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	live := liveTestResourceGroup("westus")
+	live["tags"] = map[string]any{"owner": "someone-else", "env": "prod"}
+	server := &fakeResourceServer{live: live}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+	r.recordResourcePlans = true
+	genruntime.AddAnnotation(rg, annotations.MergePaths, "tags")
+
+	// We previously applied the env tag, but it's since been removed from the spec
+	g.Expect(SetLastApplied(rg, map[string][]string{"tags": {"env"}})).To(Succeed())
+
+	g.Expect(r.Plan(ctx)).To(Succeed())
+	g.Expect(server.putCount()).To(Equal(0))
+
+	condition, ok := conditions.GetCondition(rg, conditions.ConditionTypeChangesPlanned)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(condition.Message).To(ContainSubstring("tags.env (Remove)"))
+	g.Expect(condition.Message).ToNot(ContainSubstring("owner"))
+
+	plan := &serviceoperator.ResourcePlan{}
+	key := types.NamespacedName{
+		Namespace: rg.GetNamespace(),
+		Name:      "myrg.resourcegroup.resources.azure.com",
+	}
+	g.Expect(r.KubeClient.Get(ctx, key, plan)).To(Succeed())
+	g.Expect(plan.Status.Changes).To(Equal([]serviceoperator.PlannedChange{
+		{Path: "tags.env", Type: "Remove", OldValue: jsonValue(`"prod"`)},
+	}))
+}
+
+func Test_Plan_ResourcePlanCRDNotInstalled_RecordsOnlyCondition(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	server := &fakeResourceServer{live: liveTestResourceGroup("eastus")}
+	r, rg := newCreateOrUpdateTest(ctx, t, server)
+
+	g.Expect(r.Plan(ctx)).To(Succeed())
+
+	condition, ok := conditions.GetCondition(rg, conditions.ConditionTypeChangesPlanned)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(condition.Message).To(ContainSubstring("location (Modify)"))
+
+	var plans serviceoperator.ResourcePlanList
+	g.Expect(r.KubeClient.List(ctx, &plans)).To(Succeed())
+	g.Expect(plans.Items).To(BeEmpty())
+}
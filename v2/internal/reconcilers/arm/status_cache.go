@@ -0,0 +1,344 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/benbjohnson/clock"
+	"github.com/go-logr/logr"
+	"github.com/rotisserie/eris"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+)
+
+const (
+	// statusCacheBatchSize is the number of resource IDs included in each Resource Graph query, keeping queries
+	// well within the size limits of Resource Graph.
+	statusCacheBatchSize = 100
+
+	// statusCachePageSize is the number of rows requested in each page of Resource Graph results
+	statusCachePageSize int32 = 1000
+
+	// statusCacheMaxIdle is how long a resource is tracked after its status was last looked up. Resources that are
+	// no longer reconciled (e.g. because they've been deleted) stop being refreshed after this.
+	statusCacheMaxIdle = 24 * time.Hour
+)
+
+// StatusCache refreshes the status of resources in batches using Azure Resource Graph, so that individual reconciles
+// can use the results instead of issuing a GET for each resource.
+// Resources are tracked once their status has been looked up, and refreshed every interval with one query per
+// credential, subscription and resource type (per batch of resources). Resource Graph is eventually consistent, so
+// after a resource is changed by the operator its cached status is ignored until a later refresh.
+type StatusCache struct {
+	lock     sync.Mutex
+	groups   map[statusCacheGroupKey]*statusCacheGroup
+	interval time.Duration
+	log      logr.Logger
+	clock    clock.Clock
+}
+
+var _ manager.Runnable = &StatusCache{}
+
+type statusCacheGroupKey struct {
+	credential   string
+	subscription string
+	resourceType string
+}
+
+type statusCacheGroup struct {
+	client  *genericarmclient.GenericClient
+	entries map[string]*statusCacheEntry // keyed by lowercase resource ID
+}
+
+type statusCacheEntry struct {
+	id         string
+	status     json.RawMessage
+	fetched    time.Time
+	dirtyUntil time.Time
+	lastLookup time.Time
+}
+
+// NewStatusCache creates a new StatusCache refreshing resources every interval.
+func NewStatusCache(interval time.Duration, log logr.Logger) *StatusCache {
+	return NewStatusCacheWithClock(interval, log, clock.New())
+}
+
+// NewStatusCacheWithClock creates a new StatusCache using the specified clock.
+func NewStatusCacheWithClock(interval time.Duration, log logr.Logger, clk clock.Clock) *StatusCache {
+	return &StatusCache{
+		groups:   make(map[statusCacheGroupKey]*statusCacheGroup),
+		interval: interval,
+		log:      log,
+		clock:    clk,
+	}
+}
+
+// Lookup returns the cached ARM representation of the resource with the specified ID, if there's a recent enough
+// one. The resource is tracked for future refreshes whether it was found or not.
+func (c *StatusCache) Lookup(connection Connection, id string) (json.RawMessage, bool) {
+	key, ok := c.groupKey(connection, id)
+	if !ok {
+		// Not a resource we can find in Resource Graph
+		return nil, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	group, ok := c.groups[key]
+	if !ok {
+		group = &statusCacheGroup{
+			entries: make(map[string]*statusCacheEntry),
+		}
+		c.groups[key] = group
+	}
+
+	// Always use the latest client, as it may have been recreated with new credentials
+	group.client = connection.Client()
+
+	now := c.clock.Now()
+	entry, ok := group.entries[strings.ToLower(id)]
+	if !ok {
+		entry = &statusCacheEntry{
+			id: id,
+		}
+		group.entries[strings.ToLower(id)] = entry
+	}
+
+	entry.lastLookup = now
+	if !c.isFresh(entry, now) {
+		return nil, false
+	}
+
+	return entry.status, true
+}
+
+// Invalidate discards the cached status of the resource with the specified ID. It must be called whenever the
+// operator changes the resource in Azure, as Resource Graph may take some time to reflect the change.
+func (c *StatusCache) Invalidate(connection Connection, id string) {
+	key, ok := c.groupKey(connection, id)
+	if !ok {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	group, ok := c.groups[key]
+	if !ok {
+		return
+	}
+
+	entry, ok := group.entries[strings.ToLower(id)]
+	if !ok {
+		return
+	}
+
+	entry.status = nil
+	entry.dirtyUntil = c.clock.Now().Add(c.interval)
+}
+
+// Start refreshes the cache every interval until the context is cancelled.
+func (c *StatusCache) Start(ctx context.Context) error {
+	ticker := c.clock.Ticker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh queries Resource Graph for the status of every tracked resource.
+func (c *StatusCache) Refresh(ctx context.Context) {
+	type batch struct {
+		key    statusCacheGroupKey
+		client *genericarmclient.GenericClient
+		ids    []string
+	}
+
+	// Take a snapshot of what needs refreshing so we don't hold the lock while querying
+	var batches []batch
+	c.lock.Lock()
+	now := c.clock.Now()
+	for key, group := range c.groups {
+		var ids []string
+		for k, entry := range group.entries {
+			if now.Sub(entry.lastLookup) > statusCacheMaxIdle {
+				delete(group.entries, k)
+				continue
+			}
+
+			ids = append(ids, entry.id)
+		}
+
+		if len(group.entries) == 0 {
+			delete(c.groups, key)
+			continue
+		}
+
+		for start := 0; start < len(ids); start += statusCacheBatchSize {
+			end := min(start+statusCacheBatchSize, len(ids))
+			batches = append(batches, batch{key: key, client: group.client, ids: ids[start:end]})
+		}
+	}
+	c.lock.Unlock()
+
+	for _, b := range batches {
+		err := c.refreshBatch(ctx, b.key, b.client, b.ids)
+		if err != nil {
+			c.log.V(Status).Info(
+				"Failed to refresh status from Resource Graph, falling back to GET",
+				"subscription", b.key.subscription,
+				"resourceType", b.key.resourceType,
+				"error", err.Error())
+		}
+	}
+}
+
+func (c *StatusCache) refreshBatch(
+	ctx context.Context,
+	key statusCacheGroupKey,
+	client *genericarmclient.GenericClient,
+	ids []string,
+) error {
+	// Record the time before we query, so results are never considered fresher than they are
+	fetched := c.clock.Now()
+
+	request := genericarmclient.ResourceGraphRequest{
+		Subscriptions: []string{key.subscription},
+		Query:         statusCacheQuery(key.resourceType, ids),
+		Options: &genericarmclient.ResourceGraphRequestOptions{
+			Top: to.Ptr(statusCachePageSize),
+		},
+	}
+
+	results := make(map[string]json.RawMessage, len(ids))
+	for {
+		response, err := client.QueryResourceGraph(ctx, request)
+		if err != nil {
+			return eris.Wrap(err, "querying Resource Graph")
+		}
+
+		for _, row := range response.Data {
+			var resource struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(row, &resource); err != nil || resource.ID == "" {
+				continue
+			}
+
+			results[strings.ToLower(resource.ID)] = row
+		}
+
+		if response.SkipToken == "" {
+			break
+		}
+
+		request.Options.SkipToken = response.SkipToken
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	group, ok := c.groups[key]
+	if !ok {
+		return nil
+	}
+
+	for _, id := range ids {
+		entry, ok := group.entries[strings.ToLower(id)]
+		if !ok {
+			continue
+		}
+
+		if fetched.Before(entry.dirtyUntil) {
+			// The resource was changed recently, so these results may not reflect the change yet
+			continue
+		}
+
+		// If the resource wasn't found, we clear any status we have so that a GET determines what happened to it
+		entry.status = results[strings.ToLower(id)]
+		entry.fetched = fetched
+	}
+
+	c.log.V(Verbose).Info(
+		"Refreshed status from Resource Graph",
+		"subscription", key.subscription,
+		"resourceType", key.resourceType,
+		"requested", len(ids),
+		"found", len(results))
+
+	return nil
+}
+
+// isFresh returns true if the entry can be used in place of a GET. The lock must be held.
+func (c *StatusCache) isFresh(entry *statusCacheEntry, now time.Time) bool {
+	if entry.status == nil || entry.fetched.Before(entry.dirtyUntil) {
+		return false
+	}
+
+	// Allow for a refresh that's running late, but not one that has been missed entirely
+	return now.Sub(entry.fetched) <= 2*c.interval
+}
+
+// groupKey returns the key of the group the resource is refreshed with, and true, or false if the resource can't be
+// refreshed from Resource Graph.
+func (c *StatusCache) groupKey(connection Connection, id string) (statusCacheGroupKey, bool) {
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil || resourceID.SubscriptionID == "" {
+		return statusCacheGroupKey{}, false
+	}
+
+	// The resources table in Resource Graph only contains resources directly within a resource group; child and
+	// extension resources aren't included, and resource groups themselves are in a different table.
+	if resourceID.ResourceGroupName == "" ||
+		resourceID.Parent == nil ||
+		resourceID.Parent.ResourceType.String() != arm.ResourceGroupResourceType.String() {
+		return statusCacheGroupKey{}, false
+	}
+
+	return statusCacheGroupKey{
+		credential:   connection.CredentialFrom().String(),
+		subscription: strings.ToLower(resourceID.SubscriptionID),
+		resourceType: strings.ToLower(resourceID.ResourceType.String()),
+	}, true
+}
+
+// statusCacheQuery returns a Resource Graph query for the specified resources of the specified type
+func statusCacheQuery(resourceType string, ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = kqlString(id)
+	}
+
+	return fmt.Sprintf(
+		"resources | where type =~ %s | where id in~ (%s)",
+		kqlString(resourceType),
+		strings.Join(quoted, ", "))
+}
+
+// kqlString returns s as a single quoted KQL string literal
+func kqlString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
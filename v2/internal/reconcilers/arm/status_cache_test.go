@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+)
+
+const testVNetID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"
+
+// setCachedStatus simulates a refresh from Resource Graph returning the specified status
+func setCachedStatus(cache *StatusCache, id string, status string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	for _, group := range cache.groups {
+		if entry, ok := group.entries[strings.ToLower(id)]; ok && !cache.clock.Now().Before(entry.dirtyUntil) {
+			entry.status = json.RawMessage(status)
+			entry.fetched = cache.clock.Now()
+		}
+	}
+}
+
+func Test_StatusCache_Lookup_ReturnsFreshStatus(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	clk := clock.NewMock()
+	cache := NewStatusCacheWithClock(5*time.Minute, logr.Discard(), clk)
+
+	// First lookup registers interest, but there's nothing cached yet
+	_, ok := cache.Lookup(testConnection{}, testVNetID)
+	g.Expect(ok).To(BeFalse())
+
+	setCachedStatus(cache, testVNetID, `{"id": "vnet"}`)
+	status, ok := cache.Lookup(testConnection{}, testVNetID)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(string(status)).To(Equal(`{"id": "vnet"}`))
+
+	// Once refreshes stop, the status goes stale
+	clk.Add(11 * time.Minute)
+	_, ok = cache.Lookup(testConnection{}, testVNetID)
+	g.Expect(ok).To(BeFalse())
+}
+
+func Test_StatusCache_Invalidate_IgnoresRefreshesUntilResourceGraphCatchesUp(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	clk := clock.NewMock()
+	cache := NewStatusCacheWithClock(5*time.Minute, logr.Discard(), clk)
+
+	cache.Lookup(testConnection{}, testVNetID)
+	setCachedStatus(cache, testVNetID, `{"id": "vnet"}`)
+
+	cache.Invalidate(testConnection{}, testVNetID)
+	_, ok := cache.Lookup(testConnection{}, testVNetID)
+	g.Expect(ok).To(BeFalse())
+
+	// A refresh shortly after the change may not include it
+	clk.Add(time.Minute)
+	setCachedStatus(cache, testVNetID, `{"id": "vnet"}`)
+	_, ok = cache.Lookup(testConnection{}, testVNetID)
+	g.Expect(ok).To(BeFalse())
+
+	// A later refresh is trusted
+	clk.Add(5 * time.Minute)
+	setCachedStatus(cache, testVNetID, `{"id": "vnet"}`)
+	_, ok = cache.Lookup(testConnection{}, testVNetID)
+	g.Expect(ok).To(BeTrue())
+}
+
+func Test_StatusCache_Lookup_IgnoresResourcesNotInResourceGraph(t *testing.T) {
+	t.Parallel()
+
+	ids := map[string]string{
+		"Resource group": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+		"Child resource": testVNetID + "/subnets/subnet",
+		"Extension":      testVNetID + "/providers/Microsoft.Authorization/roleAssignments/ra",
+		"Tenant scoped":  "/providers/Microsoft.Management/managementGroups/mg",
+	}
+
+	for name, id := range ids {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			cache := NewStatusCacheWithClock(5*time.Minute, logr.Discard(), clock.NewMock())
+			cache.Lookup(testConnection{}, id)
+			g.Expect(cache.groups).To(BeEmpty())
+		})
+	}
+}
+
+func Test_StatusCacheQuery_QuotesValues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	query := statusCacheQuery("microsoft.network/virtualnetworks", []string{"/a", "/b'c"})
+	g.Expect(query).To(Equal(`resources | where type =~ 'microsoft.network/virtualnetworks' | where id in~ ('/a', '/b\'c')`))
+}
+
+func Test_GetCachedStatus_OnlyUsesStatusOfSameAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		cached   string
+		expected bool
+	}{
+		"Same API version":      {`{"id": "vnet", "apiVersion": "2020-11-01"}`, true},
+		"Different API version": {`{"id": "vnet", "apiVersion": "2023-05-01"}`, false},
+		"No API version":        {`{"id": "vnet"}`, false},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			cache := NewStatusCacheWithClock(5*time.Minute, logr.Discard(), clock.NewMock())
+			connection := testConnection{cache: cache}
+			r := newTestReconcilerInstance(newTestResourceGroup(), connection)
+
+			_, _ = cache.Lookup(connection, testVNetID)
+			setCachedStatus(cache, testVNetID, c.cached)
+
+			var status map[string]any
+			g.Expect(r.getCachedStatus(testVNetID, "2020-11-01", &status)).To(Equal(c.expected))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// applyTagPolicy merges the tags required by the operator's tag policy, and by the namespace of the resource, into
+// the payload sent to ARM. Resources which don't support tags are left unchanged.
+func (r *azureDeploymentReconcilerInstance) applyTagPolicy(ctx context.Context, armResource genruntime.ARMResource) error {
+	tags, ok := tagsField(armResource.Spec())
+	if !ok || r.Obj.GetNamespace() == "" {
+		return nil
+	}
+
+	namespace, err := kubeclient.GetNamespace(ctx, r.KubeClient, r.Obj.GetNamespace())
+	if err != nil {
+		return eris.Wrapf(err, "getting namespace %q", r.Obj.GetNamespace())
+	}
+
+	policyTags, err := tagsFromPolicy(r.Config.TagPolicy, namespace)
+	if err != nil {
+		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	if len(policyTags) == 0 {
+		return nil
+	}
+
+	specTags := tags.Interface().(map[string]string)
+	tags.Set(reflect.ValueOf(mergeTags(specTags, policyTags, r.Config.TagPolicy.Collision)))
+
+	return nil
+}
+
+// tagsFromPolicy returns the tags required for resources in the specified namespace. Tags from the namespace
+// annotation take precedence over those from namespace labels, which take precedence over the operator-wide tags.
+func tagsFromPolicy(policy config.TagPolicy, namespace *v1.Namespace) (map[string]string, error) {
+	result := make(map[string]string, len(policy.Tags))
+	for key, value := range policy.Tags {
+		result[key] = value
+	}
+
+	for tag, label := range policy.NamespaceLabels {
+		if value, ok := namespace.GetLabels()[label]; ok {
+			result[tag] = value
+		}
+	}
+
+	if value, ok := namespace.GetAnnotations()[annotations.Tags]; ok {
+		namespaceTags, err := config.ParseTags(value)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid %s on namespace %q", annotations.Tags, namespace.GetName())
+		}
+
+		for key, value := range namespaceTags {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// mergeTags returns the union of the tags from the spec of a resource and those required by policy. The collision
+// rule determines which value is used for tags present in both. Neither input is modified.
+func mergeTags(specTags map[string]string, policyTags map[string]string, rule config.TagCollisionRule) map[string]string {
+	result := make(map[string]string, len(specTags)+len(policyTags))
+	for key, value := range specTags {
+		result[key] = value
+	}
+
+	for key, value := range policyTags {
+		if _, ok := result[key]; ok && rule != config.TagCollisionRulePolicyWins {
+			continue
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// tagsField returns the settable Tags field of an ARM spec, if it has one. All ARM specs for resources which support
+// tags have a top level Tags property of type map[string]string.
+func tagsField(spec genruntime.ARMResourceSpec) (reflect.Value, bool) {
+	value := reflect.ValueOf(spec)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return reflect.Value{}, false
+	}
+
+	value = value.Elem()
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	field := value.FieldByName("Tags")
+	if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf(map[string]string(nil)) {
+		return reflect.Value{}, false
+	}
+
+	return field, true
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+type taggedSpec struct {
+	Name string            `json:"name,omitempty"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+func (s *taggedSpec) GetAPIVersion() string { return "2020-01-01" }
+func (s *taggedSpec) GetName() string       { return s.Name }
+func (s *taggedSpec) GetType() string       { return "Microsoft.Test/things" }
+
+type untaggedSpec struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (s *untaggedSpec) GetAPIVersion() string { return "2020-01-01" }
+func (s *untaggedSpec) GetName() string       { return s.Name }
+func (s *untaggedSpec) GetType() string       { return "Microsoft.Test/things/children" }
+
+func Test_MergeTags_AppliesCollisionRule(t *testing.T) {
+	t.Parallel()
+
+	specTags := map[string]string{"owner": "spec", "app": "web"}
+	policyTags := map[string]string{"owner": "policy", "cost-center": "1234"}
+
+	cases := map[string]struct {
+		rule     config.TagCollisionRule
+		expected map[string]string
+	}{
+		"Spec wins": {
+			rule:     config.TagCollisionRuleSpecWins,
+			expected: map[string]string{"owner": "spec", "app": "web", "cost-center": "1234"},
+		},
+		"Policy wins": {
+			rule:     config.TagCollisionRulePolicyWins,
+			expected: map[string]string{"owner": "policy", "app": "web", "cost-center": "1234"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(mergeTags(specTags, policyTags, c.rule)).To(Equal(c.expected))
+			g.Expect(specTags).To(HaveKeyWithValue("owner", "spec"))
+		})
+	}
+}
+
+func Test_TagsFromPolicy_NamespaceTakesPrecedence(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	policy := config.TagPolicy{
+		Tags: map[string]string{"managed-by": "aso", "owner": "platform"},
+		NamespaceLabels: map[string]string{
+			"cost-center": "finops.contoso.com/cost-center",
+			"owner":       "finops.contoso.com/owner",
+			"missing":     "finops.contoso.com/missing",
+		},
+	}
+
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			Labels: map[string]string{
+				"finops.contoso.com/cost-center": "1234",
+				"finops.contoso.com/owner":       "team-a",
+			},
+			Annotations: map[string]string{
+				annotations.Tags: "cost-center=5678;environment=prod",
+			},
+		},
+	}
+
+	tags, err := tagsFromPolicy(policy, namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tags).To(Equal(map[string]string{
+		"managed-by":  "aso",
+		"owner":       "team-a",
+		"cost-center": "5678",
+		"environment": "prod",
+	}))
+}
+
+func Test_TagsFromPolicy_InvalidAnnotation_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{annotations.Tags: "cost-center"},
+		},
+	}
+
+	_, err := tagsFromPolicy(config.TagPolicy{}, namespace)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_TagsField_OnlyFoundOnSpecsWithTags(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := &taggedSpec{Name: "thing"}
+	field, ok := tagsField(spec)
+	g.Expect(ok).To(BeTrue())
+
+	field.Set(reflect.ValueOf(map[string]string{"owner": "team-a"}))
+	g.Expect(spec.Tags).To(HaveKeyWithValue("owner", "team-a"))
+
+	_, ok = tagsField(&untaggedSpec{Name: "child"})
+	g.Expect(ok).To(BeFalse())
+}
@@ -110,6 +110,9 @@ func (r *ARMOwnedResourceReconcilerCommon) NeedsToWaitForOwner(ctx context.Conte
 	return false, nil
 }
 
+// ApplyOwnership sets an owner reference on obj to its owner, so that the resource is garbage collected when its owner
+// is deleted. Kubernetes doesn't allow owner references across namespaces, so no owner reference is set for an owner in
+// another namespace (allowed by a ReferenceGrant), and deleting such an owner doesn't cascade to the resource.
 func (r *ARMOwnedResourceReconcilerCommon) ApplyOwnership(ctx context.Context, log logr.Logger, obj genruntime.ARMOwnedMetaObject) error {
 	ownerDetails, err := r.ResourceResolver.ResolveOwner(ctx, obj)
 	if err != nil {
@@ -121,6 +124,14 @@ func (r *ARMOwnedResourceReconcilerCommon) ApplyOwnership(ctx context.Context, l
 		return nil
 	}
 
+	if ownerDetails.Owner.GetNamespace() != obj.GetNamespace() {
+		// Kubernetes doesn't allow owner references across namespaces
+		log.V(Verbose).Info(
+			"Owner is in another namespace, not setting owner reference",
+			"ownerNamespace", ownerDetails.Owner.GetNamespace())
+		return nil
+	}
+
 	ownerRef := ownerutil.MakeOwnerReference(ownerDetails.Owner)
 
 	obj.SetOwnerReferences(ownerutil.EnsureOwnerRef(obj.GetOwnerReferences(), ownerRef))
@@ -178,6 +189,12 @@ func ClassifyResolverError(err error) error {
 		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonConfigMapNotFound)
 	}
 
+	// If it's a reference to another namespace which hasn't been allowed, say so
+	var notGrantedErr *core.ReferenceNotGranted
+	if eris.As(err, &notGrantedErr) {
+		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonReferenceNotGranted)
+	}
+
 	// If it's subscription mismatch, classify that
 	var subscriptionMismatchErr *core.SubscriptionMismatch
 	if eris.As(err, &subscriptionMismatchErr) {
@@ -0,0 +1,35 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// GetCreationMode returns the creation mode of the provided object, as specified by the creation-mode annotation.
+// Unlike the reconcile-policy, an unknown creation mode is an error rather than falling back to the default, as
+// the default is the less safe of the available modes.
+func GetCreationMode(obj genruntime.MetaObject) (annotations.CreationModeValue, error) {
+	mode := obj.GetAnnotations()[annotations.CreationMode]
+	switch mode {
+	case "":
+		return annotations.CreationModeAdoptOrCreate, nil
+	case string(annotations.CreationModeAdoptOrCreate):
+		return annotations.CreationModeAdoptOrCreate, nil
+	case string(annotations.CreationModeCreateOnly):
+		return annotations.CreationModeCreateOnly, nil
+	default:
+		return "", eris.Errorf(
+			"%q is not a known %s, must be one of (%s, %s)",
+			mode,
+			annotations.CreationMode,
+			annotations.CreationModeAdoptOrCreate,
+			annotations.CreationModeCreateOnly)
+	}
+}
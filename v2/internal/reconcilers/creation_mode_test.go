@@ -0,0 +1,59 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+func TestGetCreationMode(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		annotation string
+		expected   annotations.CreationModeValue
+		expectErr  bool
+	}{
+		{"Unset defaults to adopt-or-create", "", annotations.CreationModeAdoptOrCreate, false},
+		{"Adopt-or-create", "adopt-or-create", annotations.CreationModeAdoptOrCreate, false},
+		{"Create-only", "create-only", annotations.CreationModeCreateOnly, false},
+		{"Unknown value is an error", "create-onyl", "", true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			rg := &resources.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "myrg",
+				},
+			}
+			if c.annotation != "" {
+				rg.SetAnnotations(map[string]string{annotations.CreationMode: c.annotation})
+			}
+
+			mode, err := GetCreationMode(rg)
+			if c.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(mode).To(Equal(c.expected))
+		})
+	}
+}
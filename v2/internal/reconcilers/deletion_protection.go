@@ -0,0 +1,49 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"strconv"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// IsDeletionProtected returns true if the deletion-protection annotation protects the provided object from deletion.
+// Values that can't be parsed are treated as enabling protection, as that's the safer interpretation.
+func IsDeletionProtected(obj genruntime.MetaObject) bool {
+	value, ok := obj.GetAnnotations()[annotations.DeletionProtection]
+	if !ok {
+		return false
+	}
+
+	protected, err := strconv.ParseBool(value)
+	return err != nil || protected
+}
+
+// CheckDeletionProtection returns a ReadyConditionImpactingError if the provided object is protected from deletion,
+// and that deletion hasn't been confirmed.
+func CheckDeletionProtection(obj genruntime.MetaObject) error {
+	if !IsDeletionProtected(obj) {
+		return nil
+	}
+
+	if obj.GetAnnotations()[annotations.ConfirmDeletion] == obj.GetName() {
+		return nil
+	}
+
+	err := eris.Errorf(
+		"deletion is blocked by %s. To delete the resource from Azure, set %s to %q. To keep the resource in Azure, set %s to %s",
+		annotations.DeletionProtection,
+		annotations.ConfirmDeletion,
+		obj.GetName(),
+		annotations.ReconcilePolicy,
+		annotations.ReconcilePolicyDetachOnDelete)
+	return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonDeletionProtected)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		blocked     bool
+	}{
+		{"No annotations", nil, false},
+		{"Protection disabled", map[string]string{annotations.DeletionProtection: "false"}, false},
+		{"Protection enabled", map[string]string{annotations.DeletionProtection: "true"}, true},
+		{"Unparseable protection", map[string]string{annotations.DeletionProtection: "yes please"}, true},
+		{
+			"Deletion confirmed",
+			map[string]string{annotations.DeletionProtection: "true", annotations.ConfirmDeletion: "myrg"},
+			false,
+		},
+		{
+			"Deletion confirmed for a different resource",
+			map[string]string{annotations.DeletionProtection: "true", annotations.ConfirmDeletion: "otherrg"},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			rg := &resources.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "myrg",
+					Annotations: c.annotations,
+				},
+			}
+
+			err := CheckDeletionProtection(rg)
+			if !c.blocked {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+
+			readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(readyErr.Reason).To(Equal(conditions.ReasonDeletionProtected.Name))
+		})
+	}
+}
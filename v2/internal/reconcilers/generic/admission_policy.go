@@ -0,0 +1,154 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
+)
+
+// admissionPolicyValidator wraps the validator of a resource, checking that resources being created, or having their
+// spec changed, satisfy the AdmissionPolicies of the cluster.
+type admissionPolicyValidator struct {
+	admission.CustomValidator
+	kubeClient client.Reader
+	scheme     *runtime.Scheme
+}
+
+var _ admission.CustomValidator = admissionPolicyValidator{}
+
+func (v admissionPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	warnings, err := v.CustomValidator.ValidateCreate(ctx, obj)
+	if err != nil {
+		return warnings, err
+	}
+
+	policyWarnings, err := v.validatePolicies(ctx, obj)
+	return append(warnings, policyWarnings...), err
+}
+
+func (v admissionPolicyValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	warnings, err := v.CustomValidator.ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		return warnings, err
+	}
+
+	// The operator updates annotations and finalizers of resources it manages; only changes to the spec are checked,
+	// so that resources admitted before a policy was created can still be reconciled and deleted.
+	if !specChanged(oldObj, newObj) {
+		return warnings, nil
+	}
+
+	policyWarnings, err := v.validatePolicies(ctx, newObj)
+	return append(warnings, policyWarnings...), err
+}
+
+func (v admissionPolicyValidator) validatePolicies(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, eris.Errorf("expected metav1.Object, but got %T", obj)
+	}
+
+	if !metaObj.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+
+	evaluator := asocel.Evaluator()
+	if evaluator == nil {
+		return nil, nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, v.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies serviceoperator.AdmissionPolicyList
+	err = v.kubeClient.List(ctx, &policies)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// AdmissionPolicy CRD is not installed
+			return nil, nil
+		}
+
+		return nil, eris.Wrap(err, "listing AdmissionPolicies")
+	}
+
+	sort.Slice(policies.Items, func(i, j int) bool {
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+
+	var warnings admission.Warnings
+	var violations []string
+	for _, policy := range policies.Items {
+		if !policy.Spec.Match.Matches(gvk.GroupKind(), metaObj.GetNamespace()) {
+			continue
+		}
+
+		for _, message := range evaluatePolicy(evaluator, &policy.Spec, obj) {
+			if policy.Spec.EffectiveMode() == serviceoperator.AdmissionPolicyModeAudit {
+				warnings = append(
+					warnings,
+					fmt.Sprintf("resource violates admission policy %q, but is admitted as the policy is in Audit mode: %s", policy.Name, message))
+			} else {
+				violations = append(violations, fmt.Sprintf("admission policy %q denied the request: %s", policy.Name, message))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return warnings, eris.New(strings.Join(violations, "; "))
+	}
+
+	return warnings, nil
+}
+
+// evaluatePolicy returns the messages of the validations of the policy which the resource violates.
+func evaluatePolicy(evaluator asocel.ExpressionEvaluator, spec *serviceoperator.AdmissionPolicySpec, obj runtime.Object) []string {
+	var result []string
+	for _, validation := range spec.Validations {
+		ok, err := evaluator.CompileAndRunValidation(validation.Expression, obj)
+		if err != nil {
+			if spec.EffectiveFailurePolicy() == serviceoperator.AdmissionPolicyFailurePolicyIgnore {
+				continue
+			}
+
+			result = append(result, fmt.Sprintf("%s (expression %q could not be evaluated: %s)", validation.Message, validation.Expression, err))
+			continue
+		}
+
+		if !ok {
+			result = append(result, validation.Message)
+		}
+	}
+
+	return result
+}
+
+// specChanged returns true if the spec of newObj differs from that of oldObj.
+func specChanged(oldObj runtime.Object, newObj runtime.Object) bool {
+	oldSpec := reflect.ValueOf(oldObj).Elem().FieldByName("Spec")
+	newSpec := reflect.ValueOf(newObj).Elem().FieldByName("Spec")
+	if !oldSpec.IsValid() || !newSpec.IsValid() {
+		return true
+	}
+
+	return !reflect.DeepEqual(oldSpec.Interface(), newSpec.Interface())
+}
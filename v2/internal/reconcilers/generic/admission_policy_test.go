@@ -0,0 +1,238 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+)
+
+var registerEvaluator sync.Once
+
+// fakeValidator is a validator which admits everything
+type fakeValidator struct{}
+
+var _ admission.CustomValidator = fakeValidator{}
+
+func (fakeValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (fakeValidator) ValidateUpdate(_ context.Context, _ runtime.Object, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (fakeValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// newAdmissionPolicyValidator returns a validator checking resources against the provided policies
+func newAdmissionPolicyValidator(t *testing.T, policies ...*serviceoperator.AdmissionPolicy) admissionPolicyValidator {
+	g := NewGomegaWithT(t)
+
+	// The webhooks use the default evaluator
+	registerEvaluator.Do(func() {
+		evaluator, err := asocel.NewExpressionEvaluator()
+		g.Expect(err).ToNot(HaveOccurred())
+		asocel.RegisterEvaluator(evaluator)
+	})
+
+	scheme := runtime.NewScheme()
+	g.Expect(resources.AddToScheme(scheme)).To(Succeed())
+	g.Expect(serviceoperator.AddToScheme(scheme)).To(Succeed())
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, policy := range policies {
+		builder = builder.WithObjects(policy)
+	}
+
+	return admissionPolicyValidator{
+		CustomValidator: fakeValidator{},
+		kubeClient:      builder.Build(),
+		scheme:          scheme,
+	}
+}
+
+func newAdmissionPolicy(name string, mode serviceoperator.AdmissionPolicyMode, expression string) *serviceoperator.AdmissionPolicy {
+	return &serviceoperator.AdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: serviceoperator.AdmissionPolicySpec{
+			Mode: mode,
+			Validations: []serviceoperator.AdmissionValidation{
+				{
+					Expression: expression,
+					Message:    "resource groups must be in westus",
+				},
+			},
+		},
+	}
+}
+
+func newAdmissionTestResourceGroup(location string) *resources.ResourceGroup {
+	return &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myrg",
+			Namespace: "team-a",
+		},
+		Spec: resources.ResourceGroup_Spec{
+			Location: to.Ptr(location),
+		},
+	}
+}
+
+const westUSExpression = `self.spec.location == "westus"`
+
+func Test_AdmissionPolicyValidator_ValidateCreate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		policy          func() *serviceoperator.AdmissionPolicy
+		location        string
+		expectDenied    bool
+		expectWarning   bool
+		expectedMessage string
+	}{
+		"Satisfied policy admits": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				return newAdmissionPolicy("westus-only", "", westUSExpression)
+			},
+			location: "westus",
+		},
+		"Deny policy denies violation": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				return newAdmissionPolicy("westus-only", serviceoperator.AdmissionPolicyModeDeny, westUSExpression)
+			},
+			location:        "eastus",
+			expectDenied:    true,
+			expectedMessage: `admission policy "westus-only" denied the request: resource groups must be in westus`,
+		},
+		"Mode defaults to Deny": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				return newAdmissionPolicy("westus-only", "", westUSExpression)
+			},
+			location:     "eastus",
+			expectDenied: true,
+		},
+		"Audit policy warns of violation": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				return newAdmissionPolicy("westus-only", serviceoperator.AdmissionPolicyModeAudit, westUSExpression)
+			},
+			location:        "eastus",
+			expectWarning:   true,
+			expectedMessage: `resource violates admission policy "westus-only", but is admitted as the policy is in Audit mode: resource groups must be in westus`,
+		},
+		"Failing expression denies by default": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				return newAdmissionPolicy("broken", "", `self.spec.doesNotExist == "x"`)
+			},
+			location:     "westus",
+			expectDenied: true,
+		},
+		"Failing expression ignored with Ignore failure policy": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				policy := newAdmissionPolicy("broken", "", `self.spec.doesNotExist == "x"`)
+				policy.Spec.FailurePolicy = serviceoperator.AdmissionPolicyFailurePolicyIgnore
+				return policy
+			},
+			location: "westus",
+		},
+		"Policy for other namespace doesn't apply": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				policy := newAdmissionPolicy("westus-only", "", westUSExpression)
+				policy.Spec.Match.Namespaces = []string{"team-b"}
+				return policy
+			},
+			location: "eastus",
+		},
+		"Policy for namespace applies": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				policy := newAdmissionPolicy("westus-only", "", westUSExpression)
+				policy.Spec.Match.Namespaces = []string{"team-a"}
+				return policy
+			},
+			location:     "eastus",
+			expectDenied: true,
+		},
+		"Policy for other kind doesn't apply": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				policy := newAdmissionPolicy("westus-only", "", westUSExpression)
+				policy.Spec.Match.Kinds = []string{"StorageAccount"}
+				return policy
+			},
+			location: "eastus",
+		},
+		"Policy for kind applies, ignoring case": {
+			policy: func() *serviceoperator.AdmissionPolicy {
+				policy := newAdmissionPolicy("westus-only", "", westUSExpression)
+				policy.Spec.Match.Groups = []string{"Resources.Azure.com"}
+				policy.Spec.Match.Kinds = []string{"resourcegroup"}
+				return policy
+			},
+			location:     "eastus",
+			expectDenied: true,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			validator := newAdmissionPolicyValidator(t, c.policy())
+			warnings, err := validator.ValidateCreate(context.Background(), newAdmissionTestResourceGroup(c.location))
+
+			if c.expectDenied {
+				g.Expect(err).To(HaveOccurred())
+				if c.expectedMessage != "" {
+					g.Expect(err.Error()).To(Equal(c.expectedMessage))
+				}
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			if c.expectWarning {
+				g.Expect(warnings).To(ConsistOf(c.expectedMessage))
+				g.Expect(warnings[0]).ToNot(ContainSubstring("denied"))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func Test_AdmissionPolicyValidator_ValidateUpdate_OnlyChecksSpecChanges(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	validator := newAdmissionPolicyValidator(t, newAdmissionPolicy("westus-only", "", westUSExpression))
+
+	// Resources admitted before the policy was created can still have their annotations changed
+	oldObj := newAdmissionTestResourceGroup("eastus")
+	newObj := newAdmissionTestResourceGroup("eastus")
+	newObj.Annotations = map[string]string{"example.com/owner": "team-a"}
+
+	_, err := validator.ValidateUpdate(context.Background(), oldObj, newObj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// But changes to the spec must satisfy the policy
+	newObj.Spec.Tags = map[string]string{"env": "test"}
+	_, err = validator.ValidateUpdate(context.Background(), oldObj, newObj)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -15,8 +15,12 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -25,8 +29,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/Azure/azure-service-operator/v2/internal/audit"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
@@ -53,6 +59,9 @@ type GenericReconciler struct {
 	PositiveConditions        *conditions.PositiveConditionBuilder
 	RequeueIntervalCalculator interval.Calculator
 
+	// RequeuePriorities, if set, records the priority with which each request should be requeued
+	RequeuePriorities *RequeuePriorities
+
 	PanicHandler func()
 }
 
@@ -60,6 +69,30 @@ var _ reconcile.Reconciler = &GenericReconciler{} // GenericReconciler is a reco
 
 // Reconcile will take state in K8s and apply it to Azure
 func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		fmt.Sprintf("Reconcile %s", gr.GVK.Kind),
+		trace.WithAttributes(
+			tracing.KindKey.String(gr.GVK.GroupKind().String()),
+			tracing.NamespaceKey.String(req.Namespace),
+			tracing.NameKey.String(req.Name)))
+
+	result, err := gr.reconcile(ctx, req)
+	span.SetAttributes(attribute.String("aso.requeue_after", result.RequeueAfter.String()))
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (gr *GenericReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Share the lookup of the namespace between the features taking their defaults from it
+	ctx = kubeclient.WithNamespaces(ctx)
+
+	if gr.RequeuePriorities != nil {
+		// Requeues default to the priority the request was dequeued with, unless we decide otherwise below
+		gr.RequeuePriorities.Forget(req)
+	}
+
 	metaObj, err := gr.getObjectToReconcile(ctx, req)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -75,6 +108,18 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	metaObj = metaObj.DeepCopyObject().(genruntime.MetaObject)
 
 	log := gr.LoggerFactory(metaObj).WithValues("name", req.Name, "namespace", req.Namespace)
+	trace.SpanFromContext(ctx).SetAttributes(tracing.GenerationKey.Int64(metaObj.GetGeneration()))
+
+	// Attribute any changes made in Azure to this resource, and to whoever last changed it
+	ctx = audit.WithResource(ctx, audit.Resource{
+		Group:      gr.GVK.Group,
+		Kind:       gr.GVK.Kind,
+		Namespace:  metaObj.GetNamespace(),
+		Name:       metaObj.GetName(),
+		UID:        metaObj.GetUID(),
+		Generation: metaObj.GetGeneration(),
+		Actor:      metaObj.GetAnnotations()[annotations.LastModifiedBy],
+	})
 
 	defer gr.PanicHandler()
 	reconcilers.LogObj(log, Verbose, "Reconcile invoked", metaObj)
@@ -100,8 +145,14 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	if err != nil {
+		// Most errors are turned into a requeue below, so record them on the span here
+		span := trace.SpanFromContext(ctx)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
 		err = gr.writeReadyConditionErrorOrDefault(ctx, log, metaObj, err)
 		result, err = gr.RequeueIntervalCalculator.NextInterval(req, result, err)
+		gr.setRequeuePriority(req, metaObj)
 		log.V(Verbose).Info("Encountered error, re-queuing...", "result", result)
 		return result, err
 	}
@@ -122,7 +173,13 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	// https://github.com/Azure/azure-service-operator/issues/2556).
 	// In order to cater to the above scenarios we calculate some intervals ourselves using this IntervalCalculator and pass others
 	// up to the controller-runtime RateLimiter.
-	result, err = gr.RequeueIntervalCalculator.NextInterval(req, result, nil)
+	// Resources (or their namespaces) may override how often they're re-synced
+	syncPeriod, hasSyncPeriod := reconcilers.GetSyncPeriod(ctx, gr.KubeClient, log, metaObj)
+	if hasSyncPeriod {
+		result, err = gr.RequeueIntervalCalculator.NextIntervalWithSyncPeriod(req, result, nil, syncPeriod)
+	} else {
+		result, err = gr.RequeueIntervalCalculator.NextInterval(req, result, nil)
+	}
 	if err != nil {
 		// This isn't really going to happen but just do it defensively anyway
 		return result, err
@@ -149,10 +206,21 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, kubeclient.IgnoreNotFound(err)
 	}
 
+	gr.setRequeuePriority(req, metaObj)
 	log.V(Verbose).Info("Done with reconcile", "result", result)
 	return result, nil
 }
 
+// setRequeuePriority records the priority with which the request should be requeued (if it is), so that polling and
+// resyncs don't compete with new changes.
+func (gr *GenericReconciler) setRequeuePriority(req ctrl.Request, metaObj genruntime.MetaObject) {
+	if gr.RequeuePriorities == nil {
+		return
+	}
+
+	gr.RequeuePriorities.Set(req, requeuePriorityFor(metaObj))
+}
+
 // wasFinalizerRemoved returns true if the finalizer was removed from original.
 func wasFinalizerRemoved(original genruntime.MetaObject, updated genruntime.MetaObject) bool {
 	originalHasFinalizer := controllerutil.ContainsFinalizer(original, genruntime.ReconcilerFinalizer)
@@ -236,6 +304,14 @@ func (gr *GenericReconciler) createOrUpdate(ctx context.Context, log logr.Logger
 
 	// Check the reconcile-policy to ensure we're allowed to issue a CreateOrUpdate
 	reconcilePolicy := reconcilers.GetReconcilePolicy(metaObj, log, gr.Config.DefaultReconcilePolicy)
+	if reconcilePolicy == annotations.ReconcilePolicyPlan {
+		return ctrl.Result{}, gr.handlePlanReconcile(ctx, log, metaObj)
+	}
+
+	if reconcilePolicy == annotations.ReconcilePolicyObserve {
+		return ctrl.Result{}, gr.handleObserveReconcile(ctx, log, metaObj)
+	}
+
 	if !reconcilePolicy.AllowsModify() {
 		return ctrl.Result{}, gr.handleSkipReconcile(ctx, log, metaObj)
 	}
@@ -250,6 +326,14 @@ func (gr *GenericReconciler) delete(ctx context.Context, log logr.Logger, metaOb
 	reconcilePolicy := reconcilers.GetReconcilePolicy(metaObj, log, gr.Config.DefaultReconcilePolicy)
 	if !reconcilePolicy.AllowsDelete() {
 		log.V(Info).Info("Bypassing delete of resource due to policy", "policy", reconcilePolicy)
+		if reconcilePolicy == annotations.ReconcilePolicyPlan {
+			// Easily mistaken for a planned delete, so make sure it's visible
+			gr.Recorder.Event(
+				metaObj,
+				corev1.EventTypeWarning,
+				"DeleteNotPlanned",
+				"Resource was deleted while the reconcile-policy is plan, so it has been detached and not deleted in Azure")
+		}
 		controllerutil.RemoveFinalizer(metaObj, genruntime.ReconcilerFinalizer)
 		log.V(Status).Info("Deleted resource")
 		return ctrl.Result{}, nil
@@ -262,6 +346,13 @@ func (gr *GenericReconciler) delete(ctx context.Context, log logr.Logger, metaOb
 		return ctrl.Result{}, nil
 	}
 
+	// Check for deletion protection, which holds the finalizer until deletion is explicitly confirmed
+	err := reconcilers.CheckDeletionProtection(metaObj)
+	if err != nil {
+		log.V(Status).Info("Delete of resource blocked by deletion protection")
+		return ctrl.Result{}, err
+	}
+
 	result, err := gr.Reconciler.Delete(ctx, log, gr.Recorder, metaObj)
 	// If the Delete call had no error and isn't asking us to requeue, then it succeeded and we can remove
 	// the finalizer
@@ -365,6 +456,56 @@ func (gr *GenericReconciler) handleSkipReconcile(ctx context.Context, log logr.L
 	return nil
 }
 
+func (gr *GenericReconciler) handleObserveReconcile(ctx context.Context, log logr.Logger, obj genruntime.MetaObject) error {
+	observer, ok := gr.Reconciler.(genruntime.Observer)
+	if !ok {
+		// Without drift reporting, observing a resource is the same as skipping it
+		return gr.handleSkipReconcile(ctx, log, obj)
+	}
+
+	log.V(Status).Info(
+		"Observing resource without creating/updating due to policy",
+		annotations.ReconcilePolicy, annotations.ReconcilePolicyObserve)
+
+	err := observer.Observe(ctx, log, gr.Recorder, obj)
+	if err != nil {
+		return err
+	}
+	conditions.SetCondition(obj, gr.PositiveConditions.Ready.Succeeded(obj.GetGeneration()))
+
+	return nil
+}
+
+func (gr *GenericReconciler) handlePlanReconcile(ctx context.Context, log logr.Logger, obj genruntime.MetaObject) error {
+	planner, ok := gr.Reconciler.(genruntime.Planner)
+	if !ok {
+		err := eris.Errorf(
+			"resources of kind %s do not support the %q reconcile policy",
+			gr.GVK.Kind,
+			annotations.ReconcilePolicyPlan)
+		return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonFailed)
+	}
+
+	log.V(Status).Info(
+		"Planning creation/update of resource due to policy",
+		annotations.ReconcilePolicy, annotations.ReconcilePolicyPlan)
+
+	err := planner.Plan(ctx, log, gr.Recorder, obj)
+	if err != nil {
+		return err
+	}
+
+	// Until the planned changes are made, the resource isn't ready for anything that depends on it
+	if planned, ok := conditions.GetCondition(obj, conditions.ConditionTypeChangesPlanned); ok && planned.Status == metav1.ConditionTrue {
+		conditions.SetCondition(obj, gr.PositiveConditions.Ready.ChangesPending(obj.GetGeneration()))
+		return nil
+	}
+
+	conditions.SetCondition(obj, gr.PositiveConditions.Ready.Succeeded(obj.GetGeneration()))
+
+	return nil
+}
+
 func (gr *GenericReconciler) writeReadyConditionErrorOrDefault(ctx context.Context, log logr.Logger, metaObj genruntime.MetaObject, err error) error {
 	// If the error in question is NotFound or Conflict from KubeClient just return it right away as there is no reason to wrap it
 	if kubeclient.IsNotFoundOrConflict(err) {
@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+// lastModifiedByDefaulter wraps the defaulter of a resource, recording the user who last created or changed the spec
+// of the resource in the LastModifiedBy annotation.
+type lastModifiedByDefaulter struct {
+	admission.CustomDefaulter
+}
+
+var _ admission.CustomDefaulter = lastModifiedByDefaulter{}
+
+func (d lastModifiedByDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	err := d.CustomDefaulter.Default(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		// Not called from an admission webhook, nothing to record
+		return nil //nolint:nilerr
+	}
+
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return eris.Errorf("expected metav1.Object, but got %T", obj)
+	}
+
+	lastModifiedBy, err := determineLastModifiedBy(req)
+	if err != nil {
+		return err
+	}
+
+	objAnnotations := metaObj.GetAnnotations()
+	if lastModifiedBy == "" {
+		delete(objAnnotations, annotations.LastModifiedBy)
+	} else {
+		if objAnnotations == nil {
+			objAnnotations = make(map[string]string)
+		}
+		objAnnotations[annotations.LastModifiedBy] = lastModifiedBy
+	}
+	metaObj.SetAnnotations(objAnnotations)
+
+	return nil
+}
+
+// determineLastModifiedBy returns the user who should be recorded as having last modified the resource. Users can't
+// set the annotation themselves; if the spec is unchanged, the previous value is retained.
+func determineLastModifiedBy(req admission.Request) (string, error) {
+	if req.Operation == admissionv1.Create {
+		return req.UserInfo.Username, nil
+	}
+
+	type specAndAnnotations struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations,omitempty"`
+		} `json:"metadata"`
+		Spec any `json:"spec,omitempty"`
+	}
+
+	var newObj specAndAnnotations
+	if err := json.Unmarshal(req.Object.Raw, &newObj); err != nil {
+		return "", eris.Wrap(err, "parsing object")
+	}
+
+	var oldObj specAndAnnotations
+	if err := json.Unmarshal(req.OldObject.Raw, &oldObj); err != nil {
+		return "", eris.Wrap(err, "parsing old object")
+	}
+
+	if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+		return req.UserInfo.Username, nil
+	}
+
+	return oldObj.Metadata.Annotations[annotations.LastModifiedBy], nil
+}
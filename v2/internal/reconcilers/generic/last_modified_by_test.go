@@ -0,0 +1,205 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/rotisserie/eris"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+// fakeDefaulter is a defaulter returning the provided error
+type fakeDefaulter struct {
+	err error
+}
+
+var _ admission.CustomDefaulter = fakeDefaulter{}
+
+func (d fakeDefaulter) Default(_ context.Context, _ runtime.Object) error {
+	return d.err
+}
+
+func newLastModifiedByResourceGroup(location string, lastModifiedBy string) *resources.ResourceGroup {
+	rg := &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myrg",
+			Namespace: "default",
+		},
+		Spec: resources.ResourceGroup_Spec{
+			Location: to.Ptr(location),
+		},
+	}
+
+	if lastModifiedBy != "" {
+		rg.Annotations = map[string]string{annotations.LastModifiedBy: lastModifiedBy}
+	}
+
+	return rg
+}
+
+func newLastModifiedByRequest(
+	t *testing.T,
+	operation admissionv1.Operation,
+	user string,
+	obj *resources.ResourceGroup,
+	oldObj *resources.ResourceGroup,
+) admission.Request {
+	g := NewGomegaWithT(t)
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			UserInfo:  authenticationv1.UserInfo{Username: user},
+		},
+	}
+
+	raw, err := json.Marshal(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	req.Object = runtime.RawExtension{Raw: raw}
+
+	if oldObj != nil {
+		raw, err = json.Marshal(oldObj)
+		g.Expect(err).ToNot(HaveOccurred())
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+
+	return req
+}
+
+func Test_DetermineLastModifiedBy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		operation admissionv1.Operation
+		obj       *resources.ResourceGroup
+		oldObj    *resources.ResourceGroup
+		expected  string
+	}{
+		"Create records the user": {
+			operation: admissionv1.Create,
+			obj:       newLastModifiedByResourceGroup("westus", ""),
+			expected:  "alice",
+		},
+		"Create ignores the annotation set by the user": {
+			operation: admissionv1.Create,
+			obj:       newLastModifiedByResourceGroup("westus", "mallory"),
+			expected:  "alice",
+		},
+		"Spec change records the user": {
+			operation: admissionv1.Update,
+			obj:       newLastModifiedByResourceGroup("eastus", "bob"),
+			oldObj:    newLastModifiedByResourceGroup("westus", "bob"),
+			expected:  "alice",
+		},
+		"No spec change keeps the previous user": {
+			operation: admissionv1.Update,
+			obj:       newLastModifiedByResourceGroup("westus", "bob"),
+			oldObj:    newLastModifiedByResourceGroup("westus", "bob"),
+			expected:  "bob",
+		},
+		"No spec change ignores the annotation set by the user": {
+			operation: admissionv1.Update,
+			obj:       newLastModifiedByResourceGroup("westus", "mallory"),
+			oldObj:    newLastModifiedByResourceGroup("westus", "bob"),
+			expected:  "bob",
+		},
+		"No spec change and no previous user records nobody": {
+			operation: admissionv1.Update,
+			obj:       newLastModifiedByResourceGroup("westus", "mallory"),
+			oldObj:    newLastModifiedByResourceGroup("westus", ""),
+			expected:  "",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			req := newLastModifiedByRequest(t, c.operation, "alice", c.obj, c.oldObj)
+			lastModifiedBy, err := determineLastModifiedBy(req)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(lastModifiedBy).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_DetermineLastModifiedBy_InvalidOldObject_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	req := newLastModifiedByRequest(t, admissionv1.Update, "alice", newLastModifiedByResourceGroup("westus", ""), nil)
+	req.OldObject = runtime.RawExtension{Raw: []byte("{")}
+
+	_, err := determineLastModifiedBy(req)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_LastModifiedByDefaulter_InWebhook_SetsAnnotation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newLastModifiedByResourceGroup("eastus", "mallory")
+	oldObj := newLastModifiedByResourceGroup("westus", "")
+	req := newLastModifiedByRequest(t, admissionv1.Update, "alice", obj, oldObj)
+	ctx := admission.NewContextWithRequest(context.Background(), req)
+
+	defaulter := lastModifiedByDefaulter{CustomDefaulter: fakeDefaulter{}}
+	g.Expect(defaulter.Default(ctx, obj)).To(Succeed())
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(annotations.LastModifiedBy, "alice"))
+}
+
+func Test_LastModifiedByDefaulter_NobodyToRecord_RemovesAnnotation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newLastModifiedByResourceGroup("westus", "mallory")
+	oldObj := newLastModifiedByResourceGroup("westus", "")
+	req := newLastModifiedByRequest(t, admissionv1.Update, "alice", obj, oldObj)
+	ctx := admission.NewContextWithRequest(context.Background(), req)
+
+	defaulter := lastModifiedByDefaulter{CustomDefaulter: fakeDefaulter{}}
+	g.Expect(defaulter.Default(ctx, obj)).To(Succeed())
+	g.Expect(obj.GetAnnotations()).ToNot(HaveKey(annotations.LastModifiedBy))
+}
+
+func Test_LastModifiedByDefaulter_OutsideWebhook_LeavesAnnotations(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newLastModifiedByResourceGroup("westus", "bob")
+
+	defaulter := lastModifiedByDefaulter{CustomDefaulter: fakeDefaulter{}}
+	g.Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(annotations.LastModifiedBy, "bob"))
+}
+
+func Test_LastModifiedByDefaulter_DefaulterFails_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newLastModifiedByResourceGroup("westus", "")
+	req := newLastModifiedByRequest(t, admissionv1.Create, "alice", obj, nil)
+	ctx := admission.NewContextWithRequest(context.Background(), req)
+
+	defaulter := lastModifiedByDefaulter{CustomDefaulter: fakeDefaulter{err: eris.New("boom")}}
+	g.Expect(defaulter.Default(ctx, obj)).To(MatchError(ContainSubstring("boom")))
+	g.Expect(obj.GetAnnotations()).ToNot(HaveKey(annotations.LastModifiedBy))
+}
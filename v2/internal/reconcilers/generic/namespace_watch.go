@@ -0,0 +1,84 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+)
+
+// namespaceChangedPredicate admits changes to the annotations or labels of a namespace, as these supply defaults
+// (sync period, tags, maintenance windows, merge paths and credentials) for the resources in it.
+var namespaceChangedPredicate = predicate.And(
+	predicate.Or(predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}),
+	predicate.Funcs{
+		// A new namespace has no resources in it yet, and a deleted one takes its resources with it
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return false },
+	})
+
+// makeNamespaceEventHandler returns an event handler that enqueues every resource of the specified kind in a
+// namespace when the namespace changes, so that a changed default is picked up without waiting for the next resync.
+func makeNamespaceEventHandler(
+	kubeClient kubeclient.Client,
+	scheme *runtime.Scheme,
+	gvk schema.GroupVersionKind,
+	log logr.Logger,
+) handler.EventHandler {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, namespace client.Object) []reconcile.Request {
+		obj, err := scheme.New(listGVK)
+		if err != nil {
+			log.Error(err, "unable to create list for namespace change", "kind", listGVK)
+			return nil
+		}
+
+		list, ok := obj.(client.ObjectList)
+		if !ok {
+			log.Error(nil, "list for namespace change isn't an ObjectList", "kind", listGVK)
+			return nil
+		}
+
+		err = kubeClient.List(ctx, list, client.InNamespace(namespace.GetName()))
+		if err != nil {
+			log.Error(err, "unable to list resources for namespace change", "namespace", namespace.GetName())
+			return nil
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			log.Error(err, "unable to extract resources for namespace change", "namespace", namespace.GetName())
+			return nil
+		}
+
+		result := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+
+			result = append(result, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()},
+			})
+		}
+
+		return result
+	})
+}
@@ -0,0 +1,354 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// ReconcilePriority is the priority of a reconcile request in the queue. Requests with a higher priority are
+// reconciled first; requests with the same priority are reconciled in the order they became ready. Requests that have
+// waited longer than maxWait are promoted to PriorityChange, so they can't be starved by higher priority requests.
+type ReconcilePriority int
+
+const (
+	// PriorityResync is used for periodic resyncs of resources that are already in their goal state, including
+	// those triggered by the initial listing of resources when the operator starts.
+	PriorityResync ReconcilePriority = handler.LowPriority
+	// PriorityPoll is used for requests that are waiting on something, such as a long-running operation in Azure,
+	// the creation of an owner, or a retry after an error.
+	PriorityPoll ReconcilePriority = 0
+	// PriorityDelete is used for resources that are being deleted.
+	PriorityDelete ReconcilePriority = 50
+	// PriorityChange is used for new resources and for resources whose spec (or annotations) have been changed.
+	PriorityChange ReconcilePriority = 100
+)
+
+const (
+	// maxWait is how long a request with a priority below PriorityChange may wait in the queue before it's promoted
+	// to PriorityChange. Without this, a steady stream of changes would hold up resyncs (and polls) indefinitely.
+	maxWait = 5 * time.Minute
+
+	// agingInterval is how often the queue looks for requests that have waited longer than maxWait
+	agingInterval = 30 * time.Second
+)
+
+// RequeuePriorities records the priority that each request should be requeued with once its reconcile completes.
+// Without this, controller-runtime requeues a request with the priority it was dequeued with, so every LRO poll
+// following a change would jump ahead of other changes.
+type RequeuePriorities struct {
+	lock    sync.Mutex
+	pending map[reconcile.Request]ReconcilePriority
+}
+
+// NewRequeuePriorities creates a new RequeuePriorities.
+func NewRequeuePriorities() *RequeuePriorities {
+	return &RequeuePriorities{
+		pending: make(map[reconcile.Request]ReconcilePriority),
+	}
+}
+
+// Set records the priority with which req should be requeued.
+func (p *RequeuePriorities) Set(req reconcile.Request, priority ReconcilePriority) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pending[req] = priority
+}
+
+// Forget discards any priority recorded for req.
+func (p *RequeuePriorities) Forget(req reconcile.Request) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.pending, req)
+}
+
+// take returns (and discards) the priority recorded for req, if any.
+func (p *RequeuePriorities) take(req reconcile.Request) (ReconcilePriority, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	priority, ok := p.pending[req]
+	delete(p.pending, req)
+	return priority, ok
+}
+
+// NewQueue creates a priority queue for the named controller which applies the recorded requeue priorities.
+// It has the signature required by controller.Options.NewQueue.
+func (p *RequeuePriorities) NewQueue(
+	log logr.Logger,
+) func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(
+		controllerName string,
+		rateLimiter workqueue.TypedRateLimiter[reconcile.Request],
+	) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		if rateLimiter == nil {
+			rateLimiter = workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]()
+		}
+
+		queue := priorityqueue.New(controllerName, func(o *priorityqueue.Opts[reconcile.Request]) {
+			o.Log = log.WithValues("controller", controllerName)
+			o.RateLimiter = rateLimiter
+		})
+
+		result := newPrioritizedQueue(queue, rateLimiter, p, maxWait, time.Now)
+		go result.age(agingInterval)
+
+		return result
+	}
+}
+
+// requeuePriorityFor returns the priority with which a resource should be requeued, based on its state at the end
+// of a reconcile.
+func requeuePriorityFor(obj genruntime.MetaObject) ReconcilePriority {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return PriorityDelete
+	}
+
+	ready, ok := conditions.GetCondition(obj, conditions.ConditionTypeReady)
+	if ok && ready.Status == metav1.ConditionTrue {
+		// Nothing left to do until the next resync
+		return PriorityResync
+	}
+
+	return PriorityPoll
+}
+
+// prioritizedQueue wraps a priority queue, replacing the priority of requeues with the priority recorded by the
+// reconciler, and promoting requests which have waited too long.
+type prioritizedQueue struct {
+	priorityqueue.PriorityQueue[reconcile.Request]
+	rateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+	priorities  *RequeuePriorities
+	maxWait     time.Duration
+	now         func() time.Time
+
+	lock sync.Mutex
+	// waiting records when each request with a priority below PriorityChange became (or will become) ready
+	waiting map[reconcile.Request]time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ priorityqueue.PriorityQueue[reconcile.Request] = &prioritizedQueue{}
+
+func newPrioritizedQueue(
+	queue priorityqueue.PriorityQueue[reconcile.Request],
+	rateLimiter workqueue.TypedRateLimiter[reconcile.Request],
+	priorities *RequeuePriorities,
+	maxWait time.Duration,
+	now func() time.Time,
+) *prioritizedQueue {
+	return &prioritizedQueue{
+		PriorityQueue: queue,
+		rateLimiter:   rateLimiter,
+		priorities:    priorities,
+		maxWait:       maxWait,
+		now:           now,
+		waiting:       make(map[reconcile.Request]time.Time),
+		stop:          make(chan struct{}),
+	}
+}
+
+func (q *prioritizedQueue) AddWithOpts(opts priorityqueue.AddOpts, items ...reconcile.Request) {
+	// The controller only ever requeues with a delay or with rate limiting. Events from watches do neither, and
+	// carry their own priority
+	if !opts.RateLimited && opts.After == 0 {
+		q.trackWaiting(opts, items...)
+		q.PriorityQueue.AddWithOpts(opts, items...)
+		return
+	}
+
+	for _, item := range items {
+		itemOpts := opts
+		if priority, ok := q.priorities.take(item); ok {
+			itemOpts.Priority = int(priority)
+		}
+
+		// Work out the rate limited delay ourselves (as the queue would), so we know when the request will be ready
+		if itemOpts.RateLimited {
+			itemOpts.RateLimited = false
+			if after := q.rateLimiter.When(item); itemOpts.After == 0 || after < itemOpts.After {
+				itemOpts.After = after
+			}
+		}
+
+		q.trackWaiting(itemOpts, item)
+		q.PriorityQueue.AddWithOpts(itemOpts, item)
+	}
+}
+
+func (q *prioritizedQueue) AddAfter(item reconcile.Request, duration time.Duration) {
+	q.AddWithOpts(priorityqueue.AddOpts{After: duration}, item)
+}
+
+func (q *prioritizedQueue) AddRateLimited(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{RateLimited: true}, item)
+}
+
+func (q *prioritizedQueue) Forget(item reconcile.Request) {
+	q.rateLimiter.Forget(item)
+}
+
+func (q *prioritizedQueue) NumRequeues(item reconcile.Request) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+func (q *prioritizedQueue) Get() (reconcile.Request, bool) {
+	item, _, shutdown := q.GetWithPriority()
+	return item, shutdown
+}
+
+func (q *prioritizedQueue) GetWithPriority() (reconcile.Request, int, bool) {
+	item, priority, shutdown := q.PriorityQueue.GetWithPriority()
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	delete(q.waiting, item)
+
+	return item, priority, shutdown
+}
+
+func (q *prioritizedQueue) ShutDown() {
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.PriorityQueue.ShutDown()
+}
+
+func (q *prioritizedQueue) ShutDownWithDrain() {
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.PriorityQueue.ShutDownWithDrain()
+}
+
+// trackWaiting records when requests added with a priority below PriorityChange become ready, so that they can be
+// promoted if they wait too long.
+func (q *prioritizedQueue) trackWaiting(opts priorityqueue.AddOpts, items ...reconcile.Request) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	readyAt := q.now().Add(opts.After)
+	for _, item := range items {
+		if ReconcilePriority(opts.Priority) >= PriorityChange {
+			delete(q.waiting, item)
+			continue
+		}
+
+		// The queue keeps the earliest time a request is ready
+		if existing, ok := q.waiting[item]; !ok || readyAt.Before(existing) {
+			q.waiting[item] = readyAt
+		}
+	}
+}
+
+// age periodically promotes requests which have waited too long, until the queue is shut down.
+func (q *prioritizedQueue) age(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			q.promoteStarved()
+		}
+	}
+}
+
+// promoteStarved promotes requests which have been ready for longer than maxWait to PriorityChange.
+func (q *prioritizedQueue) promoteStarved() {
+	now := q.now()
+
+	var starved []reconcile.Request
+	func() {
+		q.lock.Lock()
+		defer q.lock.Unlock()
+
+		for item, readyAt := range q.waiting {
+			if now.Sub(readyAt) >= q.maxWait {
+				starved = append(starved, item)
+				delete(q.waiting, item)
+			}
+		}
+	}()
+
+	for _, item := range starved {
+		// Adding a request that's already queued raises its priority. As it's already ready, the delay has no effect
+		// on it; the delay only applies if the request was handed out in the meantime, in which case it's reconciled
+		// once more, later, which is harmless.
+		q.PriorityQueue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityChange), After: q.maxWait}, item)
+	}
+}
+
+// priorityEventHandler enqueues a request for the object in each event, prioritising changes made by users over
+// deletes, and both of those over resyncs.
+type priorityEventHandler struct{}
+
+var _ handler.EventHandler = priorityEventHandler{}
+
+func (h priorityEventHandler) Create(_ context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	priority := PriorityChange
+	if e.IsInInitialList {
+		// The operator has just started, and we're seeing everything for the first time
+		priority = PriorityResync
+	}
+
+	h.add(q, e.Object, priority)
+}
+
+func (h priorityEventHandler) Update(_ context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	var priority ReconcilePriority
+	switch {
+	case e.ObjectNew == nil || e.ObjectOld == nil:
+		priority = PriorityChange
+	case !e.ObjectNew.GetDeletionTimestamp().IsZero():
+		priority = PriorityDelete
+	case e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion():
+		// Informer resync, nothing has changed
+		priority = PriorityResync
+	default:
+		priority = PriorityChange
+	}
+
+	h.add(q, e.ObjectNew, priority)
+}
+
+func (h priorityEventHandler) Delete(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.add(q, e.Object, PriorityDelete)
+}
+
+func (h priorityEventHandler) Generic(_ context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.add(q, e.Object, PriorityChange)
+}
+
+func (h priorityEventHandler) add(q workqueue.TypedRateLimitingInterface[reconcile.Request], obj client.Object, priority ReconcilePriority) {
+	if obj == nil {
+		return
+	}
+
+	req := reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	}
+
+	if pq, ok := q.(priorityqueue.PriorityQueue[reconcile.Request]); ok {
+		pq.AddWithOpts(priorityqueue.AddOpts{Priority: int(priority)}, req)
+		return
+	}
+
+	q.Add(req)
+}
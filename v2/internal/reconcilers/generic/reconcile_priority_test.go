@@ -0,0 +1,117 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newRequest(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+}
+
+// newTestQueue returns a prioritized queue whose clock is controlled by the returned function
+func newTestQueue(t *testing.T) (*prioritizedQueue, func(time.Duration)) {
+	now := time.Now()
+	queue := newPrioritizedQueue(
+		priorityqueue.New[reconcile.Request]("test"),
+		workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+		NewRequeuePriorities(),
+		time.Minute,
+		func() time.Time { return now })
+	t.Cleanup(queue.ShutDown)
+
+	return queue, func(d time.Duration) { now = now.Add(d) }
+}
+
+func Test_PrioritizedQueue_ServesHigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	queue, _ := newTestQueue(t)
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityResync)}, newRequest("resync"))
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityChange)}, newRequest("change"))
+
+	item, priority, _ := queue.GetWithPriority()
+	g.Expect(item).To(Equal(newRequest("change")))
+	g.Expect(priority).To(Equal(int(PriorityChange)))
+}
+
+func Test_PrioritizedQueue_PromotesStarvedRequests(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	queue, advance := newTestQueue(t)
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityResync)}, newRequest("resync"))
+
+	// Not waited long enough
+	advance(30 * time.Second)
+	queue.promoteStarved()
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityChange)}, newRequest("first-change"))
+
+	item, _, _ := queue.GetWithPriority()
+	g.Expect(item).To(Equal(newRequest("first-change")))
+	queue.Done(item)
+
+	// Waited long enough, so served ahead of later changes
+	advance(30 * time.Second)
+	queue.promoteStarved()
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityChange)}, newRequest("second-change"))
+
+	item, priority, _ := queue.GetWithPriority()
+	g.Expect(item).To(Equal(newRequest("resync")))
+	g.Expect(priority).To(Equal(int(PriorityChange)))
+	g.Expect(queue.waiting).To(BeEmpty())
+}
+
+func Test_PrioritizedQueue_DelayedRequestsWaitFromWhenTheyAreReady(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	queue, advance := newTestQueue(t)
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityPoll), After: time.Hour}, newRequest("poll"))
+
+	// The request only becomes ready after an hour, so hasn't started waiting yet
+	advance(2 * time.Minute)
+	queue.promoteStarved()
+	g.Expect(queue.waiting).To(HaveKey(newRequest("poll")))
+	g.Expect(queue.Len()).To(Equal(0))
+
+	advance(time.Hour)
+	queue.promoteStarved()
+	g.Expect(queue.waiting).To(BeEmpty())
+}
+
+func Test_PrioritizedQueue_RateLimitedRequestsWaitFromWhenTheyAreReady(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	queue, _ := newTestQueue(t)
+	queue.AddRateLimited(newRequest("retry"))
+
+	g.Expect(queue.waiting).To(HaveKey(newRequest("retry")))
+	g.Expect(queue.NumRequeues(newRequest("retry"))).To(Equal(1))
+}
+
+func Test_PrioritizedQueue_ChangesAreNotTracked(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	queue, _ := newTestQueue(t)
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityResync)}, newRequest("resource"))
+	g.Expect(queue.waiting).To(HaveKey(newRequest("resource")))
+
+	queue.AddWithOpts(priorityqueue.AddOpts{Priority: int(PriorityChange)}, newRequest("resource"))
+	g.Expect(queue.waiting).To(BeEmpty())
+}
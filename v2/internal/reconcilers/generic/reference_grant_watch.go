@@ -0,0 +1,98 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// makeReferenceGrantEventHandler returns an event handler that enqueues the resources of the specified kind which are
+// waiting for a ReferenceGrant, in each namespace a ReferenceGrant allows references from, when it changes. Without
+// this, such resources would wait for their next (slow) retry before noticing they've been granted access.
+func makeReferenceGrantEventHandler(
+	kubeClient kubeclient.Client,
+	scheme *runtime.Scheme,
+	gvk schema.GroupVersionKind,
+	log logr.Logger,
+) handler.EventHandler {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		grant, ok := obj.(*serviceoperator.ReferenceGrant)
+		if !ok {
+			return nil
+		}
+
+		var result []reconcile.Request
+		for _, from := range grant.Spec.From {
+			listObj, err := scheme.New(listGVK)
+			if err != nil {
+				log.Error(err, "unable to create list for ReferenceGrant change", "kind", listGVK)
+				return nil
+			}
+
+			list, ok := listObj.(client.ObjectList)
+			if !ok {
+				log.Error(nil, "list for ReferenceGrant change isn't an ObjectList", "kind", listGVK)
+				return nil
+			}
+
+			err = kubeClient.List(ctx, list, client.InNamespace(from.Namespace))
+			if err != nil {
+				log.Error(err, "unable to list resources for ReferenceGrant change", "namespace", from.Namespace)
+				continue
+			}
+
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				log.Error(err, "unable to extract resources for ReferenceGrant change", "namespace", from.Namespace)
+				continue
+			}
+
+			for _, item := range items {
+				if !isWaitingForReferenceGrant(item) {
+					continue
+				}
+
+				accessor, err := meta.Accessor(item)
+				if err != nil {
+					continue
+				}
+
+				result = append(result, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()},
+				})
+			}
+		}
+
+		return result
+	})
+}
+
+// isWaitingForReferenceGrant returns true if the Ready condition of the resource shows that it refers to something in
+// another namespace without being allowed to by a ReferenceGrant.
+func isWaitingForReferenceGrant(obj runtime.Object) bool {
+	conditioner, ok := obj.(conditions.Conditioner)
+	if !ok {
+		return false
+	}
+
+	ready, ok := conditions.GetCondition(conditioner, conditions.ConditionTypeReady)
+	return ok && ready.Reason == conditions.ReasonReferenceNotGranted.Name
+}
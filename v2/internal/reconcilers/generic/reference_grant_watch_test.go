@@ -0,0 +1,86 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func newResourceGroupWithReadyReason(namespace string, name string, reason string) *resources.ResourceGroup {
+	return &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: resources.ResourceGroup_STATUS{
+			Conditions: []conditions.Condition{
+				{
+					Type:   conditions.ConditionTypeReady,
+					Status: metav1.ConditionFalse,
+					Reason: reason,
+				},
+			},
+		},
+	}
+}
+
+func Test_ReferenceGrantEventHandler_EnqueuesResourcesWaitingForGrant(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(resources.AddToScheme(scheme)).To(Succeed())
+	g.Expect(serviceoperator.AddToScheme(scheme)).To(Succeed())
+
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newResourceGroupWithReadyReason("team-a", "waiting", conditions.ReasonReferenceNotGranted.Name),
+			newResourceGroupWithReadyReason("team-a", "other-problem", conditions.ReasonWaitingForOwner.Name),
+			newResourceGroupWithReadyReason("team-b", "not-granted", conditions.ReasonReferenceNotGranted.Name)).
+		Build()
+
+	gvk := resources.GroupVersion.WithKind("ResourceGroup")
+	eventHandler := makeReferenceGrantEventHandler(kubeclient.NewClient(kubeClient), scheme, gvk, logr.Discard())
+
+	grant := &serviceoperator.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grant",
+			Namespace: "shared",
+		},
+		Spec: serviceoperator.ReferenceGrantSpec{
+			From: []serviceoperator.ReferenceGrantFrom{{Namespace: "team-a"}},
+			To:   []serviceoperator.ReferenceGrantTo{{Kind: "Secret"}},
+		},
+	}
+
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+
+	eventHandler.Create(ctx, event.CreateEvent{Object: grant}, queue)
+
+	g.Expect(queue.Len()).To(Equal(1))
+	item, _ := queue.Get()
+	g.Expect(item).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "waiting"}}))
+}
@@ -13,15 +13,23 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/rotisserie/eris"
+	"golang.org/x/time/rate"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/changeevents"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
@@ -39,6 +47,21 @@ type Options struct {
 	LoggerFactory             func(obj metav1.Object) logr.Logger
 
 	PanicHandler func()
+
+	// ChangeEvents, if set, enqueues resources for reconcile when they're changed in Azure
+	ChangeEvents *changeevents.Dispatcher
+
+	// WatchReferenceGrants enqueues resources waiting for a ReferenceGrant when one changes. Only set this if the
+	// ReferenceGrant CRD is installed.
+	WatchReferenceGrants bool
+
+	// ApplyPayloadMutationPolicies applies PayloadMutationPolicies to the payloads sent to Azure. Only set this if the
+	// PayloadMutationPolicy CRD is installed.
+	ApplyPayloadMutationPolicies bool
+
+	// RecordResourcePlans records the plans of resources with the plan reconcile policy in ResourcePlans. Only set this
+	// if the ResourcePlan CRD is installed.
+	RecordResourcePlans bool
 }
 
 func RegisterWebhooks(mgr ctrl.Manager, objs []*registration.KnownType) error {
@@ -59,12 +82,28 @@ func registerWebhook(mgr ctrl.Manager, knownType *registration.KnownType) error
 		return eris.Wrap(err, "obj was expected to be ptr but was not")
 	}
 
+	// Record who last changed each resource, so that changes made in Azure can be attributed to them
+	defaulter := knownType.Defaulter
+	if defaulter != nil {
+		defaulter = lastModifiedByDefaulter{CustomDefaulter: defaulter}
+	}
+
+	// Check resources against the AdmissionPolicies of the cluster
+	validator := knownType.Validator
+	if validator != nil {
+		validator = admissionPolicyValidator{
+			CustomValidator: validator,
+			kubeClient:      mgr.GetClient(),
+			scheme:          mgr.GetScheme(),
+		}
+	}
+
 	// Register the webhooks. Note that this is safe to call even if there isn't a defaulter/validator
 	// as the NewWebhookManagedBy builder no-ops in the case they're both not set.
 	err = ctrl.NewWebhookManagedBy(mgr).
 		For(knownType.Obj).
-		WithDefaulter(knownType.Defaulter).
-		WithValidator(knownType.Validator).
+		WithDefaulter(defaulter).
+		WithValidator(validator).
 		Complete()
 	if err != nil {
 		return eris.Wrapf(err, "unable to register webhooks for %T", knownType.Obj)
@@ -129,7 +168,11 @@ func register(
 	}
 	eventRecorder := mgr.GetEventRecorderFor(info.Name)
 
-	options.LogConstructor(nil).V(Status).Info("Registering", "GVK", gvk)
+	controllerOptions := resourceTypeControllerOptions(gvk.GroupKind(), options)
+	options.LogConstructor(nil).V(Status).Info(
+		"Registering",
+		"GVK", gvk,
+		"maxConcurrentReconciles", controllerOptions.MaxConcurrentReconciles)
 
 	reconciler := &GenericReconciler{
 		Reconciler:                info.Reconciler,
@@ -143,11 +186,39 @@ func register(
 		PanicHandler:              options.PanicHandler,
 	}
 
-	builder := ctrl.NewControllerManagedBy(mgr).
-		For(info.Obj, ctrlbuilder.WithPredicates(info.Predicate)).
-		WithOptions(options.Options)
+	builder := ctrl.NewControllerManagedBy(mgr)
+	var eventHandler handler.EventHandler = &handler.EnqueueRequestForObject{}
+	if options.Config.EnablePriorityQueue {
+		// Watch the resource ourselves (rather than using For) so that we can prioritise each event
+		priorities := NewRequeuePriorities()
+		reconciler.RequeuePriorities = priorities
+		controllerOptions.NewQueue = priorities.NewQueue(options.LogConstructor(nil).WithName(info.Name))
+		eventHandler = priorityEventHandler{}
+		builder = builder.Watches(info.Obj, eventHandler, ctrlbuilder.WithPredicates(info.Predicate))
+	} else {
+		builder = builder.For(info.Obj, ctrlbuilder.WithPredicates(info.Predicate))
+	}
+
+	// Only resources in Azure Resource Manager raise change events
+	if _, isARMResource := info.Obj.(genruntime.ARMMetaObject); isARMResource && options.ChangeEvents != nil {
+		builder = builder.WatchesRawSource(options.ChangeEvents.Source(gvk.GroupKind(), eventHandler))
+	}
+
+	builder = builder.WithOptions(controllerOptions)
 	builder.Named(info.Name)
 
+	// Namespaces supply defaults for the resources in them
+	builder = builder.Watches(
+		&v1.Namespace{},
+		makeNamespaceEventHandler(kubeClient, mgr.GetScheme(), gvk, options.LogConstructor(nil).WithName(info.Name)),
+		ctrlbuilder.WithPredicates(namespaceChangedPredicate))
+
+	if options.WatchReferenceGrants {
+		builder = builder.Watches(
+			&serviceoperator.ReferenceGrant{},
+			makeReferenceGrantEventHandler(kubeClient, mgr.GetScheme(), gvk, options.LogConstructor(nil).WithName(info.Name)))
+	}
+
 	for _, watch := range info.Watches {
 		builder = builder.Watches(watch.Type, watch.MakeEventHandler(kubeClient, options.LogConstructor(nil).WithName(info.Name)))
 	}
@@ -159,3 +230,39 @@ func register(
 
 	return nil
 }
+
+// resourceTypeControllerOptions returns the controller options for the specified resource type, applying any
+// ResourceTypeLimits that have been configured for it.
+func resourceTypeControllerOptions(gk schema.GroupKind, options Options) controller.Options {
+	result := options.Options
+	limit := options.Config.ResourceTypeLimits.For(gk)
+
+	if limit.MaxConcurrentReconciles > 0 {
+		result.MaxConcurrentReconciles = limit.MaxConcurrentReconciles
+	}
+
+	if limit.QPS > 0 {
+		bucketSize := limit.BucketSize
+		if bucketSize <= 0 {
+			bucketSize = options.Config.RateLimit.BucketSize
+		}
+		if bucketSize <= 0 {
+			// A bucket of size zero would never admit anything
+			bucketSize = 1
+		}
+
+		// Each resource type gets its own bucket, in addition to any limits shared by all resource types
+		limiters := []workqueue.TypedRateLimiter[reconcile.Request]{
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{
+				Limiter: rate.NewLimiter(rate.Limit(limit.QPS), bucketSize),
+			},
+		}
+		if result.RateLimiter != nil {
+			limiters = append(limiters, result.RateLimiter)
+		}
+
+		result.RateLimiter = workqueue.NewTypedMaxOfRateLimiter(limiters...)
+	}
+
+	return result
+}
@@ -0,0 +1,37 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"context"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// AnnotationsFromObjectOrNamespace returns the annotations of obj if it has the specified annotation, and otherwise
+// the annotations of its namespace. This allows an annotation on a namespace to act as the default for all the
+// resources in it, while keeping related annotations (e.g. a value and its options) together.
+func AnnotationsFromObjectOrNamespace(
+	ctx context.Context,
+	kubeClient kubeclient.Client,
+	obj genruntime.MetaObject,
+	annotation string,
+) (map[string]string, error) {
+	if _, ok := obj.GetAnnotations()[annotation]; ok || obj.GetNamespace() == "" {
+		// Nothing to look up: the resource either sets the annotation itself, or isn't in a namespace
+		return obj.GetAnnotations(), nil
+	}
+
+	namespace, err := kubeclient.GetNamespace(ctx, kubeClient, obj.GetNamespace())
+	if err != nil {
+		return nil, eris.Wrapf(err, "getting namespace %q", obj.GetNamespace())
+	}
+
+	return namespace.GetAnnotations(), nil
+}
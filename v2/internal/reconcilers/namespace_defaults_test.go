@@ -0,0 +1,100 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+// newNamespaceCountingClient returns a client holding the specified namespace, and a count of the namespaces it has
+// been asked for
+func newNamespaceCountingClient(t *testing.T, namespace *v1.Namespace) (kubeclient.Client, *int) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+	gets := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(namespace).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*v1.Namespace); ok {
+					gets++
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+
+	return kubeclient.NewClient(fakeClient), &gets
+}
+
+func TestAnnotationsFromObjectOrNamespace(t *testing.T) {
+	t.Parallel()
+
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{annotations.SyncPeriod: "1h"},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		namespace    string
+		annotations  map[string]string
+		expected     string
+		expectedGets int
+	}{
+		{"Resource annotation wins without a lookup", "team-a", map[string]string{annotations.SyncPeriod: "5m"}, "5m", 0},
+		{"Namespace annotation is the default, looked up once", "team-a", nil, "1h", 1},
+		{"Cluster-scoped resource has no namespace to look up", "", nil, "", 0},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			kubeClient, gets := newNamespaceCountingClient(t, namespace)
+			ctx := kubeclient.WithNamespaces(context.Background())
+
+			rg := &resources.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "myrg",
+					Namespace:   c.namespace,
+					Annotations: c.annotations,
+				},
+			}
+
+			// Several features consult the namespace during a reconcile
+			for i := 0; i < 3; i++ {
+				source, err := AnnotationsFromObjectOrNamespace(ctx, kubeClient, rg, annotations.SyncPeriod)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(source[annotations.SyncPeriod]).To(Equal(c.expected))
+			}
+
+			g.Expect(*gets).To(Equal(c.expectedGets))
+		})
+	}
+}
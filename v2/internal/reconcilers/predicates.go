@@ -21,7 +21,14 @@ import (
 func ARMReconcilerAnnotationChangedPredicate() predicate.Predicate {
 	return predicates.MakeSelectAnnotationChangedPredicate(
 		map[string]predicates.HasAnnotationChanged{
-			annotations.ReconcilePolicy: HasReconcilePolicyAnnotationChanged,
+			annotations.ReconcilePolicy:           HasReconcilePolicyAnnotationChanged,
+			annotations.DeletionProtection:        HasAnnotationChanged,
+			annotations.ConfirmDeletion:           HasAnnotationChanged,
+			annotations.SyncPeriod:                HasAnnotationChanged,
+			annotations.MaintenanceWindow:         HasAnnotationChanged,
+			annotations.MaintenanceWindowDuration: HasAnnotationChanged,
+			annotations.CreationMode:              HasAnnotationChanged,
+			annotations.MergePaths:                HasAnnotationChanged,
 		})
 }
 
@@ -31,19 +38,19 @@ func ARMPerResourceSecretAnnotationChangedPredicate() predicate.Predicate {
 	return predicates.MakeSelectAnnotationChangedPredicate(
 		map[string]predicates.HasAnnotationChanged{
 			annotations.PerResourceSecret: HasAnnotationChanged,
+			annotations.AzureCredential:   HasAnnotationChanged,
 		})
 }
 
 // GetReconcilePolicy gets the reconcile-policy from the ReconcilePolicy
 func GetReconcilePolicy(obj genruntime.MetaObject, log logr.Logger, defaultReconcilePolicy annotations.ReconcilePolicyValue) annotations.ReconcilePolicyValue {
-	policyStr := obj.GetAnnotations()[annotations.ReconcilePolicy]
-	policy, err := ParseReconcilePolicy(policyStr, defaultReconcilePolicy)
+	policy, err := ReconcilePolicyOf(obj, defaultReconcilePolicy)
 	if err != nil {
 		log.Error(
 			err,
 			"failed to get reconcile policy. Applying default policy instead",
 			"chosenPolicy", policy,
-			"policyAnnotation", policyStr)
+			"policyAnnotation", obj.GetAnnotations()[annotations.ReconcilePolicy])
 	}
 
 	return policy
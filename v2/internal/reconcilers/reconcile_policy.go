@@ -12,6 +12,7 @@ import (
 
 	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 )
 
 // ParseReconcilePolicy parses the provided reconcile policy.
@@ -27,11 +28,25 @@ func ParseReconcilePolicy(policy string, defaultReconcilePolicy annotations.Reco
 		return annotations.ReconcilePolicySkip, nil
 	case string(annotations.ReconcilePolicyDetachOnDelete):
 		return annotations.ReconcilePolicyDetachOnDelete, nil
+	case string(annotations.ReconcilePolicyObserve):
+		return annotations.ReconcilePolicyObserve, nil
+	case string(annotations.ReconcilePolicyPlan):
+		return annotations.ReconcilePolicyPlan, nil
 	default:
 		return defaultReconcilePolicy, eris.Errorf("%q is not a known reconcile policy", policy)
 	}
 }
 
+// ReconcilePolicyOf returns the reconcile policy of obj, as given by its reconcile-policy annotation. Read-only
+// resources (see genruntime.ReadOnlyResource) always have the observe policy.
+func ReconcilePolicyOf(obj genruntime.MetaObject, defaultReconcilePolicy annotations.ReconcilePolicyValue) (annotations.ReconcilePolicyValue, error) {
+	if readOnly, ok := obj.(genruntime.ReadOnlyResource); ok && readOnly.IsReadOnly() {
+		return annotations.ReconcilePolicyObserve, nil
+	}
+
+	return ParseReconcilePolicy(obj.GetAnnotations()[annotations.ReconcilePolicy], defaultReconcilePolicy)
+}
+
 // HasReconcilePolicyAnnotationChanged returns true if the reconcile-policy annotation has
 // changed in a way that needs to trigger a reconcile.
 func HasReconcilePolicyAnnotationChanged(old *string, new *string) bool {
@@ -44,8 +59,9 @@ func HasReconcilePolicyAnnotationChanged(old *string, new *string) bool {
 	oldStr := to.Value(old)
 	newStr := to.Value(new)
 
-	// We only care about transitions to or from ReconcilePolicySkip. We don't need to
-	// trigger an event if ReconcilePolicyDetachOnDelete is added or removed, as that annotation
+	// We only care about transitions to or from the read-only policies (ReconcilePolicySkip, ReconcilePolicyObserve
+	// and ReconcilePolicyPlan).
+	// We don't need to trigger an event if ReconcilePolicyDetachOnDelete is added or removed, as that annotation
 	// only applies on delete (which we will always run reconcile on).
-	return oldStr == string(annotations.ReconcilePolicySkip) || newStr == string(annotations.ReconcilePolicySkip)
+	return annotations.ReconcilePolicyValue(oldStr).IsReadOnly() || annotations.ReconcilePolicyValue(newStr).IsReadOnly()
 }
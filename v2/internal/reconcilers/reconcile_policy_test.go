@@ -10,7 +10,13 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 )
 
 func TestParseReconcilePolicy(t *testing.T) {
@@ -18,6 +24,8 @@ func TestParseReconcilePolicy(t *testing.T) {
 		"manage":           annotations.ReconcilePolicyManage,
 		"skip":             annotations.ReconcilePolicySkip,
 		"detach-on-delete": annotations.ReconcilePolicyDetachOnDelete,
+		"observe":          annotations.ReconcilePolicyObserve,
+		"plan":             annotations.ReconcilePolicyPlan,
 	}
 
 	t.Parallel()
@@ -40,3 +48,62 @@ func TestParseReconcilePolicy(t *testing.T) {
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(returnedPolicy).Should(Equal(annotations.ReconcilePolicySkip))
 }
+
+func TestHasReconcilePolicyAnnotationChanged(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		old      *string
+		new      *string
+		expected bool
+	}{
+		{"No change", to.Ptr("skip"), to.Ptr("skip"), false},
+		{"Added skip", nil, to.Ptr("skip"), true},
+		{"Removed skip", to.Ptr("skip"), nil, true},
+		{"Added observe", nil, to.Ptr("observe"), true},
+		{"Observe to manage", to.Ptr("observe"), to.Ptr("manage"), true},
+		{"Skip to observe", to.Ptr("skip"), to.Ptr("observe"), true},
+		{"Plan to manage", to.Ptr("plan"), to.Ptr("manage"), true},
+		{"Added detach-on-delete", nil, to.Ptr("detach-on-delete"), false},
+		{"Manage to detach-on-delete", to.Ptr("manage"), to.Ptr("detach-on-delete"), false},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(HasReconcilePolicyAnnotationChanged(c.old, c.new)).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestReconcilePolicyOf(t *testing.T) {
+	t.Parallel()
+
+	manage := map[string]string{annotations.ReconcilePolicy: "manage"}
+
+	cases := []struct {
+		name     string
+		obj      genruntime.MetaObject
+		expected annotations.ReconcilePolicyValue
+	}{
+		{"No annotation", &resources.ResourceGroup{}, annotations.ReconcilePolicyDetachOnDelete},
+		{"Annotation", &resources.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Annotations: manage}}, annotations.ReconcilePolicyManage},
+		{"Read-only resource", &serviceoperator.ResourceLookup{ObjectMeta: metav1.ObjectMeta{Annotations: manage}}, annotations.ReconcilePolicyObserve},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			policy, err := ReconcilePolicyOf(c.obj, annotations.ReconcilePolicyDetachOnDelete)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(policy).To(Equal(c.expected))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// GetSyncPeriod returns the sync period specified by the sync-period annotation on the object or its namespace.
+// Returns false if neither specifies a valid sync period, in which case the operator-wide default applies.
+// A nil sync period means the resource should not be periodically re-synced.
+func GetSyncPeriod(
+	ctx context.Context,
+	kubeClient kubeclient.Client,
+	log logr.Logger,
+	obj genruntime.MetaObject,
+) (*time.Duration, bool) {
+	source, err := AnnotationsFromObjectOrNamespace(ctx, kubeClient, obj, annotations.SyncPeriod)
+	if err != nil {
+		log.Error(err, "failed to get sync period. Applying default instead")
+		return nil, false
+	}
+
+	value, ok := source[annotations.SyncPeriod]
+	if !ok {
+		return nil, false
+	}
+
+	syncPeriod, err := config.ParseSyncPeriod(value)
+	if err != nil {
+		log.Error(
+			err,
+			"failed to parse sync period. Applying default instead",
+			"syncPeriodAnnotation", value)
+		return nil, false
+	}
+
+	return syncPeriod, true
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resolver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// ARMIDIndexKey is the key of the index of resources by their ARM ID (as recorded in the resource-id annotation).
+// IDs are indexed in lowercase, as ARM IDs are case-insensitive.
+const ARMIDIndexKey = ".metadata.annotations.resourceID"
+
+// ARMIDIndex returns the index required by ResolveResourcesByARMID. It must be registered for each storage type
+// that will be looked up by ARM ID.
+func ARMIDIndex() registration.Index {
+	return registration.Index{
+		Key:  ARMIDIndexKey,
+		Func: indexARMID,
+	}
+}
+
+// indexARMID an index function for the ARM ID of resources
+func indexARMID(rawObj client.Object) []string {
+	obj, ok := rawObj.(genruntime.ARMMetaObject)
+	if !ok {
+		return nil
+	}
+
+	id, ok := genruntime.GetResourceID(obj)
+	if !ok || id == "" {
+		return nil
+	}
+
+	return []string{strings.ToLower(id)}
+}
+
+// ResolveResourcesByARMID returns the resources of the specified kind which have the specified ARM ID. Usually there
+// is at most one, but nothing prevents multiple resources (for example in different namespaces) from referring to
+// the same Azure resource.
+func (r *Resolver) ResolveResourcesByARMID(
+	ctx context.Context,
+	groupKind schema.GroupKind,
+	armID string,
+) ([]genruntime.ARMMetaObject, error) {
+	gvk, ok := r.reconciledResourceLookup[groupKind]
+	if !ok {
+		return nil, eris.Errorf("group: %q, kind: %q was not in reconciledResourceLookup", groupKind.Group, groupKind.Kind)
+	}
+
+	return r.listByIndex(ctx, gvk, ARMIDIndexKey, strings.ToLower(armID))
+}
+
+// listByIndex returns the resources of the specified type with the specified value in the specified index
+func (r *Resolver) listByIndex(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	indexKey string,
+	value string,
+) ([]genruntime.ARMMetaObject, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	rawList, err := r.client.Scheme().New(listGVK)
+	if err != nil {
+		return nil, eris.Wrapf(err, "creating list for %s", gvk)
+	}
+
+	list, ok := rawList.(client.ObjectList)
+	if !ok {
+		return nil, eris.Errorf("%T was not of type client.ObjectList", rawList)
+	}
+
+	err = r.client.List(ctx, list, client.MatchingFields{indexKey: value})
+	if err != nil {
+		return nil, eris.Wrapf(err, "listing %s with %s %s", gvk.Kind, indexKey, value)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, eris.Wrapf(err, "extracting items from %T", list)
+	}
+
+	result := make([]genruntime.ARMMetaObject, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(genruntime.ARMMetaObject)
+		if !ok {
+			return nil, eris.Errorf("%T was not of type genruntime.ARMMetaObject", item)
+		}
+
+		result = append(result, obj)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resolver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+)
+
+func Test_ResolveResourcesByARMID_FindsResourceIgnoringCase(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	s := createTestScheme()
+	index := resolver.ARMIDIndex()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithIndex(&resources.ResourceGroup{}, index.Key, index.Func).
+		Build()
+	client := kubeclient.NewClient(fakeClient)
+	res, err := NewTestResolver(client)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rg := createResourceGroup("myrg")
+	g.Expect(client.Create(ctx, rg)).To(Succeed())
+	g.Expect(client.Create(ctx, createResourceGroup("otherrg"))).To(Succeed())
+
+	groupKind := schema.GroupKind{Group: resolver.ResourceGroupGroup, Kind: resolver.ResourceGroupKind}
+	found, err := res.ResolveResourcesByARMID(ctx, groupKind, strings.ToUpper("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(HaveLen(1))
+	g.Expect(found[0].GetName()).To(Equal(rg.Name))
+
+	found, err = res.ResolveResourcesByARMID(ctx, groupKind, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/missing")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeEmpty())
+}
+
+func Test_ResolveResourcesByARMID_UnknownKind_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	test, err := testSetup()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = test.resolver.ResolveResourcesByARMID(context.TODO(), schema.GroupKind{Group: "unknown.azure.com", Kind: "Unknown"}, "/subscriptions/00000000-0000-0000-0000-000000000000")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resolver
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// DeletionProtectedIndexKey is the key of the index of resources with the deletion-protection annotation, by the ARM
+// IDs of the resources containing them in Azure (deleting any of which deletes the resource too). IDs are indexed in
+// lowercase, as ARM IDs are case-insensitive. Only resources with the annotation are indexed, so the index is small.
+const DeletionProtectedIndexKey = ".metadata.annotations.deletionProtection"
+
+// DeletionProtectedIndex returns the index required by ResolveDeletionProtectedDescendants. It must be registered for
+// each storage type passed to IndexDeletionProtectedTypes.
+func DeletionProtectedIndex() registration.Index {
+	return registration.Index{
+		Key:  DeletionProtectedIndexKey,
+		Func: indexDeletionProtected,
+	}
+}
+
+// indexDeletionProtected an index function for the ARM IDs of the resources containing a deletion protected resource
+func indexDeletionProtected(rawObj client.Object) []string {
+	obj, ok := rawObj.(genruntime.ARMMetaObject)
+	if !ok {
+		return nil
+	}
+
+	if _, protected := obj.GetAnnotations()[annotations.DeletionProtection]; !protected {
+		return nil
+	}
+
+	id, ok := genruntime.GetResourceID(obj)
+	if !ok || id == "" {
+		return nil
+	}
+
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil {
+		return nil
+	}
+
+	// The root of every ID is the tenant, which we never delete
+	var result []string
+	for parent := resourceID.Parent; parent != nil && parent.Parent != nil; parent = parent.Parent {
+		result = append(result, strings.ToLower(parent.String()))
+	}
+
+	return result
+}
+
+// IndexDeletionProtectedTypes records the storage types searched by ResolveDeletionProtectedDescendants. Only types
+// with the DeletionProtectedIndex are recorded, so pass the types for which indexes are registered.
+func (r *Resolver) IndexDeletionProtectedTypes(scheme *runtime.Scheme, objs []*registration.StorageType) error {
+	for _, obj := range objs {
+		hasIndex := slices.ContainsFunc(obj.Indexes, func(index registration.Index) bool {
+			return index.Key == DeletionProtectedIndexKey
+		})
+		if !hasIndex {
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(obj.Obj, scheme)
+		if err != nil {
+			return eris.Wrapf(err, "creating GVK for obj %T", obj)
+		}
+
+		if !slices.Contains(r.deletionProtectedTypes, gvk) {
+			r.deletionProtectedTypes = append(r.deletionProtectedTypes, gvk)
+		}
+	}
+
+	return nil
+}
+
+// ResolveDeletionProtectedDescendants returns the resources with the deletion-protection annotation which are contained
+// in Azure by the resource with the specified ARM ID, in any namespace. Whether they're protected (rather than having
+// protection disabled, or their deletion confirmed) is left to the caller.
+func (r *Resolver) ResolveDeletionProtectedDescendants(ctx context.Context, armID string) ([]genruntime.ARMMetaObject, error) {
+	var result []genruntime.ARMMetaObject
+	for _, gvk := range r.deletionProtectedTypes {
+		objs, err := r.listByIndex(ctx, gvk, DeletionProtectedIndexKey, strings.ToLower(armID))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, objs...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resolver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	batch "github.com/Azure/azure-service-operator/v2/api/batch/v1api20210101"
+	mysql "github.com/Azure/azure-service-operator/v2/api/dbformysql/v1api20210501"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+func Test_ResolveDeletionProtectedDescendants_FindsProtectedResourcesWithin(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	s := createTestScheme()
+	index := resolver.DeletionProtectedIndex()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithIndex(&resources.ResourceGroup{}, index.Key, index.Func).
+		WithIndex(&batch.BatchAccount{}, index.Key, index.Func).
+		Build()
+	client := kubeclient.NewClient(fakeClient)
+	res := resolver.NewResolver(client)
+
+	types := []*registration.StorageType{
+		{Obj: new(resources.ResourceGroup), Indexes: []registration.Index{index}},
+		{Obj: new(batch.BatchAccount), Indexes: []registration.Index{index}},
+		registration.NewStorageType(new(mysql.FlexibleServer)),
+	}
+	g.Expect(res.IndexDeletionProtectedTypes(s, types)).To(Succeed())
+
+	rg, protected := createResourceGroupRootedResource("myrg", "protected")
+	protected.SetAnnotations(map[string]string{
+		genruntime.ResourceIDAnnotation: rg.GetAnnotations()[genruntime.ResourceIDAnnotation] + "/providers/Microsoft.Batch/batchAccounts/protected",
+		annotations.DeletionProtection:  "true",
+	})
+	_, unprotected := createResourceGroupRootedResource("myrg", "unprotected")
+	unprotected.SetAnnotations(map[string]string{
+		genruntime.ResourceIDAnnotation: rg.GetAnnotations()[genruntime.ResourceIDAnnotation] + "/providers/Microsoft.Batch/batchAccounts/unprotected",
+	})
+	otherRG := createResourceGroup("otherrg")
+	otherRG.Annotations[annotations.DeletionProtection] = "true"
+
+	for _, obj := range []genruntime.ARMMetaObject{rg, protected, unprotected, otherRG} {
+		g.Expect(client.Create(ctx, obj)).To(Succeed())
+	}
+
+	found, err := res.ResolveDeletionProtectedDescendants(ctx, strings.ToUpper(rg.GetAnnotations()[genruntime.ResourceIDAnnotation]))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(HaveLen(1))
+	g.Expect(found[0].GetName()).To(Equal(protected.GetName()))
+
+	// A resource isn't within itself, and the subscription is never deleted along with a resource group
+	found, err = res.ResolveDeletionProtectedDescendants(ctx, otherRG.Annotations[genruntime.ResourceIDAnnotation])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeEmpty())
+
+	found, err = res.ResolveDeletionProtectedDescendants(ctx, "/subscriptions/00000000-0000-0000-0000-000000000000")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(HaveLen(2))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resolver
+
+import (
+	"context"
+
+	"github.com/rotisserie/eris"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+)
+
+var (
+	secretGroupKind    = schema.GroupKind{Kind: "Secret"}
+	configMapGroupKind = schema.GroupKind{Kind: "ConfigMap"}
+)
+
+// checkReferenceGrant returns an error unless a resource in fromNamespace may refer to the target with the specified
+// kind and name. References within a namespace are always allowed, while references to other namespaces must be
+// allowed by a ReferenceGrant in the namespace of the target.
+func (r *Resolver) checkReferenceGrant(
+	ctx context.Context,
+	fromNamespace string,
+	groupKind schema.GroupKind,
+	target types.NamespacedName,
+) error {
+	if target.Namespace == fromNamespace {
+		return nil
+	}
+
+	var grants serviceoperator.ReferenceGrantList
+	err := r.client.List(ctx, &grants, client.InNamespace(target.Namespace))
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// ReferenceGrant CRD is not installed, so nothing is allowed
+			return core.NewReferenceNotGrantedError(fromNamespace, groupKind, target)
+		}
+
+		return eris.Wrapf(err, "listing ReferenceGrants in namespace %q", target.Namespace)
+	}
+
+	for i := range grants.Items {
+		if grants.Items[i].Permits(fromNamespace, groupKind, target.Name) {
+			return nil
+		}
+	}
+
+	return core.NewReferenceNotGrantedError(fromNamespace, groupKind, target)
+}
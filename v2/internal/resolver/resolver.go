@@ -10,6 +10,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
 	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
@@ -30,6 +32,9 @@ type Resolver struct {
 	kubeSecretMapResolver    SecretMapResolver
 	kubeConfigMapResolver    ConfigMapResolver
 	reconciledResourceLookup map[schema.GroupKind]schema.GroupVersionKind
+
+	// deletionProtectedTypes are the types searched by ResolveDeletionProtectedDescendants
+	deletionProtectedTypes []schema.GroupVersionKind
 }
 
 func NewResolver(client kubeclient.Client) *Resolver {
@@ -118,7 +123,12 @@ func (r *Resolver) ResolveResourceReferences(ctx context.Context, metaObject gen
 	// Include the namespace
 	namespacedRefs := make(map[genruntime.NamespacedResourceReference]struct{}, len(refs))
 	for ref := range refs {
-		namespacedRefs[ref.AsNamespacedRef(metaObject.GetNamespace())] = struct{}{}
+		namespacedRef, err := r.namespacedResourceReference(ctx, ref, metaObject.GetNamespace())
+		if err != nil {
+			return genruntime.Resolved[genruntime.ResourceReference, string]{}, err
+		}
+
+		namespacedRefs[namespacedRef] = struct{}{}
 	}
 
 	// resolve them
@@ -159,6 +169,21 @@ func (r *Resolver) ResolveResourceHierarchy(ctx context.Context, obj genruntime.
 
 // ResolveReference resolves a reference, or returns an error if the reference is not pointing to a KubernetesResource
 func (r *Resolver) ResolveReference(ctx context.Context, ref genruntime.NamespacedResourceReference) (genruntime.ARMMetaObject, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"ResolveReference",
+		trace.WithAttributes(
+			tracing.KindKey.String(schema.GroupKind{Group: ref.Group, Kind: ref.Kind}.String()),
+			tracing.NamespaceKey.String(ref.Namespace),
+			tracing.NameKey.String(ref.Name)))
+
+	result, err := r.resolveReference(ctx, ref)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (r *Resolver) resolveReference(ctx context.Context, ref genruntime.NamespacedResourceReference) (genruntime.ARMMetaObject, error) {
 	refGVK, err := r.findGVK(ref)
 	if err != nil {
 		return nil, err
@@ -259,10 +284,11 @@ func (r *Resolver) ResolveOwner(ctx context.Context, obj genruntime.ARMOwnedMeta
 		return OwnerDetailsFromARM(owner.ARMID), nil
 	}
 
-	namespacedRef := genruntime.NamespacedResourceReference{
-		ResourceReference: *owner,
-		Namespace:         obj.GetNamespace(),
+	namespacedRef, err := r.namespacedResourceReference(ctx, *owner, obj.GetNamespace())
+	if err != nil {
+		return OwnerDetails{}, err
 	}
+
 	ownerMeta, err := r.ResolveReference(ctx, namespacedRef)
 	if err != nil {
 		return OwnerDetails{}, err
@@ -271,6 +297,27 @@ func (r *Resolver) ResolveOwner(ctx context.Context, obj genruntime.ARMOwnedMeta
 	return OwnerDetailsFromKubernetes(ownerMeta), nil
 }
 
+// namespacedResourceReference returns the reference in the namespace it refers to, checking that a resource in
+// namespace may refer to it.
+func (r *Resolver) namespacedResourceReference(
+	ctx context.Context,
+	ref genruntime.ResourceReference,
+	namespace string,
+) (genruntime.NamespacedResourceReference, error) {
+	result := ref.AsNamespacedRef(ref.TargetNamespace(namespace))
+	if !ref.IsKubernetesReference() {
+		return result, nil
+	}
+
+	target := types.NamespacedName{Namespace: result.Namespace, Name: ref.Name}
+	err := r.checkReferenceGrant(ctx, namespace, ref.GroupKind(), target)
+	if err != nil {
+		return genruntime.NamespacedResourceReference{}, eris.Wrapf(err, "couldn't resolve reference %s", ref.String())
+	}
+
+	return result, nil
+}
+
 // Scheme returns the current scheme from our client
 func (r *Resolver) Scheme() *runtime.Scheme {
 	return r.client.Scheme()
@@ -310,7 +357,13 @@ func (r *Resolver) ResolveResourceSecretReferences(ctx context.Context, metaObje
 	// Include the namespace
 	namespacedSecretRefs := set.Make[genruntime.NamespacedSecretReference]()
 	for ref := range refs {
-		namespacedSecretRefs.Add(ref.AsNamespacedRef(metaObject.GetNamespace()))
+		namespacedRef := ref.AsNamespacedRef(ref.TargetNamespace(metaObject.GetNamespace()))
+		err = r.checkReferenceGrant(ctx, metaObject.GetNamespace(), secretGroupKind, types.NamespacedName{Namespace: namespacedRef.Namespace, Name: ref.Name})
+		if err != nil {
+			return genruntime.Resolved[genruntime.SecretReference, string]{}, eris.Wrapf(err, "couldn't resolve secret reference %s", namespacedRef.String())
+		}
+
+		namespacedSecretRefs.Add(namespacedRef)
 	}
 
 	// resolve them
@@ -343,7 +396,13 @@ func (r *Resolver) ResolveResourceSecretMapReferences(
 	// Include the namespace
 	namespacedSecretRefs := set.Make[genruntime.NamespacedSecretMapReference]()
 	for ref := range refs {
-		namespacedSecretRefs.Add(ref.AsNamespacedRef(metaObject.GetNamespace()))
+		namespacedRef := ref.AsNamespacedRef(ref.TargetNamespace(metaObject.GetNamespace()))
+		err = r.checkReferenceGrant(ctx, metaObject.GetNamespace(), secretGroupKind, types.NamespacedName{Namespace: namespacedRef.Namespace, Name: ref.Name})
+		if err != nil {
+			return genruntime.Resolved[genruntime.SecretMapReference, map[string]string]{}, eris.Wrapf(err, "couldn't resolve secret collection %s", namespacedRef.String())
+		}
+
+		namespacedSecretRefs.Add(namespacedRef)
 	}
 
 	// resolve them
@@ -373,7 +432,13 @@ func (r *Resolver) ResolveResourceConfigMapReferences(ctx context.Context, metaO
 	// Include the namespace
 	namespacedConfigMapReferences := set.Make[genruntime.NamespacedConfigMapReference]()
 	for ref := range refs {
-		namespacedConfigMapReferences.Add(ref.AsNamespacedRef(metaObject.GetNamespace()))
+		namespacedRef := ref.AsNamespacedRef(ref.TargetNamespace(metaObject.GetNamespace()))
+		err = r.checkReferenceGrant(ctx, metaObject.GetNamespace(), configMapGroupKind, types.NamespacedName{Namespace: namespacedRef.Namespace, Name: ref.Name})
+		if err != nil {
+			return genruntime.Resolved[genruntime.ConfigMapReference, string]{}, eris.Wrapf(err, "couldn't resolve config map reference %s", namespacedRef.String())
+		}
+
+		namespacedConfigMapReferences.Add(namespacedRef)
 	}
 
 	// resolve them
@@ -388,6 +453,20 @@ func (r *Resolver) ResolveResourceConfigMapReferences(ctx context.Context, metaO
 // ResolveAll resolves every reference on the provided genruntime.ARMMetaObject.
 // This includes: owner, all resource references, and all secrets.
 func (r *Resolver) ResolveAll(ctx context.Context, metaObject genruntime.ARMMetaObject) (ResourceHierarchy, genruntime.ConvertToARMResolvedDetails, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"ResolveAll",
+		trace.WithAttributes(
+			tracing.NamespaceKey.String(metaObject.GetNamespace()),
+			tracing.NameKey.String(metaObject.GetName())))
+
+	resourceHierarchy, resolvedDetails, err := r.resolveAll(ctx, metaObject)
+	tracing.End(span, err)
+
+	return resourceHierarchy, resolvedDetails, err
+}
+
+func (r *Resolver) resolveAll(ctx context.Context, metaObject genruntime.ARMMetaObject) (ResourceHierarchy, genruntime.ConvertToARMResolvedDetails, error) {
 	// Resolve the resource hierarchy (owner)
 	resourceHierarchy, err := r.ResolveResourceHierarchy(ctx, metaObject)
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	batch "github.com/Azure/azure-service-operator/v2/api/batch/v1api20210101"
 	mysql "github.com/Azure/azure-service-operator/v2/api/dbformysql/v1api20210501"
 	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
 	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20210401"
 	subscription "github.com/Azure/azure-service-operator/v2/api/subscription/v1api20211001"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
@@ -34,7 +35,10 @@ import (
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
 )
 
-const testNamespace = "testnamespace"
+const (
+	testNamespace  = "testnamespace"
+	otherNamespace = "othernamespace"
+)
 
 func NewKubeClient(s *runtime.Scheme) kubeclient.Client {
 	fakeClient := fake.NewClientBuilder().WithScheme(s).Build()
@@ -412,6 +416,76 @@ func Test_ResolveResourceHierarchy_ReturnsReferenceNotFound(t *testing.T) {
 	g.Expect(eris.Unwrap(err)).To(BeAssignableToTypeOf(&core.ReferenceNotFound{}))
 }
 
+func Test_ResolveResourceHierarchy_OwnerInOtherNamespace_WithoutGrant_ReturnsReferenceNotGranted(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	test, err := testSetup()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	a, b := createResourceGroupRootedResource("myrg", "myresource")
+	a.SetNamespace(otherNamespace)
+	b.(*batch.BatchAccount).Spec.Owner.Namespace = otherNamespace
+
+	err = test.client.Create(ctx, a)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = test.resolver.ResolveResourceHierarchy(ctx, b)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(eris.Unwrap(err)).To(BeAssignableToTypeOf(&core.ReferenceNotGranted{}))
+}
+
+func Test_ResolveResourceHierarchy_OwnerInOtherNamespace_WithGrant_ReturnsOwner(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	test, err := testSetup()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	a, b := createResourceGroupRootedResource("myrg", "myresource")
+	a.SetNamespace(otherNamespace)
+	b.(*batch.BatchAccount).Spec.Owner.Namespace = otherNamespace
+
+	err = test.client.Create(ctx, a)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	grant := createReferenceGrant(otherNamespace, testNamespace, resources.GroupVersion.Group, resolver.ResourceGroupKind, a.GetName())
+	err = test.client.Create(ctx, grant)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	hierarchy, err := test.resolver.ResolveResourceHierarchy(ctx, b)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hierarchy).To(HaveLen(2))
+	g.Expect(hierarchy[0].GetName()).To(Equal(a.GetName()))
+	g.Expect(hierarchy[0].GetNamespace()).To(Equal(otherNamespace))
+}
+
+func Test_ResolveResourceHierarchy_OwnerInOtherNamespace_GrantForOtherName_ReturnsReferenceNotGranted(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	test, err := testSetup()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	a, b := createResourceGroupRootedResource("myrg", "myresource")
+	a.SetNamespace(otherNamespace)
+	b.(*batch.BatchAccount).Spec.Owner.Namespace = otherNamespace
+
+	err = test.client.Create(ctx, a)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	grant := createReferenceGrant(otherNamespace, testNamespace, resources.GroupVersion.Group, resolver.ResourceGroupKind, "otherrg")
+	err = test.client.Create(ctx, grant)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = test.resolver.ResolveResourceHierarchy(ctx, b)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(eris.Unwrap(err)).To(BeAssignableToTypeOf(&core.ReferenceNotGranted{}))
+}
+
 func Test_ResolveReference_FindsReference(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
@@ -705,6 +779,74 @@ func Test_ResolveConfigMaps_ReturnsReferenceNotFound(t *testing.T) {
 	g.Expect(eris.Unwrap(err)).To(BeAssignableToTypeOf(&core.ConfigMapNotFound{}))
 }
 
+func Test_ResolveResourceSecretReferences_SecretInOtherNamespace_RequiresGrant(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	test, err := testSetup()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	secretName := "testsecret"
+	secretKey := "mysecretkey"
+	secretValue := "myPinIs1234"
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: otherNamespace,
+		},
+		Data: map[string][]byte{
+			secretKey: []byte(secretValue),
+		},
+	}
+	err = test.client.Create(ctx, secret)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ref := genruntime.SecretReference{Name: secretName, Key: secretKey, Namespace: otherNamespace}
+	server := &mysql.FlexibleServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myserver",
+			Namespace: testNamespace,
+		},
+		Spec: mysql.FlexibleServer_Spec{
+			AdministratorLoginPassword: &ref,
+		},
+	}
+
+	_, err = test.resolver.ResolveResourceSecretReferences(ctx, server)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(eris.Unwrap(err)).To(BeAssignableToTypeOf(&core.ReferenceNotGranted{}))
+
+	grant := createReferenceGrant(otherNamespace, testNamespace, "", "Secret", "")
+	err = test.client.Create(ctx, grant)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	resolved, err := test.resolver.ResolveResourceSecretReferences(ctx, server)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	actualSecret, err := resolved.Lookup(ref)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(actualSecret).To(Equal(secretValue))
+}
+
+func createReferenceGrant(namespace string, fromNamespace string, group string, kind string, name string) *serviceoperator.ReferenceGrant {
+	return &serviceoperator.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grant",
+			Namespace: namespace,
+		},
+		Spec: serviceoperator.ReferenceGrantSpec{
+			From: []serviceoperator.ReferenceGrantFrom{
+				{Namespace: fromNamespace},
+			},
+			To: []serviceoperator.ReferenceGrantTo{
+				{Group: group, Kind: kind, Name: name},
+			},
+		},
+	}
+}
+
 func createTestScheme() *runtime.Scheme {
 	s := runtime.NewScheme()
 	_ = resources.AddToScheme(s)
@@ -712,6 +854,7 @@ func createTestScheme() *runtime.Scheme {
 	_ = storage.AddToScheme(s)
 	_ = mysql.AddToScheme(s)
 	_ = subscription.AddToScheme(s)
+	_ = serviceoperator.AddToScheme(s)
 	_ = v1.AddToScheme(s)
 
 	return s
@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	batch "github.com/Azure/azure-service-operator/v2/api/batch/v1api20210101"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
@@ -437,3 +438,39 @@ func Test_ResourceHierarchy_GivenTopLevelResourceMissingAzureName_ReturnsError(t
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("empty AzureName"))
 }
+
+func Test_ResourceHierarchy_ResourceLookupByARMID(t *testing.T) {
+	t.Parallel()
+
+	vnetID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg/providers/Microsoft.Network/virtualNetworks/vnet"
+	armIDs := []string{
+		vnetID,
+		vnetID + "/subnets/default",
+		vnetID + "/providers/Microsoft.Authorization/locks/lock",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg/providers/Microsoft.Authorization/locks/lock",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleDefinitions/reader",
+		"/providers/Microsoft.Management/managementGroups/mg",
+	}
+
+	for _, armID := range armIDs {
+		armID := armID
+		t.Run(armID, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			lookup := &serviceoperator.ResourceLookup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "lookup",
+					Namespace: testNamespace,
+				},
+				Spec: serviceoperator.ResourceLookupSpec{
+					ARMID:      armID,
+					APIVersion: "2024-05-01",
+				},
+			}
+
+			hierarchy := resolver.ResourceHierarchy{lookup}
+			g.Expect(hierarchy.FullyQualifiedARMID("00000000-0000-0000-0000-000000000000")).To(Equal(armID))
+		})
+	}
+}
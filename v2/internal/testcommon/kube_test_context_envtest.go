@@ -44,6 +44,7 @@ import (
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/entra"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/generic"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
@@ -261,10 +262,12 @@ func createSharedEnvTest(cfg testConfig, namespaceResources *namespaceResources)
 	positiveConditions := conditions.NewPositiveConditionBuilder(clock.New())
 
 	if cfg.OperatorMode.IncludesWatchers() {
+		resourceResolver := resolver.NewResolver(kubeClient)
 		clientsProvider := &controllers.ClientsProvider{
 			KubeClient:             kubeClient,
 			ARMConnectionFactory:   armClientFactory,
 			EntraConnectionFactory: entraClientFactory,
+			Resolver:               resourceResolver,
 		}
 
 		var objs []*registration.StorageType
@@ -279,6 +282,12 @@ func createSharedEnvTest(cfg testConfig, namespaceResources *namespaceResources)
 			return nil, err
 		}
 
+		err = resourceResolver.IndexDeletionProtectedTypes(mgr.GetScheme(), objs)
+		if err != nil {
+			stopEnvironment()
+			return nil, eris.Wrapf(err, "indexing deletion protected types")
+		}
+
 		err = generic.RegisterAll(
 			mgr,
 			indexer,
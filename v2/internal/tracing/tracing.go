@@ -0,0 +1,118 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+
+	"github.com/Azure/azure-service-operator/v2/internal/version"
+)
+
+const (
+	// tracerName identifies the spans created by the operator
+	tracerName = "github.com/Azure/azure-service-operator/v2"
+
+	// defaultServiceName is used if OTEL_SERVICE_NAME isn't set
+	defaultServiceName = "azure-service-operator"
+)
+
+// Attribute keys used across spans created by the operator
+const (
+	NamespaceKey     = attribute.Key("k8s.namespace.name")
+	NameKey          = attribute.Key("aso.resource.name")
+	KindKey          = attribute.Key("aso.resource.kind")
+	GenerationKey    = attribute.Key("aso.resource.generation")
+	ActionKey        = attribute.Key("aso.action")
+	ResourceIDKey    = attribute.Key("azure.resource.id")
+	CorrelationIDKey = attribute.Key("azure.correlation_request_id")
+	RequestIDKey     = attribute.Key("azure.request_id")
+)
+
+// Tracer returns the tracer used for all spans created by the operator. Until Setup has configured an exporter, spans
+// are not recorded.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName, trace.WithInstrumentationVersion(version.BuildVersion))
+}
+
+// Start starts a span using the operator's tracer.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End records err (if any) on the span, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Enabled returns true if an OTLP endpoint has been configured using the standard OpenTelemetry environment
+// variables, and the SDK hasn't been disabled.
+func Enabled() bool {
+	if disabled, _ := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED")); disabled {
+		return false
+	}
+
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup configures the global tracer provider to export spans using OTLP over gRPC, if tracing is Enabled. The
+// exporter, sampler and resource attributes are configured using the standard OpenTelemetry environment variables
+// (such as OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_TRACES_SAMPLER and OTEL_RESOURCE_ATTRIBUTES).
+// The returned function flushes any buffered spans, and must be called before the process exits.
+func Setup(ctx context.Context, log logr.Logger) (func(context.Context) error, error) {
+	// Always propagate trace context, so that spans from the operator can be joined up with those of other services
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, eris.Wrap(err, "creating OTLP trace exporter")
+	}
+
+	res, err := resource.Merge(
+		resource.NewSchemaless(
+			attribute.String("service.name", defaultServiceName),
+			attribute.String("service.version", version.BuildVersion)),
+		// Values from the environment (OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES) take precedence
+		resource.Environment())
+	if err != nil {
+		return nil, eris.Wrap(err, "creating trace resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.V(Info).Info("OpenTelemetry error", "error", err.Error())
+	}))
+
+	log.V(Status).Info("Exporting traces using OTLP")
+
+	return provider.Shutdown, nil
+}
@@ -26,8 +26,9 @@ import (
 // Note: get with an annotation or property on the operatorSpec.
 
 const (
-	SelfIdent   = "self"
-	SecretIdent = "secret"
+	SelfIdent    = "self"
+	SecretIdent  = "secret"
+	PayloadIdent = "payload"
 )
 
 var evaluator ExpressionEvaluator
@@ -48,6 +49,8 @@ func RegisterEvaluator(e ExpressionEvaluator) {
 // secret parameter (containing the secrets)
 type ExpressionEvaluator interface {
 	CompileAndRun(expression string, self any, secret map[string]string) (*ExpressionResult, error)
+	CompileAndRunPatch(expression string, self any, payload map[string]any) (map[string]any, error)
+	CompileAndRunValidation(expression string, self any) (bool, error)
 	Check(expression string, self any) (*cel.Type, error)
 	FindSecretUsage(expression string, self any) (set.Set[string], error)
 	Start()
@@ -66,6 +69,22 @@ func Cache(cache ProgramCacher) ExpressionEvaluatorOption {
 	}
 }
 
+// PatchCache configures the program cache used for patch expressions, see CompileAndRunPatch.
+func PatchCache(cache ProgramCacher) ExpressionEvaluatorOption {
+	return func(e *expressionEvaluator) (*expressionEvaluator, error) {
+		e.patchCache = cache
+		return e, nil
+	}
+}
+
+// ValidationCache configures the program cache used for validation expressions, see CompileAndRunValidation.
+func ValidationCache(cache ProgramCacher) ExpressionEvaluatorOption {
+	return func(e *expressionEvaluator) (*expressionEvaluator, error) {
+		e.validationCache = cache
+		return e, nil
+	}
+}
+
 // Metrics configures CEL prometheus metrics
 func Metrics(metrics asometrics.CEL) ExpressionEvaluatorOption {
 	return func(e *expressionEvaluator) (*expressionEvaluator, error) {
@@ -85,9 +104,11 @@ func Log(log logr.Logger) ExpressionEvaluatorOption {
 var _ ExpressionEvaluator = &expressionEvaluator{}
 
 type expressionEvaluator struct {
-	programCache ProgramCacher
-	metrics      asometrics.CEL
-	log          logr.Logger
+	programCache    ProgramCacher
+	patchCache      ProgramCacher
+	validationCache ProgramCacher
+	metrics         asometrics.CEL
+	log             logr.Logger
 }
 
 func NewExpressionEvaluator(
@@ -114,17 +135,31 @@ func NewExpressionEvaluator(
 		result.programCache = NewProgramCache(envCache, result.metrics, result.log, Compile)
 	}
 
+	if result.patchCache == nil {
+		envCache := NewEnvCache(result.metrics, result.log, NewPatchEnv)
+		result.patchCache = NewProgramCache(envCache, result.metrics, result.log, CompilePatch)
+	}
+
+	if result.validationCache == nil {
+		envCache := NewEnvCache(result.metrics, result.log, NewEnv)
+		result.validationCache = NewProgramCache(envCache, result.metrics, result.log, CompileValidation)
+	}
+
 	return result, nil
 }
 
 // Start starts the expressionEvaluator.
 func (e *expressionEvaluator) Start() {
 	go e.programCache.Start()
+	go e.patchCache.Start()
+	go e.validationCache.Start()
 }
 
 // Stop stops the expressionEvaluator.
 func (e *expressionEvaluator) Stop() {
 	e.programCache.Stop()
+	e.patchCache.Stop()
+	e.validationCache.Stop()
 }
 
 // NewEnv returns a new cel.Env accepting two parameters:
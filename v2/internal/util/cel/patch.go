@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cel
+
+import (
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// PatchType is the type of the payload variable, and of the result of patch expressions
+var PatchType = cel.MapType(cel.StringType, cel.DynType)
+
+// NewPatchEnv returns a new cel.Env for expressions which patch the ARM payload of a resource. In addition to the
+// variables provided by NewEnv, expressions can use:
+//
+// payload: The ARM payload about to be sent to Azure, as a map.
+func NewPatchEnv(resource reflect.Type) (*cel.Env, error) {
+	env, err := NewEnv(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Extend(cel.Variable(PayloadIdent, PatchType))
+}
+
+// CompilePatch builds the specified patch expression and returns a CompilationResult containing the cel.AST and
+// cel.Program. Patch expressions must return a map with string keys.
+func CompilePatch(env *cel.Env, expression string) (*CompilationResult, error) {
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, eris.Wrapf(iss.Err(), "failed to compile CEL expression: %q", expression)
+	}
+
+	if !isPatchType(ast.OutputType()) {
+		return nil, makeUnexpectedResultError(ast, PatchType)
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to generate program from CEL AST: %q", expression)
+	}
+
+	return &CompilationResult{
+		AST:     ast,
+		Program: program,
+	}, nil
+}
+
+// isPatchType returns true if values of the specified type may be maps with string keys. Dyn is permitted as the
+// result of expressions such as conditionals is often only known at runtime.
+func isPatchType(t *cel.Type) bool {
+	switch t.Kind() {
+	case types.DynKind:
+		return true
+	case types.MapKind:
+		key := t.Parameters()[0]
+		return key.Kind() == types.StringKind || key.Kind() == types.DynKind
+	default:
+		return false
+	}
+}
+
+// CompileAndRunPatch compiles the specified patch expression and returns the resulting patch.
+// expression is a CEL expression that must return a map with string keys.
+// self is the resource being reconciled.
+// payload is the ARM payload of the resource, as a generic JSON value.
+func (e *expressionEvaluator) CompileAndRunPatch(expression string, self any, payload map[string]any) (map[string]any, error) {
+	if self == nil {
+		return nil, eris.New("self cannot be nil")
+	}
+
+	// Cache lookup also compiles and checks the program for errors
+	program, err := e.patchCache.Get(reflect.TypeOf(self), expression)
+	if err != nil {
+		return nil, err
+	}
+
+	input := map[string]any{
+		SelfIdent:    self,
+		SecretIdent:  map[string]string{},
+		PayloadIdent: payload,
+	}
+	out, _, err := program.Program.Eval(input)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to eval CEL expression: %q", expression)
+	}
+
+	// Converting via a JSON value gives us the generic representation used for payloads, with nested maps and lists
+	native, err := out.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, makeUnexpectedResultError(program.AST, PatchType)
+	}
+
+	result, ok := native.(*structpb.Value).AsInterface().(map[string]any)
+	if !ok {
+		return nil, makeUnexpectedResultError(program.AST, PatchType)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cel_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
+)
+
+func Test_CompileAndRunPatch(t *testing.T) {
+	t.Parallel()
+
+	payload := map[string]any{
+		"location": "eastus",
+		"properties": map[string]any{
+			"enabled": true,
+		},
+		"tags": map[string]any{
+			"temp": "yes",
+		},
+	}
+
+	cases := []struct {
+		name        string
+		expression  string
+		expected    map[string]any
+		expectedErr string
+	}{
+		{
+			name:       "constant patch",
+			expression: `{"properties": {"minimumTlsVersion": "TLS1_2"}}`,
+			expected: map[string]any{
+				"properties": map[string]any{"minimumTlsVersion": "TLS1_2"},
+			},
+		},
+		{
+			name:       "patch using self",
+			expression: `{"tags": {"namespace": self.metadata.namespace}}`,
+			expected: map[string]any{
+				"tags": map[string]any{"namespace": "default"},
+			},
+		},
+		{
+			name:       "patch using payload",
+			expression: `payload.properties.enabled ? {"properties": {"publicNetworkAccess": "Disabled"}} : {"properties": {"publicNetworkAccess": "Enabled"}}`,
+			expected: map[string]any{
+				"properties": map[string]any{"publicNetworkAccess": "Disabled"},
+			},
+		},
+		{
+			name:       "patch removing a value",
+			expression: `{"tags": {"temp": null}}`,
+			expected: map[string]any{
+				"tags": map[string]any{"temp": nil},
+			},
+		},
+		{
+			name:        "string result is rejected",
+			expression:  `"TLS1_2"`,
+			expectedErr: `expression "\"TLS1_2\"" must return one of [map(string, dyn)], but was string`,
+		},
+		{
+			name:        "nonexistent property produces parser error",
+			expression:  `{"location": self.this.doesnt.exist}`,
+			expectedErr: "undefined field 'this'",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			evaluator, err := asocel.NewExpressionEvaluator()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			result, err := evaluator.CompileAndRunPatch(c.expression, newSimpleResource(), payload)
+			if c.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.expectedErr)))
+				return // Nothing more to assert
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(c.expected))
+		})
+	}
+}
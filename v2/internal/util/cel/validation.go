@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cel
+
+import (
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/rotisserie/eris"
+)
+
+// CompileValidation builds the specified validation expression and returns a CompilationResult containing the
+// cel.AST and cel.Program. Validation expressions must return a bool.
+func CompileValidation(env *cel.Env, expression string) (*CompilationResult, error) {
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, eris.Wrapf(iss.Err(), "failed to compile CEL expression: %q", expression)
+	}
+
+	err := CheckOutputTypeAllowed(ast, cel.BoolType)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to generate program from CEL AST: %q", expression)
+	}
+
+	return &CompilationResult{
+		AST:     ast,
+		Program: program,
+	}, nil
+}
+
+// CompileAndRunValidation compiles the specified validation expression and returns its result.
+// expression is a CEL expression that must return a bool.
+// self is the resource being validated.
+func (e *expressionEvaluator) CompileAndRunValidation(expression string, self any) (bool, error) {
+	if self == nil {
+		return false, eris.New("self cannot be nil")
+	}
+
+	// Cache lookup also compiles and checks the program for errors
+	program, err := e.validationCache.Get(reflect.TypeOf(self), expression)
+	if err != nil {
+		return false, err
+	}
+
+	input := map[string]any{
+		SelfIdent:   self,
+		SecretIdent: map[string]string{},
+	}
+	out, _, err := program.Program.Eval(input)
+	if err != nil {
+		return false, eris.Wrapf(err, "failed to eval CEL expression: %q", expression)
+	}
+
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, makeUnexpectedResultError(program.AST, cel.BoolType)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cel_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	asocel "github.com/Azure/azure-service-operator/v2/internal/util/cel"
+)
+
+func Test_CompileAndRunValidation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		expression  string
+		expected    bool
+		expectedErr string
+	}{
+		{
+			name:       "allowed location",
+			expression: `self.spec.location in ["eastus", "westus"]`,
+			expected:   true,
+		},
+		{
+			name:       "disallowed location",
+			expression: `self.spec.location in ["westeurope"]`,
+			expected:   false,
+		},
+		{
+			name:       "string function",
+			expression: `!self.spec.location.startsWith("west")`,
+			expected:   true,
+		},
+		{
+			name:        "string result is rejected",
+			expression:  `self.spec.location`,
+			expectedErr: `expression "self.spec.location" must return one of [bool], but was string`,
+		},
+		{
+			name:        "nonexistent property produces parser error",
+			expression:  `self.spec.sku.name == "Standard"`,
+			expectedErr: "undefined field 'sku'",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			evaluator, err := asocel.NewExpressionEvaluator()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			result, err := evaluator.CompileAndRunValidation(c.expression, newSimpleResource())
+			if c.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.expectedErr)))
+				return // Nothing more to assert
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(c.expected))
+		})
+	}
+}
@@ -23,6 +23,10 @@ import (
 // Calculator calculates an interval
 type Calculator interface {
 	NextInterval(req ctrl.Request, result ctrl.Result, err error) (ctrl.Result, error)
+
+	// NextIntervalWithSyncPeriod is the same as NextInterval, except that healthy requests are re-synced after
+	// syncPeriod rather than the configured SyncPeriod. If syncPeriod is nil, healthy requests are not re-synced.
+	NextIntervalWithSyncPeriod(req ctrl.Request, result ctrl.Result, err error, syncPeriod *time.Duration) (ctrl.Result, error)
 }
 
 type CalculatorParameters struct {
@@ -86,6 +90,17 @@ var _ Calculator = &calculator{}
 //  2. Happy-path requests when requeueDelayOverride is not set. These are scenarios where the operator is working
 //     as expected and we're just doing something like polling an async operation.
 func (i *calculator) NextInterval(req ctrl.Request, result ctrl.Result, err error) (ctrl.Result, error) {
+	return i.NextIntervalWithSyncPeriod(req, result, err, i.syncPeriod)
+}
+
+// NextIntervalWithSyncPeriod calculates the next interval for a given request, result, and error, re-syncing
+// healthy requests after syncPeriod. See NextInterval for details.
+func (i *calculator) NextIntervalWithSyncPeriod(
+	req ctrl.Request,
+	result ctrl.Result,
+	err error,
+	syncPeriod *time.Duration,
+) (ctrl.Result, error) {
 	i.failuresLock.Lock()
 	defer i.failuresLock.Unlock()
 
@@ -96,7 +111,7 @@ func (i *calculator) NextInterval(req ctrl.Request, result ctrl.Result, err erro
 	// Happy path
 	if (result == ctrl.Result{}) {
 		// If result is a success, ensure that we requeue for monitoring state in Azure
-		result = i.makeSuccessResult()
+		result = i.makeSuccessResult(syncPeriod)
 	}
 
 	delete(i.failures, req) // On reconcile without an error, forget any previous failures
@@ -167,13 +182,13 @@ func (i *calculator) failureResult(req ctrl.Request, err error) (ctrl.Result, er
 	return ctrl.Result{}, eris.Errorf("Error with severity %q is unexpected", readyErr.Severity)
 }
 
-func (i *calculator) makeSuccessResult() ctrl.Result {
+func (i *calculator) makeSuccessResult(syncPeriod *time.Duration) ctrl.Result {
 	result := ctrl.Result{}
 	// This has a RequeueAfter because we want to force a re-sync at some point in the future in order to catch
 	// potential drift from the state in Azure. Note that we cannot use mgr.Options.SyncPeriod for this because we filter
 	// our events by predicate.GenerationChangedPredicate and the generation will not have changed.
-	if i.syncPeriod != nil {
-		result.RequeueAfter = i.delayWithJitter(*i.syncPeriod)
+	if syncPeriod != nil {
+		result.RequeueAfter = i.delayWithJitter(*syncPeriod)
 	}
 
 	return result
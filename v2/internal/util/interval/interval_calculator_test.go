@@ -82,6 +82,36 @@ func Test_Success_WithSyncPeriod_ReturnsSyncPeriod(t *testing.T) {
 	g.Expect(calc.(*calculator).failures).To(HaveLen(0))
 }
 
+func Test_Success_WithSyncPeriodOverride_ReturnsOverride(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	syncPeriod := 12 * time.Second
+	calc := newCalculator(
+		CalculatorParameters{
+			ErrorBaseDelay:     1 * time.Second,
+			ErrorMaxFastDelay:  5 * time.Second,
+			ErrorMaxSlowDelay:  10 * time.Second,
+			ErrorVerySlowDelay: 20 * time.Second,
+			SyncPeriod:         &syncPeriod,
+		})
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}}
+
+	override := 2 * time.Minute
+	result, err := calc.NextIntervalWithSyncPeriod(req, ctrl.Result{}, nil, &override)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Jitter is 0.25, so the 2m above becomes 90s to 150s
+	g.Expect(result.RequeueAfter >= 90*time.Second).To(BeTrue())
+	g.Expect(result.RequeueAfter <= 150*time.Second).To(BeTrue())
+
+	// A nil override disables re-syncing entirely
+	result, err = calc.NextIntervalWithSyncPeriod(req, ctrl.Result{}, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+}
+
 func Test_Requeue_ReturnsResultUnmodified(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var (
+	stringType    = reflect.TypeOf("")
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// EnumPaths returns the paths (as reported by Compare) of the enum values in obj, so that they can be compared
+// ignoring case. Enums are recognised as values of named string types, as generated for ARM enums.
+// Values within types that provide their own JSON serialization can't be located, so aren't included.
+func EnumPaths(obj any) map[string]bool {
+	result := make(map[string]bool)
+	collectEnumPaths("", reflect.ValueOf(obj), result)
+	return result
+}
+
+func collectEnumPaths(path string, value reflect.Value, result map[string]bool) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return
+	}
+
+	if value.Type().Implements(marshalerType) || reflect.PointerTo(value.Type()).Implements(marshalerType) {
+		// We don't know where its values end up
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		if value.Type() != stringType {
+			result[path] = true
+		}
+
+	case reflect.Struct:
+		collectFieldEnumPaths(path, value, result)
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collectEnumPaths(joinIndex(path, i), value.Index(i), result)
+		}
+
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return
+		}
+
+		iter := value.MapRange()
+		for iter.Next() {
+			collectEnumPaths(joinProperty(path, iter.Key().String()), iter.Value(), result)
+		}
+	}
+}
+
+func collectFieldEnumPaths(path string, value reflect.Value, result map[string]bool) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			if field.Anonymous {
+				// Fields of embedded structs are serialized as if they were our own
+				collectEnumPaths(path, value.Field(i), result)
+				continue
+			}
+
+			name = field.Name
+		}
+
+		collectEnumPaths(joinProperty(path, name), value.Field(i), result)
+	}
+}
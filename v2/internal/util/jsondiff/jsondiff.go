@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// ChangeType describes how a property differs between the desired and actual documents.
+type ChangeType string
+
+const (
+	// ChangeTypeAdd indicates the property is present in the desired document but null in the actual document.
+	ChangeTypeAdd = ChangeType("Add")
+
+	// ChangeTypeAbsent indicates the property is present in the desired document but omitted entirely from the actual
+	// document. ARM never returns write-only properties (such as passwords), so they always differ in this way.
+	ChangeTypeAbsent = ChangeType("Absent")
+
+	// ChangeTypeModify indicates the property is present in both documents, but with different values.
+	ChangeTypeModify = ChangeType("Modify")
+
+	// ChangeTypeRemove indicates the property is present in the actual document, and is being removed from it by
+	// omitting it from the desired document (see Merge and Removals).
+	ChangeTypeRemove = ChangeType("Remove")
+)
+
+// Difference describes a single property where the desired and actual documents disagree.
+type Difference struct {
+	// Path is the JSON path of the property, e.g. properties.subnets[0].name
+	Path string
+
+	// Type describes the kind of change required to move from actual to desired.
+	Type ChangeType
+
+	// Desired is the value of the property in the desired document.
+	Desired any
+
+	// Actual is the value of the property in the actual document, or nil if it was missing.
+	Actual any
+}
+
+// String returns a short description of the difference
+func (d Difference) String() string {
+	return fmt.Sprintf("%s (%s)", d.Path, d.Type)
+}
+
+// ToJSONValue converts the provided object to a generic JSON value (as produced by encoding/json when
+// unmarshalling into an interface{}) so that it can be compared with Compare.
+func ToJSONValue(obj any) (any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, eris.Wrapf(err, "serializing %T to JSON", obj)
+	}
+
+	var result any
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, eris.Wrapf(err, "deserializing %T from JSON", obj)
+	}
+
+	return result, nil
+}
+
+// Compare returns the differences between the desired and actual JSON values, sorted by path.
+// Only properties present in desired are considered; properties present only in actual are assumed to have been
+// defaulted or computed by the server (e.g. id, etag, provisioningState) and are ignored. Arrays are compared
+// element by element and must have the same length.
+// ARM normalizes the case of some values, so resource IDs, locations and the enums at enumPaths (see EnumPaths) are
+// compared ignoring case, and locations also ignoring spaces (so that "West US" matches "westus").
+// Both desired and actual are expected to be generic JSON values, see ToJSONValue.
+func Compare(desired any, actual any, enumPaths map[string]bool) []Difference {
+	c := comparer{
+		enumPaths: enumPaths,
+	}
+
+	c.compare("", desired, actual, true)
+	result := c.differences
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
+
+// Paths returns the paths of the provided differences
+func Paths(differences []Difference) []string {
+	result := make([]string, 0, len(differences))
+	for _, d := range differences {
+		result = append(result, d.Path)
+	}
+
+	return result
+}
+
+// Without returns the provided differences, excluding any of the specified type
+func Without(differences []Difference, changeType ChangeType) []Difference {
+	var result []Difference
+	for _, d := range differences {
+		if d.Type != changeType {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
+
+type comparer struct {
+	enumPaths   map[string]bool
+	differences []Difference
+}
+
+func (c *comparer) compare(path string, desired any, actual any, present bool) {
+	if desired == nil {
+		// Nothing desired, nothing to compare
+		return
+	}
+
+	if !present {
+		c.differences = append(c.differences, Difference{
+			Path:    path,
+			Type:    ChangeTypeAbsent,
+			Desired: desired,
+		})
+		return
+	}
+
+	switch d := desired.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			c.differences = append(c.differences, newDifference(path, desired, actual))
+			return
+		}
+
+		for key, desiredValue := range d {
+			actualValue, ok := a[key]
+			c.compare(joinProperty(path, key), desiredValue, actualValue, ok)
+		}
+
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(d) {
+			c.differences = append(c.differences, newDifference(path, desired, actual))
+			return
+		}
+
+		for i := range d {
+			c.compare(joinIndex(path, i), d[i], a[i], true)
+		}
+
+	default:
+		if !c.equal(path, desired, actual) {
+			c.differences = append(c.differences, newDifference(path, desired, actual))
+		}
+	}
+}
+
+// equal returns true if the desired and actual values at path are the same, allowing for values whose case ARM
+// normalizes.
+func (c *comparer) equal(path string, desired any, actual any) bool {
+	if reflect.DeepEqual(desired, actual) {
+		return true
+	}
+
+	d, ok := desired.(string)
+	if !ok {
+		return false
+	}
+
+	a, ok := actual.(string)
+	if !ok {
+		return false
+	}
+
+	property := lastProperty(path)
+	switch {
+	case property == "location":
+		return strings.EqualFold(strings.ReplaceAll(d, " ", ""), strings.ReplaceAll(a, " ", ""))
+	case c.enumPaths[path], isIDProperty(property), isResourceID(d):
+		return strings.EqualFold(d, a)
+	default:
+		return false
+	}
+}
+
+// lastProperty returns the name of the property at the end of path, ignoring any array index
+func lastProperty(path string) string {
+	if i := strings.IndexByte(path, '['); i >= 0 {
+		path = path[:i]
+	}
+
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		path = path[i+1:]
+	}
+
+	return path
+}
+
+// isIDProperty returns true if the property holds an identifier, such as a resource ID, tenant ID or principal ID,
+// which ARM compares (and may return) without regard to case.
+func isIDProperty(property string) bool {
+	return property == "id" || strings.HasSuffix(property, "Id") || strings.HasSuffix(property, "ID")
+}
+
+// isResourceID returns true if the value is an ARM resource ID
+func isResourceID(value string) bool {
+	return len(value) > len("/subscriptions/") && strings.EqualFold(value[:len("/subscriptions/")], "/subscriptions/")
+}
+
+func newDifference(path string, desired any, actual any) Difference {
+	changeType := ChangeTypeModify
+	if actual == nil {
+		changeType = ChangeTypeAdd
+	}
+
+	return Difference{
+		Path:    path,
+		Type:    changeType,
+		Desired: desired,
+		Actual:  actual,
+	}
+}
+
+func joinIndex(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+func joinProperty(path string, property string) string {
+	if path == "" {
+		return property
+	}
+
+	return strings.Join([]string{path, property}, ".")
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompare_GivenDocuments_ReturnsExpectedPaths(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		desired  string
+		actual   string
+		expected []string
+	}{
+		{
+			"Identical documents have no differences",
+			`{"location": "westus", "properties": {"enabled": true}}`,
+			`{"location": "westus", "properties": {"enabled": true}}`,
+			[]string{},
+		},
+		{
+			"Server-populated properties are ignored",
+			`{"location": "westus"}`,
+			`{"location": "westus", "id": "/subscriptions/123", "properties": {"provisioningState": "Succeeded"}}`,
+			[]string{},
+		},
+		{
+			"Changed value is reported",
+			`{"location": "westus", "properties": {"minimumTlsVersion": "TLS1_2"}}`,
+			`{"location": "westus", "properties": {"minimumTlsVersion": "TLS1_0"}}`,
+			[]string{"properties.minimumTlsVersion"},
+		},
+		{
+			"Missing value is reported",
+			`{"tags": {"owner": "platform", "env": "prod"}}`,
+			`{"tags": {"owner": "platform"}}`,
+			[]string{"tags.env"},
+		},
+		{
+			"Multiple differences are sorted",
+			`{"tags": {"b": "2", "a": "1"}}`,
+			`{"tags": {}}`,
+			[]string{"tags.a", "tags.b"},
+		},
+		{
+			"Array elements are compared individually",
+			`{"properties": {"subnets": [{"name": "a"}, {"name": "b"}]}}`,
+			`{"properties": {"subnets": [{"name": "a", "id": "x"}, {"name": "c", "id": "y"}]}}`,
+			[]string{"properties.subnets[1].name"},
+		},
+		{
+			"Arrays with different lengths are reported as a whole",
+			`{"properties": {"addressPrefixes": ["10.0.0.0/16", "10.1.0.0/16"]}}`,
+			`{"properties": {"addressPrefixes": ["10.0.0.0/16"]}}`,
+			[]string{"properties.addressPrefixes"},
+		},
+		{
+			"Numbers are compared by value",
+			`{"sku": {"capacity": 2}}`,
+			`{"sku": {"capacity": 2.0}}`,
+			[]string{},
+		},
+		{
+			"Location is compared ignoring case and spaces",
+			`{"location": "West US"}`,
+			`{"location": "westus"}`,
+			[]string{},
+		},
+		{
+			"Different location is reported",
+			`{"location": "westus"}`,
+			`{"location": "westus2"}`,
+			[]string{"location"},
+		},
+		{
+			"IDs are compared ignoring case",
+			`{"properties": {"subnet": {"id": "/subscriptions/123/resourceGroups/MyRG"}, "tenantId": "ABC", "principalID": "DEF"}}`,
+			`{"properties": {"subnet": {"id": "/subscriptions/123/resourcegroups/myrg"}, "tenantId": "abc", "principalID": "def"}}`,
+			[]string{},
+		},
+		{
+			"Resource IDs are compared ignoring case",
+			`{"properties": {"scopes": ["/subscriptions/123/resourceGroups/MyRG"]}}`,
+			`{"properties": {"scopes": ["/SUBSCRIPTIONS/123/resourcegroups/myrg"]}}`,
+			[]string{},
+		},
+		{
+			"Other strings are compared exactly",
+			`{"tags": {"owner": "Platform"}, "properties": {"displayName": "/Subscriptions"}}`,
+			`{"tags": {"owner": "platform"}, "properties": {"displayName": "/subscriptions"}}`,
+			[]string{"properties.displayName", "tags.owner"},
+		},
+		{
+			"Null desired values are ignored",
+			`{"properties": {"description": null}}`,
+			`{"properties": {"description": "hello"}}`,
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			var desired any
+			var actual any
+			g.Expect(json.Unmarshal([]byte(c.desired), &desired)).To(Succeed())
+			g.Expect(json.Unmarshal([]byte(c.actual), &actual)).To(Succeed())
+
+			differences := Compare(desired, actual, nil)
+			g.Expect(Paths(differences)).To(Equal(c.expected))
+		})
+	}
+}
+
+type enumTestSpec struct {
+	Properties *enumTestProperties `json:"properties,omitempty"`
+}
+
+type enumTestProperties struct {
+	MinimumTlsVersion *enumTestTlsVersion  `json:"minimumTlsVersion,omitempty"`
+	TlsVersions       []enumTestTlsVersion `json:"tlsVersions,omitempty"`
+	Description       string               `json:"description,omitempty"`
+}
+
+type enumTestTlsVersion string
+
+func TestCompare_GivenEnumPaths_ComparesEnumsIgnoringCase(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tls := enumTestTlsVersion("TLS1_2")
+	spec := &enumTestSpec{
+		Properties: &enumTestProperties{
+			MinimumTlsVersion: &tls,
+			TlsVersions:       []enumTestTlsVersion{"TLS1_2", "TLS1_3"},
+			Description:       "Primary",
+		},
+	}
+
+	desired, err := ToJSONValue(spec)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	enumPaths := EnumPaths(spec)
+	g.Expect(enumPaths).To(Equal(map[string]bool{
+		"properties.minimumTlsVersion": true,
+		"properties.tlsVersions[0]":    true,
+		"properties.tlsVersions[1]":    true,
+	}))
+
+	var actual any
+	g.Expect(json.Unmarshal(
+		[]byte(`{"properties": {"minimumTlsVersion": "Tls1_2", "tlsVersions": ["tls1_2", "TLS1_3"], "description": "primary"}}`),
+		&actual)).To(Succeed())
+
+	// The enums differ only in case, but the description isn't an enum
+	g.Expect(Paths(Compare(desired, actual, enumPaths))).To(Equal([]string{"properties.description"}))
+
+	// Without knowing where the enums are, they're compared exactly
+	g.Expect(Paths(Compare(desired, actual, nil))).To(Equal([]string{
+		"properties.description",
+		"properties.minimumTlsVersion",
+		"properties.tlsVersions[0]",
+	}))
+}
+
+func TestCompare_GivenNullProperty_ReportsAdd(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	desired := map[string]any{"properties": map[string]any{"enabled": true}}
+	actual := map[string]any{"properties": map[string]any{"enabled": nil}}
+
+	differences := Compare(desired, actual, nil)
+	g.Expect(differences).To(HaveLen(1))
+	g.Expect(differences[0].Type).To(Equal(ChangeTypeAdd))
+	g.Expect(differences[0].Desired).To(Equal(true))
+	g.Expect(differences[0].Actual).To(BeNil())
+}
+
+func TestCompare_GivenOmittedProperty_ReportsAbsent(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	desired := map[string]any{"properties": map[string]any{"administratorLoginPassword": "hunter2"}}
+	actual := map[string]any{"properties": map[string]any{}}
+
+	differences := Compare(desired, actual, nil)
+	g.Expect(differences).To(HaveLen(1))
+	g.Expect(differences[0].Path).To(Equal("properties.administratorLoginPassword"))
+	g.Expect(differences[0].Type).To(Equal(ChangeTypeAbsent))
+	g.Expect(Without(differences, ChangeTypeAbsent)).To(BeEmpty())
+}
+
+func TestToJSONValue_GivenStruct_ReturnsGenericValue(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	type sku struct {
+		Name     string `json:"name"`
+		Capacity int    `json:"capacity,omitempty"`
+	}
+
+	value, err := ToJSONValue(sku{Name: "Standard", Capacity: 3})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(value).To(Equal(map[string]any{"name": "Standard", "capacity": float64(3)}))
+}
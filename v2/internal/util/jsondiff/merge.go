@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Merge updates desired with the properties of live that were set by someone else, at each of the specified paths,
+// and returns it. Paths are dotted property names such as tags or properties.networkAcls; array elements can't be
+// addressed. lastApplied records what was previously sent at each path (see Applied), and is how properties set by
+// someone else are told apart from properties we've stopped setting.
+//
+// At each path:
+//   - If live has an object, and desired has either an object or nothing, keys missing from desired are kept unless
+//     they were previously applied.
+//   - Otherwise, if desired has no value, the live value is kept unless one was previously applied.
+//
+// Also returns the paths of the properties in live that are being removed because they're no longer desired.
+// desired is modified in place; all values are expected to be generic JSON values, see ToJSONValue.
+func Merge(desired any, live any, lastApplied map[string][]string, paths []string) (any, []string) {
+	root, ok := desired.(map[string]any)
+	if !ok {
+		return desired, nil
+	}
+
+	var removed []string
+	for _, path := range paths {
+		liveValue, hasLive := lookup(live, path)
+		if !hasLive || liveValue == nil {
+			continue
+		}
+
+		desiredValue, hasDesired := lookup(root, path)
+		appliedKeys, wasApplied := lastApplied[path]
+
+		liveMap, liveIsMap := liveValue.(map[string]any)
+		desiredMap, desiredIsMap := desiredValue.(map[string]any)
+		if liveIsMap && (desiredIsMap || !hasDesired) {
+			merged := make(map[string]any, len(desiredMap)+len(liveMap))
+			for key, value := range desiredMap {
+				merged[key] = value
+			}
+
+			for key, value := range liveMap {
+				if _, ok := merged[key]; ok {
+					continue
+				}
+
+				if slices.Contains(appliedKeys, key) {
+					removed = append(removed, joinProperty(path, key))
+					continue
+				}
+
+				merged[key] = value
+			}
+
+			if hasDesired || len(merged) > 0 {
+				assign(root, path, merged)
+			}
+
+			continue
+		}
+
+		if hasDesired {
+			continue
+		}
+
+		if wasApplied {
+			removed = append(removed, path)
+			continue
+		}
+
+		assign(root, path, liveValue)
+	}
+
+	sort.Strings(removed)
+	return root, removed
+}
+
+// Removals returns the differences describing the removal of the properties at the specified dotted paths (as
+// returned by Merge) from actual, sorted by path. Paths without a value in actual are skipped.
+func Removals(actual any, paths []string) []Difference {
+	result := make([]Difference, 0, len(paths))
+	for _, path := range paths {
+		value, ok := lookup(actual, path)
+		if !ok {
+			continue
+		}
+
+		result = append(result, Difference{
+			Path:   path,
+			Type:   ChangeTypeRemove,
+			Actual: value,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
+
+// Applied returns a record of what desired sets at each of the specified paths, keyed by path: the names of its
+// properties where the value is an object, and an empty list otherwise. Values themselves aren't recorded, as they may
+// be secrets. Paths without a value are omitted. The result is suitable for passing to Merge as lastApplied, once the
+// values have been sent.
+func Applied(desired any, paths []string) map[string][]string {
+	result := make(map[string][]string, len(paths))
+	for _, path := range paths {
+		value, ok := lookup(desired, path)
+		if !ok || value == nil {
+			continue
+		}
+
+		keys := []string{}
+		if m, isMap := value.(map[string]any); isMap {
+			for key := range m {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+		}
+
+		result[path] = keys
+	}
+
+	return result
+}
+
+// lookup returns the value at the specified dotted path, if present
+func lookup(value any, path string) (any, bool) {
+	current := value
+	for _, property := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = m[property]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// assign sets the value at the specified dotted path, creating any missing parent objects. Nothing is changed if a
+// parent exists but isn't an object.
+func assign(root map[string]any, path string, value any) {
+	properties := strings.Split(path, ".")
+	current := root
+	for _, property := range properties[:len(properties)-1] {
+		next, ok := current[property]
+		if !ok || next == nil {
+			next = map[string]any{}
+			current[property] = next
+		}
+
+		m, ok := next.(map[string]any)
+		if !ok {
+			return
+		}
+
+		current = m
+	}
+
+	current[properties[len(properties)-1]] = value
+}
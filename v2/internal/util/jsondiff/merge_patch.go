@@ -0,0 +1,33 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+// ApplyMergePatch applies a JSON merge patch (RFC 7386) to target, returning the result. Objects in the patch are
+// merged recursively, a null value removes the property, and any other value replaces the property entirely
+// (including arrays). target may be modified in place; both values are expected to be generic JSON values, see
+// ToJSONValue.
+func ApplyMergePatch(target any, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetMap, ok := target.(map[string]any)
+	if !ok {
+		targetMap = make(map[string]any, len(patchMap))
+	}
+
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+			continue
+		}
+
+		targetMap[key] = ApplyMergePatch(targetMap[key], value)
+	}
+
+	return targetMap
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyMergePatch_GivenPatch_ReturnsExpectedDocument(t *testing.T) {
+	t.Parallel()
+
+	// Cases from the examples in RFC 7386, Appendix A
+	cases := []struct {
+		name     string
+		target   string
+		patch    string
+		expected string
+	}{
+		{"Replace value", `{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{"Add value", `{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{"Remove value", `{"a": "b"}`, `{"a": null}`, `{}`},
+		{"Remove one of several", `{"a": "b", "b": "c"}`, `{"a": null}`, `{"b": "c"}`},
+		{"Replace array", `{"a": ["b"]}`, `{"a": "c"}`, `{"a": "c"}`},
+		{"Replace with array", `{"a": "c"}`, `{"a": ["b"]}`, `{"a": ["b"]}`},
+		{"Nested merge", `{"a": {"b": "c"}}`, `{"a": {"b": "d", "c": null}}`, `{"a": {"b": "d"}}`},
+		{"Arrays are not merged", `{"a": [{"b": "c"}]}`, `{"a": [1]}`, `{"a": [1]}`},
+		{"Object replaces scalar", `{"e": null}`, `{"a": 1}`, `{"e": null, "a": 1}`},
+		{"Nested object is created", `{}`, `{"a": {"bb": {"ccc": null}}}`, `{"a": {"bb": {}}}`},
+		{"Empty patch", `{"a": "b"}`, `{}`, `{"a": "b"}`},
+		{"Non-object patch replaces", `{"a": "b"}`, `["c"]`, `["c"]`},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			var target, patch, expected any
+			g.Expect(json.Unmarshal([]byte(c.target), &target)).To(Succeed())
+			g.Expect(json.Unmarshal([]byte(c.patch), &patch)).To(Succeed())
+			g.Expect(json.Unmarshal([]byte(c.expected), &expected)).To(Succeed())
+
+			g.Expect(ApplyMergePatch(target, patch)).To(Equal(expected))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMerge_GivenDocuments_ReturnsExpectedPayload(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name            string
+		desired         string
+		live            string
+		lastApplied     string
+		paths           []string
+		expected        string
+		expectedRemoved []string
+	}{
+		{
+			"Tags set by others are kept",
+			`{"tags": {"owner": "aso"}}`,
+			`{"tags": {"owner": "someone", "policy": "required"}}`,
+			`{}`,
+			[]string{"tags"},
+			`{"tags": {"owner": "aso", "policy": "required"}}`,
+			nil,
+		},
+		{
+			"Tags we stopped setting are removed",
+			`{"tags": {"owner": "aso"}}`,
+			`{"tags": {"owner": "aso", "env": "prod", "policy": "required"}}`,
+			`{"tags": ["env", "owner"]}`,
+			[]string{"tags"},
+			`{"tags": {"owner": "aso", "policy": "required"}}`,
+			[]string{"tags.env"},
+		},
+		{
+			"Tags are kept when none are desired",
+			`{"location": "westus"}`,
+			`{"location": "westus", "tags": {"policy": "required"}}`,
+			`{}`,
+			[]string{"tags"},
+			`{"location": "westus", "tags": {"policy": "required"}}`,
+			nil,
+		},
+		{
+			"Nested value set by others is kept",
+			`{"properties": {"enabled": true}}`,
+			`{"properties": {"enabled": true, "networkAcls": {"defaultAction": "Deny"}}}`,
+			`{}`,
+			[]string{"properties.networkAcls"},
+			`{"properties": {"enabled": true, "networkAcls": {"defaultAction": "Deny"}}}`,
+			nil,
+		},
+		{
+			"Scalar value we stopped setting is removed",
+			`{"properties": {}}`,
+			`{"properties": {"minimumTlsVersion": "TLS1_2"}}`,
+			`{"properties.minimumTlsVersion": []}`,
+			[]string{"properties.minimumTlsVersion"},
+			`{"properties": {}}`,
+			[]string{"properties.minimumTlsVersion"},
+		},
+		{
+			"Desired scalar value wins",
+			`{"properties": {"minimumTlsVersion": "TLS1_2"}}`,
+			`{"properties": {"minimumTlsVersion": "TLS1_0"}}`,
+			`{}`,
+			[]string{"properties.minimumTlsVersion"},
+			`{"properties": {"minimumTlsVersion": "TLS1_2"}}`,
+			nil,
+		},
+		{
+			"Paths not listed are not merged",
+			`{"tags": {"owner": "aso"}}`,
+			`{"tags": {"policy": "required"}, "properties": {"enabled": false}}`,
+			`{}`,
+			nil,
+			`{"tags": {"owner": "aso"}}`,
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			var desired, live, expected any
+			var lastApplied map[string][]string
+			g.Expect(json.Unmarshal([]byte(c.desired), &desired)).To(Succeed())
+			g.Expect(json.Unmarshal([]byte(c.live), &live)).To(Succeed())
+			g.Expect(json.Unmarshal([]byte(c.lastApplied), &lastApplied)).To(Succeed())
+			g.Expect(json.Unmarshal([]byte(c.expected), &expected)).To(Succeed())
+
+			merged, removed := Merge(desired, live, lastApplied, c.paths)
+			g.Expect(merged).To(Equal(expected))
+			g.Expect(removed).To(Equal(c.expectedRemoved))
+		})
+	}
+}
+
+func TestApplied_GivenPaths_RecordsKeysWithoutValues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	desired := map[string]any{
+		"tags":       map[string]any{"owner": "aso", "env": "prod"},
+		"properties": map[string]any{"administratorLoginPassword": "hunter2"},
+	}
+
+	applied := Applied(desired, []string{"tags", "properties.administratorLoginPassword", "properties.networkAcls"})
+	g.Expect(applied).To(Equal(map[string][]string{
+		"tags":                                  {"env", "owner"},
+		"properties.administratorLoginPassword": {},
+	}))
+}
+
+func TestRemovals_GivenRemovedPaths_RecordsLiveValues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	live := map[string]any{
+		"tags":       map[string]any{"owner": "aso", "env": "prod"},
+		"properties": map[string]any{"publicNetworkAccess": "Enabled"},
+	}
+
+	removals := Removals(live, []string{"tags.env", "properties.publicNetworkAccess", "properties.networkAcls"})
+	g.Expect(removals).To(Equal([]Difference{
+		{Path: "properties.publicNetworkAccess", Type: ChangeTypeRemove, Actual: "Enabled"},
+		{Path: "tags.env", Type: ChangeTypeRemove, Actual: "prod"},
+	}))
+}
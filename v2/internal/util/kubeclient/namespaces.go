@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package kubeclient
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type namespacesKey struct{}
+
+// WithNamespaces returns a context in which GetNamespace fetches each namespace at most once. A reconcile uses this so
+// that the features taking defaults from the namespace of a resource (sync period, tags, maintenance windows, merge
+// paths and credentials) share a single lookup. The context must not be shared between goroutines.
+func WithNamespaces(ctx context.Context) context.Context {
+	return context.WithValue(ctx, namespacesKey{}, make(map[string]*v1.Namespace))
+}
+
+// GetNamespace returns the namespace with the specified name. If ctx was returned by WithNamespaces, a namespace
+// fetched earlier is reused. Errors are returned unwrapped, so callers can check for NotFound.
+func GetNamespace(ctx context.Context, reader client.Reader, name string) (*v1.Namespace, error) {
+	namespaces, shared := ctx.Value(namespacesKey{}).(map[string]*v1.Namespace)
+	if namespace, ok := namespaces[name]; ok {
+		return namespace, nil
+	}
+
+	namespace := &v1.Namespace{}
+	err := reader.Get(ctx, types.NamespacedName{Name: name}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if shared {
+		namespaces[name] = namespace
+	}
+
+	return namespace, nil
+}
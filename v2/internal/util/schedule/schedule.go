@@ -0,0 +1,382 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// Schedule is a recurring set of instants, at minute granularity, parsed from either a cron expression or an
+// RRULE (RFC 5545) recurrence rule.
+type Schedule struct {
+	minutes  uint64 // bits 0-59
+	hours    uint64 // bits 0-23
+	days     uint64 // bits 1-31
+	months   uint64 // bits 1-12
+	weekdays uint64 // bits 0-6, Sunday is 0
+
+	// anyDay is true if a day need only match one of days or weekdays (cron semantics, when both are restricted),
+	// rather than both (RRULE semantics)
+	anyDay bool
+
+	location *time.Location
+}
+
+// maxSearchYears bounds how far ahead Next will look for a matching instant, so that schedules which can never
+// match (e.g. 30th of February) don't loop forever.
+const maxSearchYears = 5
+
+// Parse parses a schedule from the provided specification, which may be either a standard five field cron
+// expression (minute, hour, day of month, month, day of week), or an RRULE such as
+// "FREQ=WEEKLY;BYDAY=SA,SU;BYHOUR=22". Schedules are evaluated in UTC unless the specification is prefixed with a
+// time zone, e.g. "TZ=Europe/London 0 22 * * SAT".
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	location := time.UTC
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(zone, "=")
+
+		var err error
+		location, err = time.LoadLocation(name)
+		if err != nil {
+			return nil, eris.Wrapf(err, "loading time zone %q", name)
+		}
+
+		spec = strings.TrimSpace(rest)
+	}
+
+	var result *Schedule
+	var err error
+	if strings.HasPrefix(spec, "RRULE:") || strings.HasPrefix(spec, "FREQ=") {
+		result, err = parseRRule(strings.TrimPrefix(spec, "RRULE:"))
+	} else {
+		result, err = parseCron(spec)
+	}
+
+	if err != nil {
+		return nil, eris.Wrapf(err, "parsing schedule %q", spec)
+	}
+
+	result.location = location
+	return result, nil
+}
+
+// Next returns the first instant in the schedule strictly after t, or the zero time if there is none within the
+// next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	original := t.Location()
+	t = t.In(s.location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, s.location).Add(time.Minute)
+
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	for t.Before(limit) {
+		if !has(s.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+
+		if !has(s.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+
+		if !has(s.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t.In(original)
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day := has(s.days, t.Day())
+	weekday := has(s.weekdays, int(t.Weekday()))
+	if s.anyDay {
+		return day || weekday
+	}
+
+	return day && weekday
+}
+
+/*
+ * Cron
+ */
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var weekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+func parseCron(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, eris.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), but found %d", len(fields))
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "minute")
+	}
+
+	hours, err := parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "hour")
+	}
+
+	days, err := parseCronField(fields[2], 1, 31, nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "day of month")
+	}
+
+	months, err := parseCronField(fields[3], 1, 12, monthNames)
+	if err != nil {
+		return nil, eris.Wrap(err, "month")
+	}
+
+	// Both 0 and 7 are Sunday
+	weekdays, err := parseCronField(fields[4], 0, 7, weekdayNames)
+	if err != nil {
+		return nil, eris.Wrap(err, "day of week")
+	}
+
+	if has(weekdays, 7) {
+		weekdays |= 1
+	}
+
+	// As with standard cron, if both day of month and day of week are restricted, a day matching either will do
+	daysRestricted := !isWildcard(fields[2])
+	weekdaysRestricted := !isWildcard(fields[4])
+
+	return &Schedule{
+		minutes:  minutes,
+		hours:    hours,
+		days:     days,
+		months:   months,
+		weekdays: weekdays,
+		anyDay:   daysRestricted && weekdaysRestricted,
+	}, nil
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseCronField parses a comma separated list of values, ranges (a-b) and steps (*/n or a-b/n)
+func parseCronField(field string, min int, max int, names map[string]int) (uint64, error) {
+	var result uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, eris.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		var low, high int
+		if isWildcard(rng) {
+			low, high = min, max
+		} else {
+			lowStr, highStr, isRange := strings.Cut(rng, "-")
+
+			var err error
+			low, err = parseCronValue(lowStr, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+
+			high = low
+			if isRange {
+				high, err = parseCronValue(highStr, min, max, names)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = max
+			}
+
+			if high < low {
+				return 0, eris.Errorf("invalid range %q", rng)
+			}
+		}
+
+		for i := low; i <= high; i += step {
+			result |= 1 << uint(i)
+		}
+	}
+
+	return result, nil
+}
+
+func parseCronValue(value string, min int, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, eris.Errorf("invalid value %q", value)
+	}
+
+	if result < min || result > max {
+		return 0, eris.Errorf("value %d out of range [%d, %d]", result, min, max)
+	}
+
+	return result, nil
+}
+
+/*
+ * RRULE
+ */
+
+var rruleWeekdays = map[string]int{
+	"SU": 0, "MO": 1, "TU": 2, "WE": 3, "TH": 4, "FR": 5, "SA": 6,
+}
+
+// parseRRule parses the subset of RFC 5545 recurrence rules that can be expressed without a start date: FREQ of
+// DAILY, WEEKLY, MONTHLY or YEARLY, along with the BYMONTH, BYMONTHDAY, BYDAY, BYHOUR and BYMINUTE parts.
+// BYHOUR and BYMINUTE default to zero (midnight).
+func parseRRule(spec string) (*Schedule, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(spec, ";") {
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, eris.Errorf("invalid rule part %q", part)
+		}
+
+		parts[strings.ToUpper(name)] = strings.ToUpper(value)
+	}
+
+	result := &Schedule{
+		minutes:  1,
+		hours:    1,
+		days:     bitRange(1, 31),
+		months:   bitRange(1, 12),
+		weekdays: bitRange(0, 6),
+	}
+
+	for name, value := range parts {
+		var err error
+		switch name {
+		case "FREQ":
+			// Handled below
+		case "BYMINUTE":
+			result.minutes, err = parseRRuleNumbers(value, 0, 59)
+		case "BYHOUR":
+			result.hours, err = parseRRuleNumbers(value, 0, 23)
+		case "BYMONTHDAY":
+			result.days, err = parseRRuleNumbers(value, 1, 31)
+		case "BYMONTH":
+			result.months, err = parseRRuleNumbers(value, 1, 12)
+		case "BYDAY":
+			result.weekdays, err = parseRRuleWeekdays(value)
+		default:
+			err = eris.Errorf("%s is not supported", name)
+		}
+
+		if err != nil {
+			return nil, eris.Wrap(err, name)
+		}
+	}
+
+	// Without a start date, coarser frequencies need to say which days they apply to
+	switch parts["FREQ"] {
+	case "DAILY":
+	case "WEEKLY":
+		if _, ok := parts["BYDAY"]; !ok {
+			return nil, eris.New("FREQ=WEEKLY requires BYDAY")
+		}
+	case "MONTHLY":
+		_, hasDay := parts["BYDAY"]
+		_, hasMonthDay := parts["BYMONTHDAY"]
+		if !hasDay && !hasMonthDay {
+			return nil, eris.New("FREQ=MONTHLY requires BYMONTHDAY or BYDAY")
+		}
+	case "YEARLY":
+		if _, ok := parts["BYMONTH"]; !ok {
+			return nil, eris.New("FREQ=YEARLY requires BYMONTH")
+		}
+	case "":
+		return nil, eris.New("FREQ is required")
+	default:
+		return nil, eris.Errorf("FREQ=%s is not supported", parts["FREQ"])
+	}
+
+	return result, nil
+}
+
+func parseRRuleNumbers(value string, min int, max int) (uint64, error) {
+	var result uint64
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, eris.Errorf("invalid value %q", v)
+		}
+
+		if n < min || n > max {
+			return 0, eris.Errorf("value %d out of range [%d, %d]", n, min, max)
+		}
+
+		result |= 1 << uint(n)
+	}
+
+	return result, nil
+}
+
+func parseRRuleWeekdays(value string) (uint64, error) {
+	var result uint64
+	for _, v := range strings.Split(value, ",") {
+		n, ok := rruleWeekdays[v]
+		if !ok {
+			return 0, eris.Errorf("invalid weekday %q", v)
+		}
+
+		result |= 1 << uint(n)
+	}
+
+	return result, nil
+}
+
+/*
+ * Helpers
+ */
+
+func has(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
+
+func bitRange(low int, high int) uint64 {
+	var result uint64
+	for i := low; i <= high; i++ {
+		result |= 1 << uint(i)
+	}
+
+	return result
+}
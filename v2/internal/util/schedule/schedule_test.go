@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// 2024-06-05 was a Wednesday
+var wednesday = time.Date(2024, 6, 5, 10, 30, 0, 0, time.UTC)
+
+func TestSchedule_Next_GivenSpec_ReturnsExpectedTime(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"Every minute", "* * * * *", wednesday, time.Date(2024, 6, 5, 10, 31, 0, 0, time.UTC)},
+		{"Later today", "0 22 * * *", wednesday, time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC)},
+		{"Tomorrow", "0 9 * * *", wednesday, time.Date(2024, 6, 6, 9, 0, 0, 0, time.UTC)},
+		{"Strictly after", "30 10 * * *", wednesday, time.Date(2024, 6, 6, 10, 30, 0, 0, time.UTC)},
+		{"Named weekday", "0 22 * * SAT", wednesday, time.Date(2024, 6, 8, 22, 0, 0, 0, time.UTC)},
+		{"Sunday as 7", "0 1 * * 7", wednesday, time.Date(2024, 6, 9, 1, 0, 0, 0, time.UTC)},
+		{"Weekday range", "0 8 * * MON-FRI", time.Date(2024, 6, 7, 9, 0, 0, 0, time.UTC), time.Date(2024, 6, 10, 8, 0, 0, 0, time.UTC)},
+		{"Step", "*/20 * * * *", wednesday, time.Date(2024, 6, 5, 10, 40, 0, 0, time.UTC)},
+		{"Day of month", "0 0 1 * *", wednesday, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"Day of month or weekday", "0 0 1 * SUN", wednesday, time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC)},
+		{"Named month", "0 0 1 JAN *", wednesday, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"RRULE weekly", "FREQ=WEEKLY;BYDAY=SA,SU;BYHOUR=22", wednesday, time.Date(2024, 6, 8, 22, 0, 0, 0, time.UTC)},
+		{"RRULE daily", "RRULE:FREQ=DAILY;BYHOUR=2;BYMINUTE=30", wednesday, time.Date(2024, 6, 6, 2, 30, 0, 0, time.UTC)},
+		{"RRULE monthly day and weekday", "FREQ=MONTHLY;BYMONTHDAY=8,9,10,11,12,13,14;BYDAY=TU", wednesday, time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)},
+		{"Time zone", "TZ=America/New_York 0 22 * * *", wednesday, time.Date(2024, 6, 6, 2, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			s, err := Parse(c.spec)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.Next(c.from)).To(BeTemporally("==", c.expected))
+		})
+	}
+}
+
+func TestSchedule_Next_GivenImpossibleSpec_ReturnsZero(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	s, err := Parse("0 0 30 FEB *")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.Next(wednesday).IsZero()).To(BeTrue())
+}
+
+func TestParse_GivenInvalidSpec_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		spec string
+	}{
+		{"Too few fields", "0 22 * *"},
+		{"Out of range", "0 24 * * *"},
+		{"Bad name", "0 22 * * SATURDAY"},
+		{"Reversed range", "0 22 * * FRI-MON"},
+		{"Bad step", "*/0 * * * *"},
+		{"Unknown time zone", "TZ=Nowhere/Special 0 22 * * *"},
+		{"RRULE without FREQ", "RRULE:BYHOUR=2"},
+		{"RRULE weekly without BYDAY", "FREQ=WEEKLY;BYHOUR=2"},
+		{"RRULE unsupported part", "FREQ=DAILY;COUNT=3"},
+		{"RRULE unsupported frequency", "FREQ=HOURLY"},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := Parse(c.spec)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func TestWindow_Contains(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// Saturday 22:00 for 4 hours
+	w, err := ParseWindow("0 22 * * SAT", "4h")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(w.Contains(wednesday)).To(BeFalse())
+	g.Expect(w.Contains(time.Date(2024, 6, 8, 21, 59, 0, 0, time.UTC))).To(BeFalse())
+	g.Expect(w.Contains(time.Date(2024, 6, 8, 22, 0, 0, 0, time.UTC))).To(BeTrue())
+	g.Expect(w.Contains(time.Date(2024, 6, 9, 1, 59, 59, 0, time.UTC))).To(BeTrue())
+	g.Expect(w.Contains(time.Date(2024, 6, 9, 2, 0, 0, 0, time.UTC))).To(BeFalse())
+
+	g.Expect(w.NextStart(wednesday)).To(BeTemporally("==", time.Date(2024, 6, 8, 22, 0, 0, 0, time.UTC)))
+}
+
+func TestParseWindow_GivenInvalidDuration_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	_, err := ParseWindow("0 22 * * SAT", "forever")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseWindow("0 22 * * SAT", "-1h")
+	g.Expect(err).To(HaveOccurred())
+}
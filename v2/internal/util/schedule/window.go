@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package schedule
+
+import (
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// Window is a recurring window of time, starting at each instant of a Schedule and lasting for Duration.
+type Window struct {
+	Schedule *Schedule
+	Duration time.Duration
+}
+
+// ParseWindow parses a window from the provided schedule (see Parse) and duration (see time.ParseDuration).
+func ParseWindow(schedule string, duration string) (Window, error) {
+	s, err := Parse(schedule)
+	if err != nil {
+		return Window{}, err
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return Window{}, eris.Wrapf(err, "parsing duration %q", duration)
+	}
+
+	if d <= 0 {
+		return Window{}, eris.Errorf("duration %q must be positive", duration)
+	}
+
+	return Window{
+		Schedule: s,
+		Duration: d,
+	}, nil
+}
+
+// Contains returns true if t falls within an occurrence of the window.
+func (w Window) Contains(t time.Time) bool {
+	// The most recent start that could still be open is the first one after t - Duration
+	start := w.Schedule.Next(t.Add(-w.Duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// NextStart returns the start of the next occurrence of the window after t, or the zero time if there is none.
+func (w Window) NextStart(t time.Time) time.Time {
+	return w.Schedule.Next(t)
+}
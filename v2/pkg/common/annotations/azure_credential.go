@@ -0,0 +1,10 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// AzureCredential names the AzureCredential used to manage a resource, either as "name" for a credential in the same
+// namespace as the resource, or "namespace/name" for a credential in another namespace which allows its use.
+// It can be set on a resource, or on a namespace to apply to all resources in that namespace that don't specify their
+// own.
+const AzureCredential = "serviceoperator.azure.com/azure-credential"
@@ -0,0 +1,26 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// CreationMode describes how the operator behaves when asked to create a resource that already exists in Azure.
+// If no creation mode is specified, the default is "adopt-or-create".
+const CreationMode = "serviceoperator.azure.com/creation-mode"
+
+type CreationModeValue string
+
+const (
+	// CreationModeAdoptOrCreate instructs the operator to adopt the resource in Azure if it already exists, and to
+	// create it otherwise. As PUT is idempotent, an existing resource is updated to match the desired state.
+	// This is the default mode when no mode is specified.
+	CreationModeAdoptOrCreate = CreationModeValue("adopt-or-create")
+
+	// CreationModeCreateOnly instructs the operator to refuse to take over a resource that already exists in Azure
+	// but was not created by the operator for this resource.
+	CreationModeCreateOnly = CreationModeValue("create-only")
+)
+
+// AllowsAdoption determines if the mode allows the operator to adopt an existing Azure resource
+func (m CreationModeValue) AllowsAdoption() bool {
+	return m != CreationModeCreateOnly
+}
@@ -0,0 +1,14 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// DeletionProtection protects the resource in Azure from deletion when the resource is deleted in Kubernetes.
+// While set to "true", deletion is blocked (and the Kubernetes resource remains, pending finalization) until it is
+// either confirmed with ConfirmDeletion or protection is removed. As deleting an owner of the resource (such as its
+// resource group) in Azure would delete the resource along with it, deletion of its owners is blocked too.
+const DeletionProtection = "serviceoperator.azure.com/deletion-protection"
+
+// ConfirmDeletion confirms that a resource protected by DeletionProtection should be deleted from Azure.
+// The value must be the name of the resource being deleted, to guard against it being applied indiscriminately.
+const ConfirmDeletion = "serviceoperator.azure.com/confirm-deletion"
@@ -0,0 +1,9 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// LastModifiedBy records the Kubernetes user who last created or changed the spec of the resource. It is maintained
+// by the operator's webhooks (any value set by users is ignored), and is included in audit records of the changes
+// the operator makes in Azure.
+const LastModifiedBy = "serviceoperator.azure.com/last-modified-by"
@@ -0,0 +1,14 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// MaintenanceWindow restricts when the operator may modify or delete the resource in Azure to recurring windows
+// of time. The value is either a five field cron expression or an RRULE giving the start of each window, optionally
+// prefixed with a time zone (e.g. "TZ=Europe/London 0 22 * * SAT"). It may be set on a resource, or on a namespace
+// to apply to all resources in that namespace that don't specify their own.
+const MaintenanceWindow = "serviceoperator.azure.com/maintenance-window"
+
+// MaintenanceWindowDuration is the duration of each maintenance window, e.g. "4h". It is required alongside
+// MaintenanceWindow, and is read from the same object.
+const MaintenanceWindowDuration = "serviceoperator.azure.com/maintenance-window-duration"
@@ -0,0 +1,10 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// MergePaths lists the JSON paths of the ARM payload where properties set outside the operator (for example by Azure
+// Policy) are preserved rather than overwritten, as a comma separated list such as "tags,properties.networkAcls".
+// It can be set on a resource, or on a namespace to apply to all resources in that namespace that don't specify their
+// own. These paths are added to any configured for the resource type by the operator.
+const MergePaths = "serviceoperator.azure.com/merge-paths"
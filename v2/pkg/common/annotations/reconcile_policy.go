@@ -6,7 +6,7 @@ package annotations
 // ReconcilePolicy describes the reconcile policy for the resource in question.
 // A reconcile policy describes what action (if any) the operator is allowed to take when
 // reconciling the resource.
-// If no reconcile policy is specified, the default is "run"
+// If no reconcile policy is specified, the default is "manage"
 const ReconcilePolicy = "serviceoperator.azure.com/reconcile-policy"
 
 type ReconcilePolicyValue string
@@ -24,6 +24,19 @@ const (
 	// ReconcilePolicyDetachOnDelete instructs the operator to skip deletion of resources in Azure. This allows
 	// deletion of the resource in Kubernetes to go through but does not delete the underlying Azure resource.
 	ReconcilePolicyDetachOnDelete = ReconcilePolicyValue("detach-on-delete")
+
+	// ReconcilePolicyObserve instructs the operator to track the resource in Azure without ever modifying it.
+	// As with ReconcilePolicySkip, the resource is periodically read from Azure to update its status, Ready condition
+	// and any exported ConfigMaps or Secrets, but PUTs and DELETEs are never issued. In addition, differences between
+	// the resource in Azure and its spec are reported in the DriftDetected condition. This is intended for resources
+	// owned by someone else (e.g. a platform team) that need to be referenced and read from, but must not be changed.
+	ReconcilePolicyObserve = ReconcilePolicyValue("observe")
+
+	// ReconcilePolicyPlan instructs the operator to preview the changes it would make to the resource in Azure,
+	// without making them. The payload that would be PUT is compared with the resource in Azure and the planned
+	// change (create, modify or no-op) is recorded in the ChangesPlanned condition and as an event. Like
+	// ReconcilePolicyObserve, PUTs and DELETEs are never issued.
+	ReconcilePolicyPlan = ReconcilePolicyValue("plan")
 )
 
 // AllowsDelete determines if the policy allows deletion of the backing Azure resource
@@ -35,3 +48,8 @@ func (r ReconcilePolicyValue) AllowsDelete() bool {
 func (r ReconcilePolicyValue) AllowsModify() bool {
 	return r == ReconcilePolicyManage || r == ReconcilePolicyDetachOnDelete
 }
+
+// IsReadOnly determines if the policy restricts the operator to reading the backing Azure resource
+func (r ReconcilePolicyValue) IsReadOnly() bool {
+	return r == ReconcilePolicySkip || r == ReconcilePolicyObserve || r == ReconcilePolicyPlan
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// SyncPeriod overrides the frequency at which the resource is re-reconciled with Azure (AZURE_SYNC_PERIOD), as a
+// duration such as "5m" or "24h", or "never" to disable periodic re-reconciliation. It may be set on a resource, or on
+// a namespace to apply to all resources in that namespace that don't specify their own.
+const SyncPeriod = "serviceoperator.azure.com/sync-period"
@@ -0,0 +1,9 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// Tags specifies tags to apply to every resource in a namespace, as a semicolon separated list of <key>=<value>
+// entries, for example "cost-center=1234;owner=team-a". It's only honoured on namespaces, and is merged with the tags
+// configured by the operator's tag policy, taking precedence over them.
+const Tags = "serviceoperator.azure.com/tags"
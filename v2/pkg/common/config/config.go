@@ -101,4 +101,66 @@ const (
 	// DefaultReconcilePolicy allows to change default reconciliation policy to use when serviceoperator.azure.com/reconcile-policy annotation
 	// is not explicitly defined. If omitted, it will be automatically set to "manage"
 	DefaultReconcilePolicy = "DEFAULT_RECONCILE_POLICY"
+	// EnableDriftDetection instructs the operator to compare the desired state of each resource with the live resource
+	// in Azure before issuing a PUT. If they match, the PUT is skipped. Any differences are reported in the
+	// DriftDetected condition. If omitted, drift detection is disabled.
+	EnableDriftDetection = "ENABLE_DRIFT_DETECTION"
+	// ResourceTypeLimits overrides MaxConcurrentReconciles and adds a token-bucket rate limit for particular resource
+	// types. It is a semicolon separated list of entries of the form <group>[/<kind>]:<key>=<value>[,<key>=<value>...],
+	// where the keys are maxConcurrentReconciles, qps and bucketSize. An entry without a kind applies to every kind in
+	// the group, and an entry for a kind takes precedence over an entry for its group.
+	// For example: "containerservice.azure.com:maxConcurrentReconciles=1;network.azure.com/PrivateDnsZonesARecord:maxConcurrentReconciles=10,qps=20"
+	ResourceTypeLimits = "RESOURCE_TYPE_LIMITS"
+	// EnablePriorityQueue instructs the operator to prioritise reconciles of new and changed resources over deletes,
+	// deletes over polling of long-running operations, and polling over periodic resyncs. If omitted, all reconciles
+	// share a single first-in first-out queue.
+	EnablePriorityQueue = "ENABLE_PRIORITY_QUEUE"
+	// StatusRefreshMode configures how the status of resources is refreshed from Azure.
+	// Valid values are [get, resourcegraph]
+	// * get: Each resource is refreshed with a GET of the resource. This is the default.
+	// * resourcegraph: Resources are refreshed in batches, using Azure Resource Graph queries grouped by
+	//   subscription and resource type. Resources not yet in the batch results (or whose results are out of date)
+	//   fall back to a GET.
+	StatusRefreshMode = "STATUS_REFRESH_MODE"
+	// StatusRefreshInterval is how often batched status refreshes are performed. This value only has an effect if
+	// StatusRefreshMode is 'resourcegraph'. If omitted, the default is 5m.
+	StatusRefreshInterval = "STATUS_REFRESH_INTERVAL"
+	// ChangeEventsBindAddress is the address (for example ":8083") on which the operator listens for Azure resource
+	// change events, delivered as CloudEvents by Event Grid. Resources named in the events are reconciled immediately.
+	// If omitted, change events are not received.
+	ChangeEventsBindAddress = "CHANGE_EVENTS_BIND_ADDRESS"
+	// ChangeEventsKey must be supplied as the "key" query parameter of each request delivering change events. Required if
+	// ChangeEventsBindAddress is set.
+	ChangeEventsKey = "CHANGE_EVENTS_KEY"
+	// AuditLogSink configures where audit records of requests that modify resources in Azure are written.
+	// Valid values are [none, stdout, file, webhook]
+	// * none: No audit records are written. This is the default.
+	// * stdout: Records are written to stdout as lines of JSON.
+	// * file: Records are written to AuditLogFile as lines of JSON, rotating the file as it fills.
+	// * webhook: Each record is POSTed as JSON to AuditLogWebhookURL.
+	AuditLogSink = "AUDIT_LOG_SINK"
+	// AuditLogFile is the path of the file audit records are written to. Required if AuditLogSink is 'file'.
+	AuditLogFile = "AUDIT_LOG_FILE"
+	// AuditLogFileMaxSize is the size (in megabytes) at which AuditLogFile is rotated. If omitted, the default is 100.
+	AuditLogFileMaxSize = "AUDIT_LOG_FILE_MAX_SIZE"
+	// AuditLogFileMaxBackups is the number of rotated audit log files to keep. If omitted, the default is 5.
+	AuditLogFileMaxBackups = "AUDIT_LOG_FILE_MAX_BACKUPS"
+	// AuditLogWebhookURL is the URL audit records are POSTed to. Required if AuditLogSink is 'webhook'.
+	AuditLogWebhookURL = "AUDIT_LOG_WEBHOOK_URL"
+	// OperationHistoryLength is the number of recent ARM operations recorded in the OperationHistory of each resource.
+	// Set to 0 to disable recording. If omitted, the default is 10.
+	OperationHistoryLength = "OPERATION_HISTORY_LENGTH"
+	// TagPolicyTags is a semicolon separated list of <key>=<value> tags applied to every resource, for example
+	// "managed-by=aso;environment=prod".
+	TagPolicyTags = "TAG_POLICY_TAGS"
+	// TagPolicyNamespaceLabels is a semicolon separated list of <tag>=<label> entries. Each resource is tagged with
+	// the value of the named label from its namespace, for example "cost-center=finops.contoso.com/cost-center".
+	TagPolicyNamespaceLabels = "TAG_POLICY_NAMESPACE_LABELS"
+	// TagPolicyCollision determines which value is used when a tag is set by both the tag policy and the spec of
+	// a resource. Valid values are 'spec-wins' (the default) and 'policy-wins'.
+	TagPolicyCollision = "TAG_POLICY_COLLISION"
+	// MergePaths is a semicolon separated list of entries of the form <group>[/<kind>]:<path>[,<path>...], giving
+	// the JSON paths of the ARM payload where properties set outside the operator are preserved for particular
+	// resource types, for example "storage.azure.com/StorageAccount:tags,properties.networkAcls".
+	MergePaths = "MERGE_PATHS"
 )
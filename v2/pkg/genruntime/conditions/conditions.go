@@ -222,10 +222,11 @@ func SetCondition(o Conditioner, new Condition) {
 // v2/tools/generator/internal/readonly/readonly_map.go but given
 // Golang generics bugs for now we go with the simpler approach
 var reasonPriority = map[string]int{
-	ReasonReferenceNotFound.Name: -2,
-	ReasonSecretNotFound.Name:    -2,
-	ReasonConfigMapNotFound.Name: -2,
-	// AzureResourceNotFound only comes up when ReconcilePolicy is skip. This conditions priority being less than
+	ReasonReferenceNotFound.Name:   -2,
+	ReasonReferenceNotGranted.Name: -2,
+	ReasonSecretNotFound.Name:      -2,
+	ReasonConfigMapNotFound.Name:   -2,
+	// AzureResourceNotFound only comes up when ReconcilePolicy is skip or observe. This conditions priority being less than
 	// Reconciling allows skip -> reconcile to immediately update the condition to Reconciling rather than continuing to
 	// report AzureResourceNotFound until the resource is created.
 	ReasonAzureResourceNotFound.Name: -2,
@@ -0,0 +1,66 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conditions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConditionTypeDriftDetected is a condition indicating whether the resource in Azure has drifted from the state
+// most recently applied by the operator. Unlike Ready, this condition has negative polarity: Status == False is
+// the normal/healthy state.
+const ConditionTypeDriftDetected = "DriftDetected"
+
+const (
+	ReasonDriftDetected = "DriftDetected"
+	ReasonNoDrift       = "NoDrift"
+)
+
+// maxMessagePaths is the maximum number of differing paths included in a condition message, to avoid
+// bloating the resource with an unbounded list
+const maxMessagePaths = 10
+
+func NewDriftConditionBuilder(builder PositiveConditionBuilderInterface) *DriftConditionBuilder {
+	return &DriftConditionBuilder{
+		builder: builder,
+	}
+}
+
+type DriftConditionBuilder struct {
+	builder PositiveConditionBuilderInterface
+}
+
+// DriftDetected returns a condition indicating the resource in Azure differs from the desired state at the given paths.
+func (b *DriftConditionBuilder) DriftDetected(observedGeneration int64, paths []string) Condition {
+	result := b.builder.MakeTrueCondition(ConditionTypeDriftDetected, observedGeneration)
+	result.Severity = ConditionSeverityWarning
+	result.Reason = ReasonDriftDetected
+	result.Message = fmt.Sprintf("Azure resource differs from the desired state at: %s", formatPaths(paths))
+
+	return result
+}
+
+// NoDrift returns a condition indicating the resource in Azure matches the desired state.
+func (b *DriftConditionBuilder) NoDrift(observedGeneration int64) Condition {
+	return b.builder.MakeFalseCondition(
+		ConditionTypeDriftDetected,
+		ConditionSeverityNone,
+		observedGeneration,
+		ReasonNoDrift,
+		"Azure resource matches the desired state")
+}
+
+func formatPaths(paths []string) string {
+	if len(paths) <= maxMessagePaths {
+		return strings.Join(paths, ", ")
+	}
+
+	return fmt.Sprintf(
+		"%s (and %d more)",
+		strings.Join(paths[:maxMessagePaths], ", "),
+		len(paths)-maxMessagePaths)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conditions_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func Test_DriftConditionBuilder_DriftDetected(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Drift.DriftDetected(3, []string{"properties.minimumTlsVersion", "tags.owner"})
+
+	g.Expect(condition.Type).To(Equal(conditions.ConditionType("DriftDetected")))
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.Severity).To(Equal(conditions.ConditionSeverityWarning))
+	g.Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonDriftDetected))
+	g.Expect(condition.Message).To(ContainSubstring("properties.minimumTlsVersion, tags.owner"))
+}
+
+func Test_DriftConditionBuilder_DriftDetected_TruncatesLongPathLists(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	var paths []string
+	for i := 0; i < 15; i++ {
+		paths = append(paths, fmt.Sprintf("tags.tag%d", i))
+	}
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Drift.DriftDetected(1, paths)
+
+	g.Expect(condition.Message).To(ContainSubstring("tags.tag9"))
+	g.Expect(condition.Message).ToNot(ContainSubstring("tags.tag10"))
+	g.Expect(condition.Message).To(ContainSubstring("(and 5 more)"))
+}
+
+func Test_DriftConditionBuilder_NoDrift(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Drift.NoDrift(2)
+
+	g.Expect(condition.Type).To(Equal(conditions.ConditionType("DriftDetected")))
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Severity).To(Equal(conditions.ConditionSeverityNone))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonNoDrift))
+}
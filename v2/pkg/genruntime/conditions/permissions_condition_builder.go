@@ -0,0 +1,52 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conditions
+
+import "fmt"
+
+// ConditionTypePermissionsVerified is a condition indicating whether a credential has been verified to have the
+// access needed by the resources that use it.
+const ConditionTypePermissionsVerified = "PermissionsVerified"
+
+const (
+	ReasonMissingPermissions    = "MissingPermissions"
+	ReasonPermissionCheckFailed = "PermissionCheckFailed"
+)
+
+func NewPermissionsConditionBuilder(builder PositiveConditionBuilderInterface) *PermissionsConditionBuilder {
+	return &PermissionsConditionBuilder{
+		builder: builder,
+	}
+}
+
+type PermissionsConditionBuilder struct {
+	builder PositiveConditionBuilderInterface
+}
+
+// Verified returns a condition indicating the credential has all the access needed.
+func (b *PermissionsConditionBuilder) Verified(observedGeneration int64) Condition {
+	return b.builder.MakeTrueCondition(ConditionTypePermissionsVerified, observedGeneration)
+}
+
+// MissingPermissions returns a condition indicating the credential is missing the given permissions, each
+// describing an action and the scope at which it is needed.
+func (b *PermissionsConditionBuilder) MissingPermissions(observedGeneration int64, missing []string) Condition {
+	return b.builder.MakeFalseCondition(
+		ConditionTypePermissionsVerified,
+		ConditionSeverityWarning,
+		observedGeneration,
+		ReasonMissingPermissions,
+		fmt.Sprintf("Credential is missing permissions: %s", formatPaths(missing)))
+}
+
+// CheckFailed returns a condition indicating the permissions of the credential couldn't be checked.
+func (b *PermissionsConditionBuilder) CheckFailed(observedGeneration int64, err error) Condition {
+	return b.builder.MakeUnknownCondition(
+		ConditionTypePermissionsVerified,
+		observedGeneration,
+		ReasonPermissionCheckFailed,
+		err.Error())
+}
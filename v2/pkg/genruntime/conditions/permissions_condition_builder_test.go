@@ -0,0 +1,59 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conditions_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/rotisserie/eris"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func Test_PermissionsConditionBuilder_Verified(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Permissions.Verified(2)
+
+	g.Expect(condition.Type).To(Equal(conditions.ConditionType("PermissionsVerified")))
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+}
+
+func Test_PermissionsConditionBuilder_MissingPermissions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Permissions.MissingPermissions(
+		3,
+		[]string{"Microsoft.Network/virtualNetworks/subnets/join/action at /subscriptions/00000000-0000-0000-0000-000000000000"})
+
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Severity).To(Equal(conditions.ConditionSeverityWarning))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonMissingPermissions))
+	g.Expect(condition.Message).To(ContainSubstring("Microsoft.Network/virtualNetworks/subnets/join/action"))
+}
+
+func Test_PermissionsConditionBuilder_CheckFailed(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Permissions.CheckFailed(1, eris.New("boom"))
+
+	g.Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonPermissionCheckFailed))
+	g.Expect(condition.Message).To(Equal("boom"))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conditions
+
+import "fmt"
+
+// ConditionTypeChangesPlanned is a condition describing the changes the operator would make to the resource in Azure
+// if it were allowed to, as determined when the reconcile-policy is plan. Status == True indicates that changes
+// are pending; Status == False indicates the resource in Azure already matches the desired state.
+const ConditionTypeChangesPlanned = "ChangesPlanned"
+
+const (
+	ReasonPlannedCreate = "PlannedCreate"
+	ReasonPlannedModify = "PlannedModify"
+	ReasonPlannedNoOp   = "PlannedNoOp"
+)
+
+func NewPlanConditionBuilder(builder PositiveConditionBuilderInterface) *PlanConditionBuilder {
+	return &PlanConditionBuilder{
+		builder: builder,
+	}
+}
+
+type PlanConditionBuilder struct {
+	builder PositiveConditionBuilderInterface
+}
+
+// Create returns a condition indicating the resource does not exist in Azure and would be created.
+func (b *PlanConditionBuilder) Create(observedGeneration int64) Condition {
+	result := b.builder.MakeTrueCondition(ConditionTypeChangesPlanned, observedGeneration)
+	result.Severity = ConditionSeverityInfo
+	result.Reason = ReasonPlannedCreate
+	result.Message = "Resource does not exist in Azure and would be created"
+
+	return result
+}
+
+// Modify returns a condition indicating the resource in Azure would be modified. Each change describes a single
+// property that would be changed, without its value (which may be sensitive).
+func (b *PlanConditionBuilder) Modify(observedGeneration int64, changes []string) Condition {
+	result := b.builder.MakeTrueCondition(ConditionTypeChangesPlanned, observedGeneration)
+	result.Severity = ConditionSeverityInfo
+	result.Reason = ReasonPlannedModify
+	result.Message = fmt.Sprintf("Resource in Azure would be modified: %s", formatPaths(changes))
+
+	return result
+}
+
+// NoOp returns a condition indicating the resource in Azure already matches the desired state.
+func (b *PlanConditionBuilder) NoOp(observedGeneration int64) Condition {
+	return b.builder.MakeFalseCondition(
+		ConditionTypeChangesPlanned,
+		ConditionSeverityNone,
+		observedGeneration,
+		ReasonPlannedNoOp,
+		"Resource in Azure matches the desired state, no changes would be made")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conditions_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func Test_PlanConditionBuilder_Create(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Plan.Create(1)
+
+	g.Expect(condition.Type).To(Equal(conditions.ConditionType("ChangesPlanned")))
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.Severity).To(Equal(conditions.ConditionSeverityInfo))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonPlannedCreate))
+}
+
+func Test_PlanConditionBuilder_Modify(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Plan.Modify(4, []string{"properties.minimumTlsVersion (Modify)", "tags.env (Add)"})
+
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.ObservedGeneration).To(Equal(int64(4)))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonPlannedModify))
+	g.Expect(condition.Message).To(ContainSubstring("properties.minimumTlsVersion (Modify), tags.env (Add)"))
+}
+
+func Test_PlanConditionBuilder_NoOp(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Plan.NoOp(2)
+
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Severity).To(Equal(conditions.ConditionSeverityNone))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonPlannedNoOp))
+}
+
+func Test_ReadyConditionBuilder_ChangesPending(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	clk := newMockClock()
+
+	builder := conditions.NewPositiveConditionBuilder(clk)
+	condition := builder.Ready.ChangesPending(1)
+
+	g.Expect(condition.Type).To(Equal(conditions.ConditionType(conditions.ConditionTypeReady)))
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Severity).To(Equal(conditions.ConditionSeverityInfo))
+	g.Expect(condition.Reason).To(Equal(conditions.ReasonChangesPending.Name))
+}
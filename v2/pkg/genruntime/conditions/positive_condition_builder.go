@@ -29,7 +29,10 @@ var _ PositiveConditionBuilderInterface = &PositiveConditionBuilder{}
 type PositiveConditionBuilder struct {
 	clock clock.Clock
 
-	Ready *ReadyConditionBuilder
+	Ready       *ReadyConditionBuilder
+	Drift       *DriftConditionBuilder
+	Plan        *PlanConditionBuilder
+	Permissions *PermissionsConditionBuilder
 }
 
 // NewPositiveConditionBuilder creates a new PositiveConditionBuilder for creating positive polarity conditions.
@@ -39,6 +42,9 @@ func NewPositiveConditionBuilder(clock clock.Clock) *PositiveConditionBuilder {
 	}
 
 	result.Ready = NewReadyConditionBuilder(result)
+	result.Drift = NewDriftConditionBuilder(result)
+	result.Plan = NewPlanConditionBuilder(result)
+	result.Permissions = NewPermissionsConditionBuilder(result)
 	return result
 }
 
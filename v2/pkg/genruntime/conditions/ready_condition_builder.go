@@ -19,10 +19,12 @@ var ReasonSubscriptionMismatch = Reason{Name: "SubscriptionMismatch", RetryClass
 
 // Precondition reasons
 var (
-	ReasonSecretNotFound    = Reason{Name: "SecretNotFound", RetryClassification: retry.Fast}
-	ReasonConfigMapNotFound = Reason{Name: "ConfigMapNotFound", RetryClassification: retry.Fast}
-	ReasonReferenceNotFound = Reason{Name: "ReferenceNotFound", RetryClassification: retry.Fast}
-	ReasonWaitingForOwner   = Reason{Name: "WaitingForOwner", RetryClassification: retry.Fast}
+	ReasonSecretNotFound             = Reason{Name: "SecretNotFound", RetryClassification: retry.Fast}
+	ReasonConfigMapNotFound          = Reason{Name: "ConfigMapNotFound", RetryClassification: retry.Fast}
+	ReasonReferenceNotFound          = Reason{Name: "ReferenceNotFound", RetryClassification: retry.Fast}
+	ReasonReferenceNotGranted        = Reason{Name: "ReferenceNotGranted", RetryClassification: retry.Slow}
+	ReasonWaitingForOwner            = Reason{Name: "WaitingForOwner", RetryClassification: retry.Fast}
+	ReasonAzureResourceAlreadyExists = Reason{Name: "AzureResourceAlreadyExists", RetryClassification: retry.None}
 )
 
 // Post-ARM PUT reasons
@@ -39,6 +41,8 @@ var (
 	ReasonReconcileBlocked                = Reason{Name: "ReconciliationBlocked", RetryClassification: retry.Slow}
 	ReasonReconcilePostponed              = Reason{Name: "ReconciliationPostponed", RetryClassification: retry.Slow}
 	ReasonPostReconcileFailure            = Reason{Name: "PostReconciliationFailure", RetryClassification: retry.Slow}
+	ReasonDeletionProtected               = Reason{Name: "DeletionProtected", RetryClassification: retry.Slow}
+	ReasonChangesPending                  = Reason{Name: "ChangesPending", RetryClassification: retry.Slow}
 )
 
 // ReasonFailed is a catch-all error code for when we don't have a more specific error classification
@@ -89,6 +93,17 @@ func (b *ReadyConditionBuilder) Deleting(observedGeneration int64) Condition {
 		"The resource is being deleted")
 }
 
+// ChangesPending returns a condition indicating the resource in Azure doesn't match the desired state, but changes
+// aren't being made because the reconcile-policy is plan.
+func (b *ReadyConditionBuilder) ChangesPending(observedGeneration int64) Condition {
+	return b.builder.MakeFalseCondition(
+		ConditionTypeReady,
+		ConditionSeverityInfo,
+		observedGeneration,
+		ReasonChangesPending.Name,
+		"The resource in Azure doesn't match the desired state, and changes are only being planned")
+}
+
 func (b *ReadyConditionBuilder) Succeeded(observedGeneration int64) Condition {
 	return b.builder.MakeTrueCondition(ConditionTypeReady, observedGeneration)
 }
@@ -11,20 +11,23 @@ import (
 	"github.com/rotisserie/eris"
 )
 
-// ConfigMapReference is a reference to a Kubernetes configmap and key in the same namespace as
+// ConfigMapReference is a reference to a Kubernetes configmap and key, by default in the same namespace as
 // the resource it is on.
 // +kubebuilder:object:generate=true
 //
 //nolint:recvcheck
 type ConfigMapReference struct {
 	// Name is the name of the Kubernetes configmap being referenced.
-	// The configmap must be in the same namespace as the resource
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty"`
 
 	// Key is the key in the Kubernetes configmap being referenced
 	// +kubebuilder:validation:Required
 	Key string `json:"key,omitempty"`
+
+	// Namespace is the namespace of the Kubernetes configmap being referenced. If omitted, the namespace of the
+	// resource is used. Configmaps in other namespaces must be allowed by a ReferenceGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 var _ Indexer = ConfigMapReference{}
@@ -39,10 +42,25 @@ func (c ConfigMapReference) Copy() ConfigMapReference {
 }
 
 func (c ConfigMapReference) String() string {
+	if c.Namespace != "" {
+		return fmt.Sprintf("Namespace: %q, Name: %q, Key: %q", c.Namespace, c.Name, c.Key)
+	}
+
 	return fmt.Sprintf("Name: %q, Key: %q", c.Name, c.Key)
 }
 
-// AsNamespacedRef creates a NamespacedSecretReference from this SecretReference in the given namespace
+// TargetNamespace returns the namespace of the configmap referred to, given the namespace of the resource with the
+// reference.
+func (c ConfigMapReference) TargetNamespace(namespace string) string {
+	if c.Namespace != "" {
+		return c.Namespace
+	}
+
+	return namespace
+}
+
+// AsNamespacedRef creates a NamespacedSecretReference from this SecretReference in the given namespace.
+// The namespace of the reference itself is ignored; see TargetNamespace.
 func (c ConfigMapReference) AsNamespacedRef(namespace string) NamespacedConfigMapReference {
 	return NamespacedConfigMapReference{
 		ConfigMapReference: c,
@@ -9,14 +9,16 @@ import (
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/retry"
 )
 
+// CloudErrorDetails is the classification of an error returned by ARM.
+// +kubebuilder:object:generate=true
 type CloudErrorDetails struct {
 	// Classification specifies if the error is fatal or transient
-	Classification ErrorClassification
+	Classification ErrorClassification `json:"classification,omitempty"`
 	// Retry defines the speed at which the error should be retried. If this is not set,
 	// the default is retry.Slow.
-	Retry retry.Classification
+	Retry retry.Classification `json:"retry,omitempty"`
 	// Code is the error code
-	Code string
+	Code string `json:"code,omitempty"`
 	// Message is the error message
-	Message string
+	Message string `json:"message,omitempty"`
 }
@@ -197,6 +197,41 @@ func (e *ConfigMapNotFound) Format(s fmt.State, verb rune) {
 	format(e, s, verb)
 }
 
+// ReferenceNotGranted error is used when a resource refers to something in another namespace, and no ReferenceGrant
+// in that namespace allows it
+type ReferenceNotGranted struct {
+	FromNamespace  string
+	GroupKind      schema.GroupKind
+	NamespacedName types.NamespacedName
+}
+
+func NewReferenceNotGrantedError(fromNamespace string, groupKind schema.GroupKind, name types.NamespacedName) *ReferenceNotGranted {
+	return &ReferenceNotGranted{
+		FromNamespace:  fromNamespace,
+		GroupKind:      groupKind,
+		NamespacedName: name,
+	}
+}
+
+var _ error = &ReferenceNotGranted{}
+
+func (e *ReferenceNotGranted) Error() string {
+	return fmt.Sprintf(
+		"reference to %s %s from namespace %q is not allowed by any ReferenceGrant in namespace %q",
+		e.GroupKind,
+		e.NamespacedName,
+		e.FromNamespace,
+		e.NamespacedName.Namespace)
+}
+
+func (e *ReferenceNotGranted) Is(err error) bool {
+	var typedErr *ReferenceNotGranted
+	if eris.As(err, &typedErr) {
+		return *e == *typedErr
+	}
+	return false
+}
+
 // SubscriptionMismatch error is used when a child resource and parent resource subscription don't match
 type SubscriptionMismatch struct {
 	ExpectedSubscription string
@@ -9,6 +9,21 @@ Licensed under the MIT license.
 
 package core
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudErrorDetails) DeepCopyInto(out *CloudErrorDetails) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudErrorDetails.
+func (in *CloudErrorDetails) DeepCopy() *CloudErrorDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudErrorDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DestinationExpression) DeepCopyInto(out *DestinationExpression) {
 	*out = *in
@@ -62,6 +62,13 @@ type KubernetesResource interface {
 	SetStatus(status ConvertibleStatus) error
 }
 
+// ReadOnlyResource is implemented by resources which the operator only ever reads from Azure, such as ResourceLookup.
+// They're always reconciled as if they had the observe reconcile policy, whatever their annotations say.
+type ReadOnlyResource interface {
+	// IsReadOnly returns true if the resource must never be created, modified or deleted in Azure
+	IsReadOnly() bool
+}
+
 // NewEmptyVersionedResource returns a new blank resource based on the passed metaObject; the original API version used
 // (if available) from when the resource was first created is used to identify the version to return.
 // Returns an empty resource.
@@ -97,6 +104,12 @@ func GetAPIVersion(metaObject ARMMetaObject, scheme *runtime.Scheme) (string, er
 		return "", eris.Wrapf(err, "unable return API version for %s", metaObject.GetObjectKind().GroupVersionKind())
 	}
 
+	if rsrc.GetObjectKind().GroupVersionKind() == metaObject.GetObjectKind().GroupVersionKind() {
+		// No conversion needed; ask the resource itself, as its API version may be specified per instance
+		// (as for ARMResource) rather than being fixed by its type
+		return metaObject.GetAPIVersion(), nil
+	}
+
 	return rsrc.GetAPIVersion(), nil
 }
 
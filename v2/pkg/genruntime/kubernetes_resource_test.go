@@ -0,0 +1,104 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genruntime_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	resourcesstorage "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601/storage"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+func newAPIVersionTestScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(resources.AddToScheme(scheme)).To(Succeed())
+	g.Expect(resourcesstorage.AddToScheme(scheme)).To(Succeed())
+	g.Expect(serviceoperator.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func Test_GetAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		obj      func() genruntime.ARMMetaObject
+		expected string
+	}{
+		{
+			name: "Resource with API version fixed by its type",
+			obj: func() genruntime.ARMMetaObject {
+				rg := &resources.ResourceGroup{}
+				rg.SetGroupVersionKind(resources.GroupVersion.WithKind("ResourceGroup"))
+				return rg
+			},
+			expected: "2020-06-01",
+		},
+		{
+			name: "Storage resource uses the API version of its original version",
+			obj: func() genruntime.ARMMetaObject {
+				rg := &resourcesstorage.ResourceGroup{}
+				rg.SetGroupVersionKind(resourcesstorage.GroupVersion.WithKind("ResourceGroup"))
+				rg.Spec.OriginalVersion = resources.GroupVersion.Version
+				return rg
+			},
+			expected: "2020-06-01",
+		},
+		{
+			name: "Resource with API version specified per instance",
+			obj: func() genruntime.ARMMetaObject {
+				res := &serviceoperator.ARMResource{}
+				res.SetGroupVersionKind(serviceoperator.GroupVersion.WithKind("ARMResource"))
+				res.Spec.Type = "Microsoft.Network/virtualNetworks"
+				res.Spec.APIVersion = "2024-05-01"
+				return res
+			},
+			expected: "2024-05-01",
+		},
+		{
+			name: "Instances of the same type can use different API versions",
+			obj: func() genruntime.ARMMetaObject {
+				res := &serviceoperator.ARMResource{}
+				res.SetGroupVersionKind(serviceoperator.GroupVersion.WithKind("ARMResource"))
+				res.Spec.Type = "Microsoft.Network/virtualNetworks"
+				res.Spec.APIVersion = "2023-09-01"
+				return res
+			},
+			expected: "2023-09-01",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			apiVersion, err := genruntime.GetAPIVersion(c.obj(), newAPIVersionTestScheme(g))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(apiVersion).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_GetAPIVersion_GivenUnknownKind_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	res := &serviceoperator.ARMResource{}
+	res.SetGroupVersionKind(serviceoperator.GroupVersion.WithKind("ARMResource"))
+	res.Spec.APIVersion = "2024-05-01"
+
+	// Without the kind registered there's nothing to tell us how the API version is determined
+	_, err := genruntime.GetAPIVersion(res, runtime.NewScheme())
+	g.Expect(err).To(MatchError(ContainSubstring("unable return API version")))
+}
@@ -51,3 +51,27 @@ type Reconciler interface {
 		eventRecorder record.EventRecorder,
 		obj MetaObject) error
 }
+
+// Planner is optionally implemented by a Reconciler that can preview the changes CreateOrUpdate would make, without
+// making them. It is called in place of CreateOrUpdate when the reconcile-policy annotation is set to plan.
+type Planner interface {
+	// Plan determines the changes that CreateOrUpdate would make to the resource and records them on the resource,
+	// but performs no create or update. Implementations should refresh the status of the resource if it exists.
+	Plan(
+		ctx context.Context,
+		log logr.Logger,
+		eventRecorder record.EventRecorder,
+		obj MetaObject) error
+}
+
+// Observer is optionally implemented by a Reconciler that can report how the resource in Azure differs from its spec,
+// without changing it. It is called in place of UpdateStatus when the reconcile-policy annotation is set to observe.
+type Observer interface {
+	// Observe refreshes the status of the resource, as UpdateStatus does, and records any differences between the
+	// resource and its spec on the resource. It performs no create or update.
+	Observe(
+		ctx context.Context,
+		log logr.Logger,
+		eventRecorder record.EventRecorder,
+		obj MetaObject) error
+}
@@ -29,10 +29,9 @@ type KnownResourceReference struct {
 	// This is the name of the Kubernetes resource to reference.
 	Name string `json:"name,omitempty"`
 
-	// References across namespaces are not supported.
-	// Note that ownership across namespaces in Kubernetes is not allowed, but technically resource
-	// references are. There are RBAC considerations here though so probably easier to just start by
-	// disallowing cross-namespace references for now
+	// Namespace is the namespace of the Kubernetes resource to reference. If omitted, the namespace of the resource
+	// with the reference is used. References to other namespaces must be allowed by a ReferenceGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
 
 	// +kubebuilder:validation:Pattern="(?i)(^(/subscriptions/([^/]+)(/resourcegroups/([^/]+))?)?/providers/([^/]+)/([^/]+/[^/]+)(/([^/]+/[^/]+))*$|^/subscriptions/([^/]+)(/resourcegroups/([^/]+))?$)"
 	ARMID string `json:"armId,omitempty"`
@@ -46,10 +45,11 @@ func (ref *KnownResourceReference) AsResourceReference(group string, kind string
 	}
 
 	return &ResourceReference{
-		Group: group,
-		Kind:  kind,
-		Name:  ref.Name,
-		ARMID: ref.ARMID,
+		Group:     group,
+		Kind:      kind,
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+		ARMID:     ref.ARMID,
 	}
 }
 
@@ -84,7 +84,10 @@ type ArbitraryOwnerReference struct {
 	// Kind is the Kubernetes kind of the resource.
 	Kind string `json:"kind,omitempty"`
 
-	// Ownership across namespaces is not supported.
+	// Namespace is the namespace of the Kubernetes resource. If omitted, the namespace of the owned resource is used.
+	// Owners in other namespaces must be allowed by a ReferenceGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
+
 	// +kubebuilder:validation:Pattern="(?i)(^(/subscriptions/([^/]+)(/resourcegroups/([^/]+))?)?/providers/([^/]+)/([^/]+/[^/]+)(/([^/]+/[^/]+))*$|^/subscriptions/([^/]+)(/resourcegroups/([^/]+))?$)"
 	ARMID string `json:"armId,omitempty"`
 }
@@ -92,10 +95,11 @@ type ArbitraryOwnerReference struct {
 // AsResourceReference transforms this ArbitraryOwnerReference into a ResourceReference
 func (ref *ArbitraryOwnerReference) AsResourceReference() *ResourceReference {
 	return &ResourceReference{
-		Group: ref.Group,
-		Kind:  ref.Kind,
-		Name:  ref.Name,
-		ARMID: ref.ARMID,
+		Group:     ref.Group,
+		Kind:      ref.Kind,
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+		ARMID:     ref.ARMID,
 	}
 }
 
@@ -112,6 +116,9 @@ type ResourceReference struct {
 	Kind string `json:"kind,omitempty"`
 	// Name is the Kubernetes name of the resource.
 	Name string `json:"name,omitempty"`
+	// Namespace is the Kubernetes namespace of the resource. If omitted, the namespace of the resource with the
+	// reference is used. References to other namespaces must be allowed by a ReferenceGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
 
 	// Note: Version is not required here because references are all about linking one Kubernetes
 	// resource to another, and Kubernetes resources are uniquely identified by group, kind, (optionally namespace) and
@@ -134,7 +141,7 @@ func CreateResourceReferenceFromARMID(armID string) ResourceReference {
 
 // IsDirectARMReference returns true if this ResourceReference is referring to an ARMID directly.
 func (ref *ResourceReference) IsDirectARMReference() bool {
-	return ref.ARMID != "" && ref.Name == "" && ref.Group == "" && ref.Kind == ""
+	return ref.ARMID != "" && ref.Name == "" && ref.Namespace == "" && ref.Group == "" && ref.Kind == ""
 }
 
 // IsKubernetesReference returns true if this ResourceReference is referring to a Kubernetes resource.
@@ -148,17 +155,21 @@ func (ref ResourceReference) String() string {
 	}
 
 	if ref.IsKubernetesReference() {
+		if ref.Namespace != "" {
+			return fmt.Sprintf("%s/%s, Group/Kind: %s/%s", ref.Namespace, ref.Name, ref.Group, ref.Kind)
+		}
+
 		return fmt.Sprintf("%s, Group/Kind: %s/%s", ref.Name, ref.Group, ref.Kind)
 	}
 
 	// Printing all the fields here just in case something weird happens and we have an ARMID and also Kubernetes reference stuff
-	return fmt.Sprintf("Group: %q, Kind: %q, Name: %q, ARMID: %q", ref.Group, ref.Kind, ref.Name, ref.ARMID)
+	return fmt.Sprintf("Group: %q, Kind: %q, Namespace: %q, Name: %q, ARMID: %q", ref.Group, ref.Kind, ref.Namespace, ref.Name, ref.ARMID)
 }
 
 // TODO: We wouldn't need this if controller-gen supported DUs or OneOf better, see: https://github.com/kubernetes-sigs/controller-tools/issues/461
 // Validate validates the ResourceReference to ensure that it is structurally valid.
 func (ref *ResourceReference) Validate() (admission.Warnings, error) {
-	if ref.ARMID == "" && ref.Name == "" && ref.Group == "" && ref.Kind == "" {
+	if ref.ARMID == "" && ref.Name == "" && ref.Namespace == "" && ref.Group == "" && ref.Kind == "" {
 		return nil, eris.Errorf("at least one of ['ARMID'] or ['Group', 'Kind', 'Namespace', 'Name'] must be set for ResourceReference")
 	}
 
@@ -174,6 +185,7 @@ func (ref *ResourceReference) Validate() (admission.Warnings, error) {
 }
 
 // AsNamespacedRef creates a NamespacedResourceReference from this reference.
+// The namespace of the reference itself is ignored; see TargetNamespace.
 func (ref *ResourceReference) AsNamespacedRef(namespace string) NamespacedResourceReference {
 	// If this is a direct ARM reference, don't append a namespace as it reads weird
 	if ref.IsDirectARMReference() {
@@ -188,6 +200,16 @@ func (ref *ResourceReference) AsNamespacedRef(namespace string) NamespacedResour
 	}
 }
 
+// TargetNamespace returns the namespace of the resource referred to, given the namespace of the resource with the
+// reference.
+func (ref *ResourceReference) TargetNamespace(namespace string) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+
+	return namespace
+}
+
 // AsArbitraryOwnerReference creates an ArbitraryOwnerReference from this reference.
 func (ref *ResourceReference) AsArbitraryOwnerReference() ArbitraryOwnerReference {
 	// If this is a direct ARM reference, return just the ARM  ID
@@ -199,9 +221,10 @@ func (ref *ResourceReference) AsArbitraryOwnerReference() ArbitraryOwnerReferenc
 
 	// Otherwise return GVK
 	return ArbitraryOwnerReference{
-		Group: ref.Group,
-		Kind:  ref.Kind,
-		Name:  ref.Name,
+		Group:     ref.Group,
+		Kind:      ref.Kind,
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
 	}
 }
 
@@ -214,9 +237,10 @@ func (ref *ResourceReference) AsKnownResourceReference() KnownResourceReference
 		}
 	}
 
-	// Otherwise return just the name
+	// Otherwise return just the name and namespace
 	return KnownResourceReference{
-		Name: ref.Name,
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
 	}
 }
 
@@ -9,14 +9,13 @@ import (
 	"fmt"
 )
 
-// SecretReference is a reference to a Kubernetes secret and key in the same namespace as
+// SecretReference is a reference to a Kubernetes secret and key, by default in the same namespace as
 // the resource it is on.
 // +kubebuilder:object:generate=true
 //
 //nolint:recvcheck
 type SecretReference struct {
 	// Name is the name of the Kubernetes secret being referenced.
-	// The secret must be in the same namespace as the resource
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
 
@@ -24,6 +23,10 @@ type SecretReference struct {
 	// +kubebuilder:validation:Required
 	Key string `json:"key"`
 
+	// Namespace is the namespace of the Kubernetes secret being referenced. If omitted, the namespace of the resource
+	// is used. Secrets in other namespaces must be allowed by a ReferenceGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
+
 	// If we end up wanting to support secrets from KeyVault (or elsewhere) we should be able to add a
 	// Type *SecretType
 	// here and default it to Kubernetes if it's not set. See the secrets design for more details.
@@ -41,10 +44,25 @@ func (s SecretReference) Copy() SecretReference {
 }
 
 func (s SecretReference) String() string {
+	if s.Namespace != "" {
+		return fmt.Sprintf("Namespace: %q, Name: %q, Key: %q", s.Namespace, s.Name, s.Key)
+	}
+
 	return fmt.Sprintf("Name: %q, Key: %q", s.Name, s.Key)
 }
 
-// AsNamespacedRef creates a NamespacedSecretReference from this SecretReference in the given namespace
+// TargetNamespace returns the namespace of the secret referred to, given the namespace of the resource with the
+// reference.
+func (s SecretReference) TargetNamespace(namespace string) string {
+	if s.Namespace != "" {
+		return s.Namespace
+	}
+
+	return namespace
+}
+
+// AsNamespacedRef creates a NamespacedSecretReference from this SecretReference in the given namespace.
+// The namespace of the reference itself is ignored; see TargetNamespace.
 func (s SecretReference) AsNamespacedRef(namespace string) NamespacedSecretReference {
 	return NamespacedSecretReference{
 		SecretReference: s,
@@ -62,17 +80,20 @@ func (s NamespacedSecretReference) String() string {
 	return fmt.Sprintf("Namespace: %q, %s", s.Namespace, s.SecretReference)
 }
 
-// SecretMapReference is a reference to a Kubernetes secret in the same namespace as
+// SecretMapReference is a reference to a Kubernetes secret, by default in the same namespace as
 // the resource it is on.
 // +kubebuilder:object:generate=true
 //
 //nolint:recvcheck
 type SecretMapReference struct {
 	// Name is the name of the Kubernetes secret being referenced.
-	// The secret must be in the same namespace as the resource
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
 
+	// Namespace is the namespace of the Kubernetes secret being referenced. If omitted, the namespace of the resource
+	// is used. Secrets in other namespaces must be allowed by a ReferenceGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
+
 	// If we end up wanting to support secrets from KeyVault (or elsewhere) we should be able to add a
 	// Type *SecretType
 	// here and default it to Kubernetes if it's not set. See the secrets design for more details.
@@ -93,10 +114,25 @@ func (s SecretMapReference) Copy() SecretMapReference {
 }
 
 func (s SecretMapReference) String() string {
+	if s.Namespace != "" {
+		return fmt.Sprintf("Namespace: %q, Name: %q", s.Namespace, s.Name)
+	}
+
 	return fmt.Sprintf("Name: %q", s.Name)
 }
 
-// AsNamespacedRef creates a NamespacedSecretReference from this SecretReference in the given namespace
+// TargetNamespace returns the namespace of the secret referred to, given the namespace of the resource with the
+// reference.
+func (s SecretMapReference) TargetNamespace(namespace string) string {
+	if s.Namespace != "" {
+		return s.Namespace
+	}
+
+	return namespace
+}
+
+// AsNamespacedRef creates a NamespacedSecretReference from this SecretReference in the given namespace.
+// The namespace of the reference itself is ignored; see TargetNamespace.
 func (s SecretMapReference) AsNamespacedRef(namespace string) NamespacedSecretMapReference {
 	return NamespacedSecretMapReference{
 		SecretMapReference: s,